@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/config"
+	"bananas/internal/middleware"
+	"bananas/internal/problem"
+	"bananas/internal/ws"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// newChiServer builds the Chi server.
+func newChiServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	r := chi.NewRouter()
+
+	stack := fw.Middleware
+	if stack.Production() {
+		r.Use(chimiddleware.RequestID)
+		// Chi keeps the ID to itself; echo it as the other frameworks do,
+		// which is also where problems find it.
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(middleware.RequestIDHeader, chimiddleware.GetReqID(r.Context()))
+				next.ServeHTTP(w, r)
+			})
+		})
+		r.Use(chimiddleware.RequestLogger(chiAccessLog{}))
+	}
+	if stack.Minimal() {
+		// Chi's Recoverer and Timeout answer with an empty body, and its
+		// RequestSize never answers 413, so the shared middleware, which
+		// answers with a problem, stands in for them.
+		r.Use(middleware.Recover)
+		r.Use(middleware.CORS)
+	}
+	if stack.Production() {
+		compressor := chimiddleware.NewCompressor(middleware.CompressLevel)
+		compressor.SetEncoder("br", middleware.NewBrotliWriter)
+		r.Use(compressor.Handler)
+		r.Use(middleware.SkipLongLived(middleware.Timeout(stack.RequestTimeout)))
+		r.Use(middleware.SkipFileTransfers(middleware.MaxBytes(stack.MaxBodyBytes)))
+	}
+
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), "framework", "chi")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK - Chi"))
+	})
+
+	r.Route("/api", func(r chi.Router) {
+		r.Route("/test", func(r chi.Router) {
+			r.Get("/simple", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.SimpleRequest(w, r)
+			})
+			r.Get("/database", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.DatabaseQuery(w, r)
+			})
+			r.Get("/json", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.JsonResponse(w, r)
+			})
+			// Chi has no binder, so validation always runs adapted.
+			r.Post("/validate", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.ValidateOrder(w, r)
+			})
+		})
+		r.Get("/info", func(w http.ResponseWriter, r *http.Request) {
+			app.Controllers.FrameworkInfo(w, r)
+		})
+		r.Route("/orders", func(r chi.Router) {
+			r.Get("/recent", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.GetRecentOrders(w, r)
+			})
+			r.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.StreamOrders(w, r)
+			})
+		})
+		r.Route("/auth", func(r chi.Router) {
+			r.Post("/register", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.Register(w, r)
+			})
+			r.Post("/login", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.Login(w, r)
+			})
+			// The recent orders again, behind JWT verification.
+			r.With(middleware.RequireAuth(app.Controllers.Tokens)).Get("/orders/recent", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.GetRecentOrders(w, r)
+			})
+		})
+		r.Route("/files", func(r chi.Router) {
+			r.Post("/upload", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.UploadMultipart(w, r)
+			})
+			r.Post("/raw", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.UploadRaw(w, r)
+			})
+			r.Get("/download", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.DownloadFile(w, r)
+			})
+			r.Head("/download", func(w http.ResponseWriter, r *http.Request) {
+				app.Controllers.DownloadFile(w, r)
+			})
+		})
+	})
+
+	r.Get("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GraphQL(w, r)
+	})
+	r.Post("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GraphQL(w, r)
+	})
+
+	// WebSocket routes
+	hub := ws.NewHub()
+	r.Route("/ws", func(r chi.Router) {
+		r.Get("/echo", ws.NewCoderHandler(ws.Echo).ServeHTTP)
+		r.Get("/broadcast", ws.NewCoderHandler(hub.Serve).ServeHTTP)
+	})
+
+	// Templ routes
+	r.Get("/templ", func(w http.ResponseWriter, r *http.Request) {
+		app.TemplController.HomePage(w, r)
+	})
+	r.Get("/templ/run-test", func(w http.ResponseWriter, r *http.Request) {
+		app.TemplController.RunTest(w, r)
+	})
+
+	// Sub-routers inherit these.
+	r.NotFound(problem.NotFoundHandler)
+	r.MethodNotAllowed(problem.MethodNotAllowedHandler)
+
+	return newHTTPServer(fw, r)
+}
+
+// chiAccessLog plugs the shared access log format into Chi's RequestLogger.
+type chiAccessLog struct{}
+
+func (chiAccessLog) NewLogEntry(r *http.Request) chimiddleware.LogEntry {
+	return chiAccessEntry{r: r}
+}
+
+type chiAccessEntry struct {
+	r *http.Request
+}
+
+func (e chiAccessEntry) Write(status, bytes int, _ http.Header, elapsed time.Duration, _ interface{}) {
+	middleware.LogAccess(middleware.AccessEntry{
+		Framework: "chi",
+		RequestID: chimiddleware.GetReqID(e.r.Context()),
+		Method:    e.r.Method,
+		Path:      e.r.URL.Path,
+		Status:    status,
+		Bytes:     int64(bytes),
+		Latency:   elapsed,
+	})
+}
+
+func (e chiAccessEntry) Panic(v interface{}, _ []byte) {
+	chimiddleware.PrintPrettyStack(v)
+}
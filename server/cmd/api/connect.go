@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/config"
+	"bananas/internal/middleware"
+	"bananas/internal/rpc"
+	"context"
+	"net/http"
+)
+
+// newConnectServer builds the Connect server. It runs on net/http, so it can
+// be served over any protocol, and answers Connect, gRPC and gRPC-Web calls.
+func newConnectServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK - Connect"))
+	})
+
+	// /api/info lets the bench record how the server is deployed, as it
+	// does for the HTTP frameworks.
+	mux.HandleFunc("/api/info", func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "framework", "connect")
+		app.Controllers.FrameworkInfo(w, r.WithContext(ctx))
+	})
+
+	path, handler := rpc.NewConnectHandler(app.Services)
+	mux.Handle(path, handler)
+
+	return newHTTPServer(fw, middleware.Chain(mux, middleware.Stack(fw.Name, fw.Middleware)...))
+}
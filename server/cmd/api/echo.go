@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/auth"
+	"bananas/internal/config"
+	"bananas/internal/middleware"
+	"bananas/internal/problem"
+	"bananas/internal/validation"
+	"bananas/internal/ws"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// newEchoServer builds the Echo server. Echo is served through our own
+// http.Server so it shares the configured timeouts and shutdown path.
+func newEchoServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	e := echo.New()
+	e.HideBanner = true
+	e.HTTPErrorHandler = echoErrorHandler(fw.Middleware)
+	// Echo binds but leaves validation to whatever validator it is given.
+	e.Validator = echoValidator{}
+
+	stack := fw.Middleware
+	if stack.Production() {
+		e.Use(echomiddleware.RequestID())
+		e.Use(echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
+			LogRequestID:    true,
+			LogMethod:       true,
+			LogURIPath:      true,
+			LogStatus:       true,
+			LogResponseSize: true,
+			LogLatency:      true,
+			LogValuesFunc: func(c echo.Context, v echomiddleware.RequestLoggerValues) error {
+				middleware.LogAccess(middleware.AccessEntry{
+					Framework: "echo",
+					RequestID: v.RequestID,
+					Method:    v.Method,
+					Path:      v.URIPath,
+					Status:    v.Status,
+					Bytes:     v.ResponseSize,
+					Latency:   v.Latency,
+				})
+				return nil
+			},
+		}))
+	}
+	if stack.Minimal() {
+		e.Use(echomiddleware.Recover())
+		e.Use(echomiddleware.CORS())
+	}
+	if stack.Production() {
+		// Echo's Gzip middleware has no brotli, so use the shared compressor.
+		// WebSocket handshakes skip it: WrapMiddleware swaps in a new
+		// Response, which would hide the upgrade's status from the logger.
+		compress := echo.WrapMiddleware(middleware.Compress)
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			compressed := compress(next)
+			return func(c echo.Context) error {
+				if middleware.IsWebSocketUpgrade(c.Request()) {
+					return next(c)
+				}
+				return compressed(c)
+			}
+		})
+		e.Use(echomiddleware.ContextTimeoutWithConfig(echomiddleware.ContextTimeoutConfig{
+			Skipper: func(c echo.Context) bool { return middleware.IsLongLived(c.Request()) },
+			Timeout: stack.RequestTimeout,
+		}))
+		e.Use(echomiddleware.BodyLimitWithConfig(echomiddleware.BodyLimitConfig{
+			Skipper: func(c echo.Context) bool { return middleware.IsFileTransfer(c.Request().URL.Path) },
+			Limit:   strconv.FormatInt(stack.MaxBodyBytes, 10),
+		}))
+	}
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), "framework", "echo")))
+			return next(c)
+		}
+	})
+
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK - Echo")
+	})
+
+	api := e.Group("/api")
+	{
+		test := api.Group("/test")
+		{
+			test.GET("/simple", func(c echo.Context) error {
+				app.Controllers.SimpleRequest(c.Response(), c.Request())
+				return nil
+			})
+			test.GET("/database", func(c echo.Context) error {
+				app.Controllers.DatabaseQuery(c.Response(), c.Request())
+				return nil
+			})
+			test.GET("/json", func(c echo.Context) error {
+				app.Controllers.JsonResponse(c.Response(), c.Request())
+				return nil
+			})
+			test.POST("/validate", echoValidateOrder(app))
+		}
+		api.GET("/info", func(c echo.Context) error {
+			app.Controllers.FrameworkInfo(c.Response(), c.Request())
+			return nil
+		})
+		orders := api.Group("/orders")
+		{
+			orders.GET("/recent", func(c echo.Context) error {
+				app.Controllers.GetRecentOrders(c.Response(), c.Request())
+				return nil
+			})
+			orders.GET("/stream", func(c echo.Context) error {
+				app.Controllers.StreamOrders(c.Response(), c.Request())
+				return nil
+			})
+		}
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/register", func(c echo.Context) error {
+				app.Controllers.Register(c.Response(), c.Request())
+				return nil
+			})
+			authRoutes.POST("/login", func(c echo.Context) error {
+				app.Controllers.Login(c.Response(), c.Request())
+				return nil
+			})
+			// The recent orders again, behind JWT verification.
+			authRoutes.GET("/orders/recent", func(c echo.Context) error {
+				app.Controllers.GetRecentOrders(c.Response(), c.Request())
+				return nil
+			}, echoRequireAuth(app.Controllers.Tokens))
+		}
+		files := api.Group("/files")
+		{
+			files.POST("/upload", func(c echo.Context) error {
+				app.Controllers.UploadMultipart(c.Response(), c.Request())
+				return nil
+			})
+			files.POST("/raw", func(c echo.Context) error {
+				app.Controllers.UploadRaw(c.Response(), c.Request())
+				return nil
+			})
+			files.GET("/download", func(c echo.Context) error {
+				app.Controllers.DownloadFile(c.Response(), c.Request())
+				return nil
+			})
+			files.HEAD("/download", func(c echo.Context) error {
+				app.Controllers.DownloadFile(c.Response(), c.Request())
+				return nil
+			})
+		}
+	}
+
+	e.GET("/graphql", func(c echo.Context) error {
+		app.Controllers.GraphQL(c.Response(), c.Request())
+		return nil
+	})
+	e.POST("/graphql", func(c echo.Context) error {
+		app.Controllers.GraphQL(c.Response(), c.Request())
+		return nil
+	})
+
+	// WebSocket routes
+	hub := ws.NewHub()
+	// gorilla/websocket writes the 101 to the hijacked connection, leaving
+	// Echo's response uncommitted; record it for the access log.
+	sockets := e.Group("/ws", func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if !c.Response().Committed {
+				c.Response().Status = http.StatusSwitchingProtocols
+			}
+			return err
+		}
+	})
+	{
+		sockets.GET("/echo", echo.WrapHandler(ws.NewGorillaHandler(ws.Echo)))
+		sockets.GET("/broadcast", echo.WrapHandler(ws.NewGorillaHandler(hub.Serve)))
+	}
+
+	// Templ routes
+	e.GET("/templ", func(c echo.Context) error {
+		app.TemplController.HomePage(c.Response(), c.Request())
+		return nil
+	})
+	e.GET("/templ/run-test", func(c echo.Context) error {
+		app.TemplController.RunTest(c.Response(), c.Request())
+		return nil
+	})
+
+	return newHTTPServer(fw, e)
+}
+
+// echoRequireAuth is middleware.RequireAuth as Echo middleware.
+func echoRequireAuth(tokens *auth.Tokens) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := tokens.Authenticate(c.Request().Header.Get("Authorization"))
+			if err != nil {
+				c.Response().Header().Set("WWW-Authenticate", auth.Challenge(err))
+				return echoProblem(c, auth.Unauthorized(err))
+			}
+			c.SetRequest(c.Request().WithContext(auth.WithClaims(c.Request().Context(), claims)))
+			return next(c)
+		}
+	}
+}
+
+// echoErrorHandler answers the errors Echo's router and middleware return,
+// and any a handler returns, with a problem.
+func echoErrorHandler(stack config.MiddlewareConfig) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		p := problem.Internal()
+		var he *echo.HTTPError
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			p = problem.Timeout(stack.RequestTimeout)
+		case errors.As(err, &he):
+			switch he.Code {
+			case http.StatusNotFound:
+				p = problem.NotFound()
+			case http.StatusMethodNotAllowed:
+				p = problem.MethodNotAllowed(c.Request().Method)
+			case http.StatusRequestEntityTooLarge:
+				p = problem.TooLarge(stack.MaxBodyBytes)
+			default:
+				p = problem.New(he.Code, fmt.Sprint(he.Message))
+			}
+		}
+
+		if err := echoProblem(c, p); err != nil {
+			c.Logger().Error(err)
+		}
+	}
+}
+
+// echoProblem answers with p through Echo's own JSON serializer.
+func echoProblem(c echo.Context, p problem.Problem) error {
+	c.Response().Header().Set(echo.HeaderContentType, problem.MediaType)
+	return c.JSON(p.Status, p.For(c.Response(), c.Request()))
+}
+
+// echoValidator plugs the shared validator into c.Validate.
+type echoValidator struct{}
+
+func (echoValidator) Validate(i interface{}) error {
+	return validation.Validate(i)
+}
+
+// echoValidateOrder binds the order with Echo's binder and validates it
+// through c.Validate in native mode, leaving adapted mode to the shared
+// controller.
+func echoValidateOrder(app *app.App) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if mode := c.QueryParam("mode"); mode != "" && mode != validation.ModeNative {
+			app.Controllers.ValidateOrder(c.Response(), c.Request())
+			return nil
+		}
+
+		var req validation.OrderRequest
+		err := c.Bind(&req)
+		if err == nil {
+			err = c.Validate(&req)
+		}
+		if err != nil {
+			return echoProblem(c, validation.Problem(err))
+		}
+		return c.JSON(http.StatusOK, validation.Summarize(&req, validation.ModeNative, "echo"))
+	}
+}
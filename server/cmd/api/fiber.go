@@ -0,0 +1,464 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/auth"
+	"bananas/internal/certs"
+	"bananas/internal/codec"
+	"bananas/internal/config"
+	"bananas/internal/controllers"
+	"bananas/internal/middleware"
+	"bananas/internal/problem"
+	"bananas/internal/validation"
+	"bananas/internal/ws"
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/fiber/v2/middleware/timeout"
+)
+
+// fiberAccessLogFormat renders the same JSON line as middleware.FormatAccess.
+const fiberAccessLogFormat = `{"time":"${time}","level":"INFO","msg":"request","framework":"fiber",` +
+	`"request_id":"${locals:requestid}","method":"${method}","path":"${path}",` +
+	`"status":${status},"bytes":${bytesSent},"latency_us":${latency}}` + "\n"
+
+// fiberServer adapts a Fiber app to the frameworkServer lifecycle. Fiber runs
+// on fasthttp, so it only ever serves HTTP/1.1, optionally over TLS.
+type fiberServer struct {
+	app *fiber.App
+	fw  config.FrameworkConfig
+}
+
+// ListenAndServe preforks when several listeners are configured, which
+// config rules out alongside a socket. Otherwise the TCP listener and any
+// socket come from listen, as for the other frameworks, and are served
+// concurrently by the app's one fasthttp server, since the App's own
+// Listen and Listener each set up the app and must not run side by side.
+func (s *fiberServer) ListenAndServe() error {
+	if s.fw.Listeners > 1 {
+		if s.fw.UsesTLS() {
+			return s.app.ListenTLS(s.fw.Addr(), s.fw.TLS.CertFile, s.fw.TLS.KeyFile)
+		}
+		return s.app.Listen(s.fw.Addr())
+	}
+
+	listeners, err := listen(s.fw)
+	if err != nil {
+		return err
+	}
+	if s.fw.UsesTLS() {
+		tlsConfig, err := certs.ServerConfig(s.fw.TLS)
+		if err != nil {
+			closeListeners(listeners)
+			return err
+		}
+		for i, ln := range listeners {
+			listeners[i] = tls.NewListener(ln, tlsConfig)
+		}
+	}
+
+	// Handler readies the app once, before any listener is served.
+	s.app.Handler()
+	server := s.app.Server()
+	errs := make(chan error, len(listeners))
+	for _, ln := range listeners {
+		go func(ln net.Listener) {
+			errs <- server.Serve(ln)
+		}(ln)
+	}
+
+	err = <-errs
+	if err != nil {
+		s.app.Shutdown()
+	}
+	return err
+}
+
+func (s *fiberServer) Shutdown(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
+}
+
+// newFiberServer builds the Fiber server.
+func newFiberServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	stack := fw.Middleware
+
+	fiberConfig := fiber.Config{
+		ReadTimeout:           fw.ReadTimeout,
+		WriteTimeout:          fw.WriteTimeout,
+		IdleTimeout:           fw.IdleTimeout,
+		ReadBufferSize:        fw.MaxHeaderBytes,
+		DisableStartupMessage: true,
+		Prefork:               fw.Listeners > 1,
+		// Hand request bodies to handlers as they arrive rather than reading
+		// them whole first, and leave multipart bodies unparsed, so uploads
+		// are buffered only when the endpoint chooses to.
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+		ErrorHandler:                 fiberErrorHandler(stack),
+	}
+	// Fiber's own JSON responses use the same codec as the shared controllers.
+	if encoder, ok := codec.JSON(fw.JSONCodec); ok {
+		fiberConfig.JSONEncoder = encoder.Marshal
+	}
+	if stack.Production() {
+		fiberConfig.BodyLimit = int(stack.MaxBodyBytes)
+	}
+	fiberApp := fiber.New(fiberConfig)
+
+	if stack.Production() {
+		fiberApp.Use(requestid.New())
+		fiberApp.Use(logger.New(logger.Config{
+			Format:     fiberAccessLogFormat,
+			TimeFormat: time.RFC3339Nano,
+			// Override the latency tag (rather than adding a new one) because
+			// Fiber only times requests when ${latency} is in the format.
+			CustomTags: map[string]logger.LogFunc{
+				logger.TagLatency: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, _ string) (int, error) {
+					return output.WriteString(strconv.FormatInt(data.Stop.Sub(data.Start).Microseconds(), 10))
+				},
+			},
+		}))
+	}
+	if stack.Minimal() {
+		fiberApp.Use(recover.New())
+		fiberApp.Use(cors.New(cors.Config{
+			AllowOrigins: "*",
+			AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
+			AllowHeaders: "Content-Type,Authorization",
+		}))
+	}
+	if stack.Production() {
+		// Streams are left uncompressed so each flush reaches the client, and
+		// file transfers because, unlike the shared compressor, Fiber's does
+		// not check the content type and would gzip binary payloads.
+		fiberApp.Use(compress.New(compress.Config{
+			Next: func(c *fiber.Ctx) bool {
+				return middleware.IsStream(c.Path(), c.Get(fiber.HeaderAccept)) || middleware.IsFileTransfer(c.Path())
+			},
+		}))
+		requestTimeout := timeout.NewWithContext(func(c *fiber.Ctx) error {
+			return c.Next()
+		}, stack.RequestTimeout)
+		fiberApp.Use(func(c *fiber.Ctx) error {
+			if middleware.IsFileTransfer(c.Path()) {
+				return c.Next()
+			}
+			return requestTimeout(c)
+		})
+		// A streamed body over BodyLimit is passed on rather than rejected,
+		// so enforce the limit up front, as middleware.MaxBytes does.
+		fiberApp.Use(func(c *fiber.Ctx) error {
+			if !middleware.IsFileTransfer(c.Path()) && c.Request().Header.ContentLength() > int(stack.MaxBodyBytes) {
+				return fiber.ErrRequestEntityTooLarge
+			}
+			return c.Next()
+		})
+	}
+
+	fiberApp.Use(func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("framework", "fiber")
+		return c.Next()
+	})
+
+	fiberApp.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendString("OK - Fiber")
+	})
+
+	api := fiberApp.Group("/api")
+	{
+		test := api.Group("/test")
+		{
+			test.Get("/simple", fiberHandler(app.Controllers.SimpleRequest))
+			test.Get("/database", fiberHandler(app.Controllers.DatabaseQuery))
+			test.Get("/json", fiberHandler(app.Controllers.JsonResponse))
+			test.Post("/validate", fiberValidateOrder(app))
+		}
+		api.Get("/info", fiberHandler(app.Controllers.FrameworkInfo))
+		orders := api.Group("/orders")
+		{
+			orders.Get("/recent", fiberHandler(app.Controllers.GetRecentOrders))
+			orders.Get("/stream", fiberOrderStream(app.Controllers, fw.WriteTimeout))
+		}
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.Post("/register", fiberHandler(app.Controllers.Register))
+			authRoutes.Post("/login", fiberHandler(app.Controllers.Login))
+			// The recent orders again, behind JWT verification.
+			authRoutes.Get("/orders/recent", fiberRequireAuth(app.Controllers.Tokens), fiberHandler(app.Controllers.GetRecentOrders))
+		}
+		files := api.Group("/files")
+		{
+			files.Post("/upload", fiberHandler(app.Controllers.UploadMultipart))
+			files.Post("/raw", fiberHandler(app.Controllers.UploadRaw))
+			files.Get("/download", fiberDownload(app.Controllers, fw.WriteTimeout))
+		}
+	}
+
+	fiberApp.Get("/graphql", fiberHandler(app.Controllers.GraphQL))
+	fiberApp.Post("/graphql", fiberHandler(app.Controllers.GraphQL))
+
+	// WebSocket routes. Plain requests are turned away before the upgrade.
+	hub := ws.NewHub()
+	sockets := fiberApp.Group("/ws", func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		return c.Next()
+	})
+	{
+		sockets.Get("/echo", fiberWebSocket(ws.Echo))
+		sockets.Get("/broadcast", fiberWebSocket(hub.Serve))
+	}
+
+	// Templ routes
+	fiberApp.Get("/templ", fiberHandler(app.TemplController.HomePage))
+	fiberApp.Get("/templ/run-test", fiberHandler(app.TemplController.RunTest))
+
+	return &fiberServer{app: fiberApp, fw: fw}
+}
+
+// fiberWebSocket upgrades with Fiber's websocket middleware and hands the
+// connection to serve.
+func fiberWebSocket(serve func(ws.Conn)) fiber.Handler {
+	return websocket.New(func(c *websocket.Conn) {
+		c.SetReadLimit(ws.MaxMessageSize)
+		serve(c)
+	})
+}
+
+// fiberValidateOrder parses the order with Fiber's BodyParser and validates
+// it with the shared validator, Fiber having none of its own, in native
+// mode. Adapted mode goes to the shared controller.
+func fiberValidateOrder(app *app.App) fiber.Handler {
+	adapted := fiberHandler(app.Controllers.ValidateOrder)
+	return func(c *fiber.Ctx) error {
+		if mode := c.Query("mode"); mode != "" && mode != validation.ModeNative {
+			return adapted(c)
+		}
+
+		var req validation.OrderRequest
+		err := c.BodyParser(&req)
+		if err == nil {
+			err = validation.Validate(&req)
+		}
+		if err != nil {
+			return fiberProblem(c, validation.Problem(err))
+		}
+		return c.JSON(validation.Summarize(&req, validation.ModeNative, "fiber"))
+	}
+}
+
+// fiberErrorHandler answers the errors Fiber's router, server and middleware
+// return, and any a handler returns, with a problem.
+func fiberErrorHandler(stack config.MiddlewareConfig) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		p := problem.Internal()
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			switch fe.Code {
+			case fiber.StatusNotFound:
+				p = problem.NotFound()
+			case fiber.StatusMethodNotAllowed:
+				p = problem.MethodNotAllowed(c.Method())
+			case fiber.StatusRequestTimeout:
+				// The timeout middleware's error, answered with the 504 the
+				// other frameworks send.
+				p = problem.Timeout(stack.RequestTimeout)
+			case fiber.StatusRequestEntityTooLarge:
+				p = problem.TooLarge(stack.MaxBodyBytes)
+			default:
+				p = problem.New(fe.Code, fe.Message)
+			}
+		}
+		return fiberProblem(c, p)
+	}
+}
+
+// fiberProblem answers with p through Fiber's JSON encoder.
+func fiberProblem(c *fiber.Ctx, p problem.Problem) error {
+	return c.Status(p.Status).JSON(p.At(c.Path(), c.GetRespHeader(fiber.HeaderXRequestID)), problem.MediaType)
+}
+
+// fiberRequireAuth is middleware.RequireAuth as Fiber middleware. The claims
+// go in the user context, which fiberHandler builds the request from.
+func fiberRequireAuth(tokens *auth.Tokens) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := tokens.Authenticate(c.Get(fiber.HeaderAuthorization))
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, auth.Challenge(err))
+			return fiberProblem(c, auth.Unauthorized(err))
+		}
+		c.SetUserContext(auth.WithClaims(c.UserContext(), claims))
+		return c.Next()
+	}
+}
+
+// fiberHandler bridges a shared net/http controller method onto a Fiber route.
+func fiberHandler(handler http.HandlerFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		handler(&fiberResponseWriter{ctx: c}, fiberRequest(c.UserContext(), c))
+		return nil
+	}
+}
+
+// fiberRequest builds the net/http view of a Fiber request for the shared
+// controllers. Request headers are passed through so controllers can
+// negotiate on Accept. The body is read from fasthttp's request stream, so
+// an upload reaches the controller as it arrives.
+func fiberRequest(ctx context.Context, c *fiber.Ctx) *http.Request {
+	parsedURL, _ := url.Parse(c.OriginalURL())
+	req := &http.Request{
+		Method:        c.Method(),
+		URL:           parsedURL,
+		Header:        http.Header(c.GetReqHeaders()),
+		Host:          c.Hostname(),
+		Body:          http.NoBody,
+		ContentLength: max(int64(c.Request().Header.ContentLength()), -1),
+	}
+	if stream := c.Context().RequestBodyStream(); stream != nil {
+		req.Body = io.NopCloser(stream)
+	}
+	// The controllers cannot see the response headers, so the ID the
+	// requestid middleware assigned is passed on as if the client sent it.
+	if id := c.GetRespHeader(fiber.HeaderXRequestID); id != "" {
+		req.Header.Set(fiber.HeaderXRequestID, id)
+	}
+	return req.WithContext(context.WithValue(ctx, "framework", "fiber"))
+}
+
+// fiberOrderStream serves /api/orders/stream from fasthttp's body stream
+// writer, since a Fiber handler's writes are buffered until it returns. The
+// writer runs after the handler, and after any timeout middleware has
+// cancelled the request context, so the stream gets a context of its own;
+// a failed flush is what ends it when the client goes away. fasthttp sets the
+// write deadline once per response, so each flush extends it as net/http
+// streams do.
+func fiberOrderStream(controller *controllers.BaseController, writeTimeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		writer := &fiberResponseWriter{ctx: c}
+		stream, ok := controller.NewOrderStream(writer, fiberRequest(c.UserContext(), c))
+		if !ok {
+			return nil
+		}
+		stream.SetHeaders(writer.Header())
+		writer.WriteHeader(http.StatusOK)
+
+		ctx := context.WithValue(context.Background(), "framework", "fiber")
+		conn := c.Context().Conn()
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			flush := func() error {
+				if writeTimeout > 0 {
+					conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+				}
+				return w.Flush()
+			}
+			if err := flush(); err != nil {
+				return
+			}
+			stream.Write(ctx, w, flush)
+		})
+		return nil
+	}
+}
+
+// fiberDownload serves /api/files/download through fasthttp's body stream,
+// since a Fiber handler's writes are buffered until it returns and a large
+// payload would be held in memory whole. fasthttp leaves the body out of HEAD
+// responses itself.
+func fiberDownload(controller *controllers.BaseController, writeTimeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		writer := &fiberResponseWriter{ctx: c}
+		download, ok := controller.NewFileDownload(writer, fiberRequest(c.UserContext(), c))
+		if !ok {
+			return nil
+		}
+		download.SetHeaders(writer.Header())
+		writer.WriteHeader(download.Status())
+
+		body := download.Body()
+		if body == nil {
+			return nil
+		}
+		if writeTimeout > 0 {
+			body = &fiberDeadlineReader{r: body, conn: c.Context().Conn(), timeout: writeTimeout}
+		}
+		c.Context().SetBodyStream(body, int(download.Length()))
+		return nil
+	}
+}
+
+// fiberDeadlineReader extends the write deadline each time fasthttp reads
+// more of a streamed body, since fasthttp sets it once per response.
+type fiberDeadlineReader struct {
+	r       io.Reader
+	conn    net.Conn
+	timeout time.Duration
+}
+
+func (d *fiberDeadlineReader) Read(p []byte) (int, error) {
+	d.conn.SetWriteDeadline(time.Now().Add(d.timeout))
+	return d.r.Read(p)
+}
+
+// fiberResponseWriter adapts Fiber's Ctx to http.ResponseWriter. Headers are
+// collected in a regular http.Header and copied onto the Fiber response when
+// the status is written, matching net/http semantics.
+type fiberResponseWriter struct {
+	ctx         *fiber.Ctx
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *fiberResponseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *fiberResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ctx.Write(data)
+}
+
+func (w *fiberResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	for key, values := range w.header {
+		w.ctx.Response().Header.Del(key)
+		for _, value := range values {
+			w.ctx.Response().Header.Add(key, value)
+		}
+	}
+	w.ctx.Status(statusCode)
+}
+
+// SetReadDeadline and SetWriteDeadline let http.ResponseController extend
+// the connection's deadlines, as controllers do for long transfers.
+func (w *fiberResponseWriter) SetReadDeadline(deadline time.Time) error {
+	return w.ctx.Context().Conn().SetReadDeadline(deadline)
+}
+
+func (w *fiberResponseWriter) SetWriteDeadline(deadline time.Time) error {
+	return w.ctx.Context().Conn().SetWriteDeadline(deadline)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/auth"
+	"bananas/internal/config"
+	"bananas/internal/middleware"
+	"bananas/internal/problem"
+	"bananas/internal/validation"
+	"bananas/internal/ws"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// newGinServer builds the Gin server.
+func newGinServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+
+	stack := fw.Middleware
+	if stack.Production() {
+		r.Use(gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+			return middleware.FormatAccess(middleware.AccessEntry{
+				Framework: "gin",
+				RequestID: middleware.GetRequestID(p.Request.Context()),
+				Method:    p.Method,
+				Path:      p.Request.URL.Path,
+				Status:    p.StatusCode,
+				Bytes:     int64(p.BodySize),
+				Latency:   p.Latency,
+			})
+		}))
+	}
+	if stack.Minimal() {
+		r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			ginProblem(c, problem.Internal())
+		}))
+
+		// CORS middleware
+		r.Use(func(c *gin.Context) {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+			if c.Request.Method == "OPTIONS" {
+				c.AbortWithStatus(http.StatusOK)
+				return
+			}
+
+			c.Next()
+		})
+	}
+
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "framework", "gin"))
+		c.Next()
+	})
+
+	r.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, "OK - Gin")
+	})
+
+	api := r.Group("/api")
+	{
+		test := api.Group("/test")
+		{
+			test.GET("/simple", func(c *gin.Context) {
+				app.Controllers.SimpleRequest(c.Writer, c.Request)
+			})
+			test.GET("/database", func(c *gin.Context) {
+				app.Controllers.DatabaseQuery(c.Writer, c.Request)
+			})
+			test.GET("/json", func(c *gin.Context) {
+				app.Controllers.JsonResponse(c.Writer, c.Request)
+			})
+			test.POST("/validate", ginValidateOrder(app))
+		}
+		api.GET("/info", func(c *gin.Context) {
+			app.Controllers.FrameworkInfo(c.Writer, c.Request)
+		})
+		orders := api.Group("/orders")
+		{
+			orders.GET("/recent", func(c *gin.Context) {
+				app.Controllers.GetRecentOrders(c.Writer, c.Request)
+			})
+			orders.GET("/stream", func(c *gin.Context) {
+				app.Controllers.StreamOrders(c.Writer, c.Request)
+			})
+		}
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/register", func(c *gin.Context) {
+				app.Controllers.Register(c.Writer, c.Request)
+			})
+			authRoutes.POST("/login", func(c *gin.Context) {
+				app.Controllers.Login(c.Writer, c.Request)
+			})
+			// The recent orders again, behind JWT verification.
+			authRoutes.GET("/orders/recent", ginRequireAuth(app.Controllers.Tokens), func(c *gin.Context) {
+				app.Controllers.GetRecentOrders(c.Writer, c.Request)
+			})
+		}
+		files := api.Group("/files")
+		{
+			files.POST("/upload", func(c *gin.Context) {
+				app.Controllers.UploadMultipart(c.Writer, c.Request)
+			})
+			files.POST("/raw", func(c *gin.Context) {
+				app.Controllers.UploadRaw(c.Writer, c.Request)
+			})
+			files.GET("/download", func(c *gin.Context) {
+				app.Controllers.DownloadFile(c.Writer, c.Request)
+			})
+			files.HEAD("/download", func(c *gin.Context) {
+				app.Controllers.DownloadFile(c.Writer, c.Request)
+			})
+		}
+	}
+
+	r.GET("/graphql", func(c *gin.Context) {
+		app.Controllers.GraphQL(c.Writer, c.Request)
+	})
+	r.POST("/graphql", func(c *gin.Context) {
+		app.Controllers.GraphQL(c.Writer, c.Request)
+	})
+
+	// WebSocket routes
+	hub := ws.NewHub()
+	// gorilla/websocket writes the 101 to the hijacked connection, so record
+	// it on Gin's writer first for the access log. A failed handshake still
+	// overrides it with its own status.
+	sockets := r.Group("/ws", func(c *gin.Context) {
+		c.Status(http.StatusSwitchingProtocols)
+		c.Next()
+	})
+	{
+		sockets.GET("/echo", gin.WrapH(ws.NewGorillaHandler(ws.Echo)))
+		sockets.GET("/broadcast", gin.WrapH(ws.NewGorillaHandler(hub.Serve)))
+	}
+
+	// Templ routes
+	r.GET("/templ", func(c *gin.Context) {
+		app.TemplController.HomePage(c.Writer, c.Request)
+	})
+	r.GET("/templ/run-test", func(c *gin.Context) {
+		app.TemplController.RunTest(c.Writer, c.Request)
+	})
+
+	// Gin answers 405 only when asked to, and both in plain text unless
+	// given handlers.
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		ginProblem(c, problem.NotFound())
+	})
+	r.NoMethod(func(c *gin.Context) {
+		ginProblem(c, problem.MethodNotAllowed(c.Request.Method))
+	})
+
+	// Gin's core has no request ID, compression, timeout or body limit
+	// middleware, so the shared net/http versions wrap the engine.
+	var handler http.Handler = r
+	if stack.Production() {
+		handler = middleware.Chain(r,
+			middleware.RequestID,
+			middleware.Compress,
+			middleware.SkipLongLived(middleware.Timeout(stack.RequestTimeout)),
+			middleware.SkipFileTransfers(middleware.MaxBytes(stack.MaxBodyBytes)),
+		)
+	}
+
+	return newHTTPServer(fw, handler)
+}
+
+// ginRequireAuth is middleware.RequireAuth as Gin middleware.
+func ginRequireAuth(tokens *auth.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := tokens.Authenticate(c.GetHeader("Authorization"))
+		if err != nil {
+			c.Header("WWW-Authenticate", auth.Challenge(err))
+			ginProblem(c, auth.Unauthorized(err))
+			return
+		}
+		c.Request = c.Request.WithContext(auth.WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+// ginProblem answers with p through Gin's own JSON rendering and stops the
+// handler chain.
+func ginProblem(c *gin.Context, p problem.Problem) {
+	c.Header("Content-Type", problem.MediaType)
+	c.AbortWithStatusJSON(p.Status, p.For(c.Writer, c.Request))
+}
+
+// ginValidateOrder binds and validates the order with Gin's own binding in
+// native mode, leaving adapted mode to the shared controller.
+func ginValidateOrder(app *app.App) gin.HandlerFunc {
+	// Gin's validator reports Go field names unless told otherwise.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validation.UseJSONNames(v)
+	}
+
+	return func(c *gin.Context) {
+		if mode := c.Query("mode"); mode != "" && mode != validation.ModeNative {
+			app.Controllers.ValidateOrder(c.Writer, c.Request)
+			return
+		}
+
+		var req validation.OrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ginProblem(c, validation.Problem(err))
+			return
+		}
+		c.JSON(http.StatusOK, validation.Summarize(&req, validation.ModeNative, "gin"))
+	}
+}
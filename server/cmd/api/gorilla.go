@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/config"
+	"bananas/internal/middleware"
+	"bananas/internal/problem"
+	"bananas/internal/ws"
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// newGorillaServer builds the Gorilla Mux server.
+func newGorillaServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	r := mux.NewRouter()
+
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), "framework", "gorilla")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK - Gorilla Mux"))
+	}).Methods("GET")
+
+	api := r.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.FrameworkInfo(w, r)
+	}).Methods("GET")
+
+	test := api.PathPrefix("/test").Subrouter()
+	test.HandleFunc("/simple", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.SimpleRequest(w, r)
+	}).Methods("GET")
+
+	test.HandleFunc("/database", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.DatabaseQuery(w, r)
+	}).Methods("GET")
+
+	test.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.JsonResponse(w, r)
+	}).Methods("GET")
+
+	// Gorilla has no binder, so validation always runs adapted.
+	test.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.ValidateOrder(w, r)
+	}).Methods("POST")
+
+	orders := api.PathPrefix("/orders").Subrouter()
+	orders.HandleFunc("/recent", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GetRecentOrders(w, r)
+	}).Methods("GET")
+
+	orders.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.StreamOrders(w, r)
+	}).Methods("GET")
+
+	authRoutes := api.PathPrefix("/auth").Subrouter()
+	authRoutes.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.Register(w, r)
+	}).Methods("POST")
+
+	authRoutes.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.Login(w, r)
+	}).Methods("POST")
+
+	// The recent orders again, behind JWT verification.
+	protected := authRoutes.PathPrefix("/orders").Subrouter()
+	protected.Use(mux.MiddlewareFunc(middleware.RequireAuth(app.Controllers.Tokens)))
+	protected.HandleFunc("/recent", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GetRecentOrders(w, r)
+	}).Methods("GET")
+
+	files := api.PathPrefix("/files").Subrouter()
+	files.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.UploadMultipart(w, r)
+	}).Methods("POST")
+
+	files.HandleFunc("/raw", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.UploadRaw(w, r)
+	}).Methods("POST")
+
+	files.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.DownloadFile(w, r)
+	}).Methods("GET", "HEAD")
+
+	r.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GraphQL(w, r)
+	}).Methods("GET", "POST")
+
+	// WebSocket routes
+	hub := ws.NewHub()
+	sockets := r.PathPrefix("/ws").Subrouter()
+	sockets.Handle("/echo", ws.NewGorillaHandler(ws.Echo)).Methods("GET")
+	sockets.Handle("/broadcast", ws.NewGorillaHandler(hub.Serve)).Methods("GET")
+
+	// Templ routes
+	r.HandleFunc("/templ", func(w http.ResponseWriter, r *http.Request) {
+		app.TemplController.HomePage(w, r)
+	}).Methods("GET")
+	r.HandleFunc("/templ/run-test", func(w http.ResponseWriter, r *http.Request) {
+		app.TemplController.RunTest(w, r)
+	}).Methods("GET")
+
+	r.NotFoundHandler = gorillaNotFound(r)
+	r.MethodNotAllowedHandler = http.HandlerFunc(problem.MethodNotAllowedHandler)
+
+	// Gorilla has no middleware of its own, and r.Use only runs for matched
+	// routes, so the shared stack wraps the whole router.
+	handler := middleware.Chain(r, middleware.Stack(fw.Name, fw.Middleware)...)
+	return newHTTPServer(fw, handler)
+}
+
+// gorillaNotFound answers unmatched requests, telling a 405 from a 404 by
+// trying the request's path with other methods. Gorilla Mux forgets a method
+// mismatch in a sub-router as soon as a later route there misses on its
+// path, so it would answer most of them with 404.
+func gorillaNotFound(router *mux.Router) http.Handler {
+	methods := []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, method := range methods {
+			probe := *r
+			probe.Method = method
+			var match mux.RouteMatch
+			if method != r.Method && router.Match(&probe, &match) && match.MatchErr == nil {
+				problem.MethodNotAllowedHandler(w, r)
+				return
+			}
+		}
+		problem.NotFoundHandler(w, r)
+	})
+}
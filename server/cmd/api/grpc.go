@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/certs"
+	"bananas/internal/config"
+	"bananas/internal/httpclient"
+	"bananas/internal/rpc"
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// grpcServer serves BananasService with grpc-go's own HTTP/2 transport
+// rather than through net/http, over h2c or h2 depending on the protocol.
+type grpcServer struct {
+	fw     config.FrameworkConfig
+	server *grpc.Server
+}
+
+// newGRPCServer builds the native gRPC server.
+func newGRPCServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	return &grpcServer{fw: fw, server: rpc.NewGRPCServer(app.Services, fw.Middleware)}
+}
+
+func (s *grpcServer) ListenAndServe() error {
+	listeners, err := listen(s.fw)
+	if err != nil {
+		return err
+	}
+
+	if s.fw.UsesTLS() {
+		tlsConfig, err := certs.ServerConfig(s.fw.TLS)
+		if err != nil {
+			closeListeners(listeners)
+			return err
+		}
+		tlsConfig.NextProtos = []string{"h2"}
+		for i, ln := range listeners {
+			listeners[i] = tls.NewListener(ln, tlsConfig)
+		}
+	}
+
+	// Serve returns nil once the server is stopped, so the first non-nil
+	// error is a real failure and stops the remaining listeners.
+	errs := make(chan error, len(listeners))
+	for _, ln := range listeners {
+		go func(ln net.Listener) {
+			errs <- s.server.Serve(ln)
+		}(ln)
+	}
+
+	err = <-errs
+	if err != nil {
+		s.server.Stop()
+	}
+	return err
+}
+
+// Shutdown drains in-flight calls, cutting them off if ctx expires first.
+func (s *grpcServer) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// grpcHealthCheck asks the standard gRPC health service whether fw is serving.
+func grpcHealthCheck(fw config.FrameworkConfig, timeout time.Duration) (bool, error) {
+	conn, err := httpclient.GRPC(fw.DialAddr(), httpclient.Options{Protocol: fw.Protocol, TLS: fw.TLS})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetStatus() == healthpb.HealthCheckResponse_SERVING, nil
+}
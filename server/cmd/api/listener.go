@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bananas/internal/certs"
+	"bananas/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// httpServer serves a net/http handler over the framework's configured
+// protocol and listeners. For h3 it runs a QUIC listener alongside the TLS
+// one on the same port number, and the TLS side advertises HTTP/3 through
+// Alt-Svc.
+type httpServer struct {
+	fw     config.FrameworkConfig
+	server *http.Server
+	h3     *http3.Server
+}
+
+// newHTTPServer applies the configured address, protocol and connection
+// limits to a net/http server wrapping the framework's handler.
+func newHTTPServer(fw config.FrameworkConfig, handler http.Handler) frameworkServer {
+	s := &httpServer{
+		fw: fw,
+		server: &http.Server{
+			Addr:           fw.Addr(),
+			Handler:        handler,
+			ReadTimeout:    fw.ReadTimeout,
+			WriteTimeout:   fw.WriteTimeout,
+			IdleTimeout:    fw.IdleTimeout,
+			MaxHeaderBytes: fw.MaxHeaderBytes,
+			Protocols:      new(http.Protocols),
+		},
+	}
+
+	switch fw.Protocol {
+	case config.ProtocolHTTP1, config.ProtocolHTTPS:
+		s.server.Protocols.SetHTTP1(true)
+	case config.ProtocolH2:
+		s.server.Protocols.SetHTTP1(true)
+		s.server.Protocols.SetHTTP2(true)
+	case config.ProtocolH2C:
+		s.server.Protocols.SetHTTP1(true)
+		s.server.Protocols.SetUnencryptedHTTP2(true)
+	case config.ProtocolH3:
+		s.server.Protocols.SetHTTP1(true)
+		s.server.Protocols.SetHTTP2(true)
+		s.h3 = &http3.Server{
+			Addr:           fw.Addr(),
+			Handler:        handler,
+			IdleTimeout:    fw.IdleTimeout,
+			MaxHeaderBytes: fw.MaxHeaderBytes,
+		}
+		s.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.h3.SetQUICHeaders(w.Header())
+			handler.ServeHTTP(w, r)
+		})
+	}
+
+	return s
+}
+
+func (s *httpServer) ListenAndServe() error {
+	listeners, err := listen(s.fw)
+	if err != nil {
+		return err
+	}
+
+	if s.fw.UsesTLS() {
+		tlsConfig, err := certs.ServerConfig(s.fw.TLS)
+		if err != nil {
+			closeListeners(listeners)
+			return err
+		}
+		s.server.TLSConfig = tlsConfig
+		if s.h3 != nil {
+			s.h3.TLSConfig = http3.ConfigureTLSConfig(tlsConfig.Clone())
+		}
+	}
+
+	errs := make(chan error, len(listeners)+1)
+	for _, ln := range listeners {
+		go func(ln net.Listener) {
+			if s.fw.UsesTLS() {
+				errs <- s.server.ServeTLS(ln, "", "")
+			} else {
+				errs <- s.server.Serve(ln)
+			}
+		}(ln)
+	}
+	if s.h3 != nil {
+		go func() {
+			errs <- s.h3.ListenAndServe()
+		}()
+	}
+
+	// The first listener to stop decides the result. Anything other than a
+	// shutdown takes the rest down too so the failure surfaces.
+	err = <-errs
+	if !errors.Is(err, http.ErrServerClosed) {
+		s.server.Close()
+		if s.h3 != nil {
+			s.h3.Close()
+		}
+	}
+	return err
+}
+
+func (s *httpServer) Shutdown(ctx context.Context) error {
+	var h3Err error
+	if s.h3 != nil {
+		h3Err = s.h3.Shutdown(ctx)
+	}
+	return errors.Join(s.server.Shutdown(ctx), h3Err)
+}
+
+// listen opens the framework's TCP listeners, using SO_REUSEPORT when more
+// than one is configured, plus its Unix socket if it has one.
+func listen(fw config.FrameworkConfig) ([]net.Listener, error) {
+	var listeners []net.Listener
+
+	if fw.Listeners <= 1 {
+		ln, err := net.Listen("tcp", fw.Addr())
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, ln)
+	} else {
+		lc := reusePortConfig()
+		for i := 0; i < fw.Listeners; i++ {
+			ln, err := lc.Listen(context.Background(), "tcp", fw.Addr())
+			if err != nil {
+				closeListeners(listeners)
+				return nil, fmt.Errorf("failed to open listener %d: %w", i+1, err)
+			}
+			listeners = append(listeners, ln)
+		}
+	}
+
+	if fw.Socket != "" {
+		ln, err := listenUnix(fw.Socket)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		listeners = append(listeners, ln)
+	}
+
+	return listeners, nil
+}
+
+// listenUnix listens on a Unix domain socket, replacing any stale socket
+// file left behind by a previous run.
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	return net.Listen("unix", path)
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, ln := range listeners {
+		ln.Close()
+	}
+}
@@ -2,33 +2,48 @@ package main
 
 import (
 	"bananas/internal/app"
+	"bananas/internal/certs"
+	"bananas/internal/config"
+	"bananas/internal/httpclient"
 	"bananas/internal/logger"
 	"context"
+	"errors"
+	"flag"
 	"net/http"
-	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/gorilla/mux"
-	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
 )
 
+// frameworkServer is the lifecycle shared by every framework server so main
+// can start and stop them uniformly.
+type frameworkServer interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+}
+
+// frameworkBuilders maps a config framework name to the function that builds its server.
+var frameworkBuilders = map[string]func(*app.App, config.FrameworkConfig) frameworkServer{
+	"standard": newStandardServer,
+	"gin":      newGinServer,
+	"fiber":    newFiberServer,
+	"echo":     newEchoServer,
+	"chi":      newChiServer,
+	"gorilla":  newGorillaServer,
+	"grpc":     newGRPCServer,
+	"connect":  newConnectServer,
+}
+
 func gracefulShutdown(
-	servers []*http.Server,
+	servers map[string]frameworkServer,
 	app *app.App,
 	done chan bool,
-	shutdownChan chan struct{},
 	log logger.Logger,
-	wg *sync.WaitGroup,
 ) {
 	log = log.Function("gracefulShutdown")
 
@@ -39,22 +54,20 @@ func gracefulShutdown(
 
 	log.Info("shutting down gracefully, press Ctrl+C again to force")
 
-	close(shutdownChan)
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var wgShutdown sync.WaitGroup
-	for i, server := range servers {
+	for name, server := range servers {
 		wgShutdown.Add(1)
-		go func(srv *http.Server, idx int) {
+		go func(srv frameworkServer, name string) {
 			defer wgShutdown.Done()
 			if err := srv.Shutdown(ctx); err != nil {
-				log.Er("Server %d forced to shutdown", err, idx)
+				log.Er("%s server forced to shutdown", err, name)
 			} else {
-				log.Info("Server %d shutdown gracefully", idx)
+				log.Info("%s server shutdown gracefully", name)
 			}
-		}(server, i)
+		}(server, name)
 	}
 	wgShutdown.Wait()
 
@@ -66,625 +79,149 @@ func gracefulShutdown(
 	done <- true
 }
 
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
 func main() {
+	framework := flag.String("framework", "", "serve only these frameworks (comma separated) in this process")
+	supervise := flag.Bool("supervise", false, "fork one child process per enabled framework")
+	procs := flag.Int("procs", 0, "GOMAXPROCS for each supervised child (0 = Go default)")
+	flag.Parse()
+
 	log := logger.New("main")
 
-	app, err := app.New()
+	cfg, err := config.New()
 	if err != nil {
-		log.Er("failed to initialize app", err)
+		log.Er("failed to initialize config", err)
 		os.Exit(1)
 	}
-	defer func() {
-		if err := app.Close(); err != nil {
-			log.Er("failed to close app", err)
-		}
-	}()
-
-	var servers []*http.Server
-	var wg sync.WaitGroup
-	shutdownChan := make(chan struct{})
-
-	// 1. Standard Library Server (Port 8081)
-	{
-		mux := http.NewServeMux()
-		
-		frameworkMiddleware := func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				ctx := context.WithValue(r.Context(), "framework", "standard")
-				next.ServeHTTP(w, r.WithContext(ctx))
-			})
-		}
 
-		mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("OK - Standard Library"))
-		})
-
-		mux.HandleFunc("/api/test/simple", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.Controllers.SimpleRequest(w, r.WithContext(ctx))
-		})
-
-		mux.HandleFunc("/api/test/database", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.Controllers.DatabaseQuery(w, r.WithContext(ctx))
-		})
-
-		mux.HandleFunc("/api/test/json", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.Controllers.JsonResponse(w, r.WithContext(ctx))
-		})
-
-		mux.HandleFunc("/api/info", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.Controllers.FrameworkInfo(w, r.WithContext(ctx))
-		})
-
-		mux.HandleFunc("/api/orders/recent", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.Controllers.GetRecentOrders(w, r.WithContext(ctx))
-		})
-
-		// Templ routes
-		mux.HandleFunc("/templ", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.TemplController.HomePage(w, r.WithContext(ctx))
-		})
-
-		mux.HandleFunc("/templ/run-test", func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), "framework", "standard")
-			app.TemplController.RunTest(w, r.WithContext(ctx))
-		})
-
-		server := &http.Server{
-			Addr:    ":8081",
-			Handler: corsMiddleware(frameworkMiddleware(mux)),
-		}
-		servers = append(servers, server)
-		
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			log.Info("Starting Standard Library server on port 8081")
-			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Er("Standard Library server failed to start", err)
+	if *framework != "" {
+		for _, name := range strings.Split(*framework, ",") {
+			fw, ok := cfg.Framework(strings.TrimSpace(name))
+			if !ok || !fw.Enabled {
+				log.Er("framework %s is unknown or not enabled", nil, name)
+				os.Exit(1)
 			}
-		}()
+			cfg.LocalFrameworks = append(cfg.LocalFrameworks, fw.Name)
+		}
 	}
 
-	// 2. Gin Server (Port 8082)
-	{
-		gin.SetMode(gin.ReleaseMode)
-		r := gin.New()
-		
-		r.Use(gin.Logger())
-		r.Use(gin.Recovery())
-
-		// CORS middleware
-		r.Use(func(c *gin.Context) {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-			c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-			if c.Request.Method == "OPTIONS" {
-				c.AbortWithStatus(http.StatusOK)
-				return
-			}
+	// Generate the self-signed certificate up front, before any supervised
+	// children or TLS listeners need it.
+	if err := ensureCertificates(cfg); err != nil {
+		log.Er("failed to prepare TLS certificate", err)
+		os.Exit(1)
+	}
 
-			c.Next()
-		})
-
-		r.Use(func(c *gin.Context) {
-			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "framework", "gin"))
-			c.Next()
-		})
-
-		r.GET("/health", func(c *gin.Context) {
-			c.String(http.StatusOK, "OK - Gin")
-		})
-
-		api := r.Group("/api")
-		{
-			test := api.Group("/test")
-			{
-				test.GET("/simple", func(c *gin.Context) {
-					app.Controllers.SimpleRequest(c.Writer, c.Request)
-				})
-				test.GET("/database", func(c *gin.Context) {
-					app.Controllers.DatabaseQuery(c.Writer, c.Request)
-				})
-				test.GET("/json", func(c *gin.Context) {
-					app.Controllers.JsonResponse(c.Writer, c.Request)
-				})
-			}
-			api.GET("/info", func(c *gin.Context) {
-				app.Controllers.FrameworkInfo(c.Writer, c.Request)
-			})
-			orders := api.Group("/orders")
-			{
-				orders.GET("/recent", func(c *gin.Context) {
-					app.Controllers.GetRecentOrders(c.Writer, c.Request)
-				})
-			}
-		}
+	// Fiber's prefork re-runs this binary for each child; those children are
+	// only there to serve Fiber's SO_REUSEPORT sockets.
+	if fiber.IsChild() {
+		cfg.LocalFrameworks = []string{"fiber"}
+	}
 
-		// Templ routes
-		r.GET("/templ", func(c *gin.Context) {
-			app.TemplController.HomePage(c.Writer, c.Request)
-		})
-		r.GET("/templ/run-test", func(c *gin.Context) {
-			app.TemplController.RunTest(c.Writer, c.Request)
-		})
-
-		server := &http.Server{
-			Addr:    ":8082",
-			Handler: r,
+	if *supervise {
+		if err := superviseFrameworks(cfg, *procs, log); err != nil {
+			log.Er("supervisor failed", err)
+			os.Exit(1)
 		}
-		servers = append(servers, server)
-		
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			log.Info("Starting Gin server on port 8082")
-			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Er("Gin server failed to start", err)
-			}
-		}()
+		return
 	}
 
-	// 3. Fiber Server (Port 8083)
-	{
-		fiberApp := fiber.New(fiber.Config{
-			ReadTimeout:  5 * time.Second,
-			WriteTimeout: 5 * time.Second,
-		})
-
-		fiberApp.Use(recover.New())
-
-		// CORS middleware
-		fiberApp.Use(func(c *fiber.Ctx) error {
-			c.Set("Access-Control-Allow-Origin", "*")
-			c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-			if c.Method() == "OPTIONS" {
-				return c.SendStatus(fiber.StatusOK)
-			}
-
-			return c.Next()
-		})
-
-		fiberApp.Use(func(c *fiber.Ctx) error {
-			c.Context().SetUserValue("framework", "fiber")
-			return c.Next()
-		})
-
-		fiberApp.Get("/health", func(c *fiber.Ctx) error {
-			return c.SendString("OK - Fiber")
-		})
-
-		api := fiberApp.Group("/api")
-		{
-			test := api.Group("/test")
-			{
-				test.Get("/simple", func(c *fiber.Ctx) error {
-					ctx := context.WithValue(context.Background(), "framework", "fiber")
-					writer := &fiberResponseWriter{ctx: c}
-
-					parsedURL, _ := url.Parse(c.OriginalURL())
-					req := &http.Request{
-						Method: c.Method(),
-						URL:    parsedURL,
-						Header: make(http.Header),
-					}
-					app.Controllers.SimpleRequest(writer, req.WithContext(ctx))
-					return nil
-				})
-				test.Get("/database", func(c *fiber.Ctx) error {
-					ctx := context.WithValue(context.Background(), "framework", "fiber")
-					writer := &fiberResponseWriter{ctx: c}
-
-					parsedURL, _ := url.Parse(c.OriginalURL())
-					req := &http.Request{
-						Method: c.Method(),
-						URL:    parsedURL,
-						Header: make(http.Header),
-					}
-					app.Controllers.DatabaseQuery(writer, req.WithContext(ctx))
-					return nil
-				})
-				test.Get("/json", func(c *fiber.Ctx) error {
-					ctx := context.WithValue(context.Background(), "framework", "fiber")
-					writer := &fiberResponseWriter{ctx: c}
-
-					parsedURL, _ := url.Parse(c.OriginalURL())
-					req := &http.Request{
-						Method: c.Method(),
-						URL:    parsedURL,
-						Header: make(http.Header),
-					}
-					app.Controllers.JsonResponse(writer, req.WithContext(ctx))
-					return nil
-				})
-			}
-			api.Get("/info", func(c *fiber.Ctx) error {
-				ctx := context.WithValue(context.Background(), "framework", "fiber")
-				writer := &fiberResponseWriter{ctx: c}
-
-				parsedURL, _ := url.Parse(c.OriginalURL())
-				req := &http.Request{
-					Method: c.Method(),
-					URL:    parsedURL,
-					Header: make(http.Header),
-				}
-				app.Controllers.FrameworkInfo(writer, req.WithContext(ctx))
-				return nil
-			})
-			orders := api.Group("/orders")
-			{
-				orders.Get("/recent", func(c *fiber.Ctx) error {
-					ctx := context.WithValue(context.Background(), "framework", "fiber")
-					writer := &fiberResponseWriter{ctx: c}
-
-					parsedURL, _ := url.Parse(c.OriginalURL())
-					req := &http.Request{
-						Method: c.Method(),
-						URL:    parsedURL,
-						Header: make(http.Header),
-					}
-					app.Controllers.GetRecentOrders(writer, req.WithContext(ctx))
-					return nil
-				})
-			}
+	app, err := app.NewWithConfig(cfg)
+	if err != nil {
+		log.Er("failed to initialize app", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := app.Close(); err != nil {
+			log.Er("failed to close app", err)
 		}
+	}()
 
-		// Templ routes
-		fiberApp.Get("/templ", func(c *fiber.Ctx) error {
-			ctx := context.WithValue(context.Background(), "framework", "fiber")
-			writer := &fiberResponseWriter{ctx: c}
-
-			parsedURL, _ := url.Parse(c.OriginalURL())
-			req := &http.Request{
-				Method: c.Method(),
-				URL:    parsedURL,
-				Header: make(http.Header),
-			}
-			app.TemplController.HomePage(writer, req.WithContext(ctx))
-			return nil
-		})
-
-		fiberApp.Get("/templ/run-test", func(c *fiber.Ctx) error {
-			ctx := context.WithValue(context.Background(), "framework", "fiber")
-			writer := &fiberResponseWriter{ctx: c}
-
-			parsedURL, _ := url.Parse(c.OriginalURL())
-			req := &http.Request{
-				Method: c.Method(),
-				URL:    parsedURL,
-				Header: make(http.Header),
-			}
-			app.TemplController.RunTest(writer, req.WithContext(ctx))
-			return nil
-		})
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			log.Info("Starting Fiber server on port 8083")
-			if err := fiberApp.Listen(":8083"); err != nil {
-				log.Er("Fiber server failed to start", err)
-			}
-		}()
-
-		go func() {
-			<-shutdownChan
-			log.Info("Shutting down Fiber server...")
-			if err := fiberApp.Shutdown(); err != nil {
-				log.Er("Fiber server shutdown error", err)
-			}
-		}()
+	frameworks := app.Config.ServedFrameworks()
+	if len(frameworks) == 0 {
+		log.Er("no frameworks enabled, check FRAMEWORKS", nil)
+		os.Exit(1)
 	}
+	log.Info("Isolation mode: %s", app.Config.IsolationMode())
 
-	// 4. Echo Server (Port 8084)
-	{
-		e := echo.New()
-		e.HideBanner = true
-		e.Use(echomiddleware.Logger())
-		e.Use(echomiddleware.Recover())
-		e.Use(echomiddleware.CORS())
-		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
-			return func(c echo.Context) error {
-				c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), "framework", "echo")))
-				return next(c)
-			}
-		})
-
-		e.GET("/health", func(c echo.Context) error {
-			return c.String(http.StatusOK, "OK - Echo")
-		})
-
-		api := e.Group("/api")
-		{
-			test := api.Group("/test")
-			{
-				test.GET("/simple", func(c echo.Context) error {
-					app.Controllers.SimpleRequest(c.Response(), c.Request())
-					return nil
-				})
-				test.GET("/database", func(c echo.Context) error {
-					app.Controllers.DatabaseQuery(c.Response(), c.Request())
-					return nil
-				})
-				test.GET("/json", func(c echo.Context) error {
-					app.Controllers.JsonResponse(c.Response(), c.Request())
-					return nil
-				})
-			}
-			api.GET("/info", func(c echo.Context) error {
-				app.Controllers.FrameworkInfo(c.Response(), c.Request())
-				return nil
-			})
-			orders := api.Group("/orders")
-			{
-				orders.GET("/recent", func(c echo.Context) error {
-					app.Controllers.GetRecentOrders(c.Response(), c.Request())
-					return nil
-				})
-			}
-		}
+	servers := make(map[string]frameworkServer, len(frameworks))
+	var wg sync.WaitGroup
 
-		// Templ routes
-		e.GET("/templ", func(c echo.Context) error {
-			app.TemplController.HomePage(c.Response(), c.Request())
-			return nil
-		})
-		e.GET("/templ/run-test", func(c echo.Context) error {
-			app.TemplController.RunTest(c.Response(), c.Request())
-			return nil
-		})
-
-		server := &http.Server{
-			Addr:    ":8084",
-			Handler: e,
+	for _, fw := range frameworks {
+		build, ok := frameworkBuilders[fw.Name]
+		if !ok {
+			log.Er("no server builder for framework %s", nil, fw.Name)
+			continue
 		}
-		servers = append(servers, server)
-		
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			log.Info("Starting Echo server on port 8084")
-			e.Start(":8084")
-		}()
-	}
 
-	// 5. Chi Server (Port 8085)
-	{
-		r := chi.NewRouter()
-		r.Use(middleware.Logger)
-		r.Use(middleware.Recoverer)
-		r.Use(middleware.RequestID)
-		r.Use(middleware.RealIP)
-
-		// CORS middleware
-		r.Use(func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Access-Control-Allow-Origin", "*")
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-				if r.Method == "OPTIONS" {
-					w.WriteHeader(http.StatusOK)
-					return
-				}
-
-				next.ServeHTTP(w, r)
-			})
-		})
-
-		r.Use(func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				ctx := context.WithValue(r.Context(), "framework", "chi")
-				next.ServeHTTP(w, r.WithContext(ctx))
-			})
-		})
-
-		r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("OK - Chi"))
-		})
-
-		r.Route("/api", func(r chi.Router) {
-			r.Route("/test", func(r chi.Router) {
-				r.Get("/simple", func(w http.ResponseWriter, r *http.Request) {
-					app.Controllers.SimpleRequest(w, r)
-				})
-				r.Get("/database", func(w http.ResponseWriter, r *http.Request) {
-					app.Controllers.DatabaseQuery(w, r)
-				})
-				r.Get("/json", func(w http.ResponseWriter, r *http.Request) {
-					app.Controllers.JsonResponse(w, r)
-				})
-			})
-			r.Get("/info", func(w http.ResponseWriter, r *http.Request) {
-				app.Controllers.FrameworkInfo(w, r)
-			})
-			r.Route("/orders", func(r chi.Router) {
-				r.Get("/recent", func(w http.ResponseWriter, r *http.Request) {
-					app.Controllers.GetRecentOrders(w, r)
-				})
-			})
-		})
-
-		// Templ routes
-		r.Get("/templ", func(w http.ResponseWriter, r *http.Request) {
-			app.TemplController.HomePage(w, r)
-		})
-		r.Get("/templ/run-test", func(w http.ResponseWriter, r *http.Request) {
-			app.TemplController.RunTest(w, r)
-		})
-
-		server := &http.Server{
-			Addr:    ":8085",
-			Handler: r,
-		}
-		servers = append(servers, server)
-		
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			log.Info("Starting Chi server on port 8085")
-			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Er("Chi server failed to start", err)
-			}
-		}()
-	}
+		server := build(app, fw)
+		servers[fw.Name] = server
 
-	// 6. Gorilla Mux Server (Port 8086)
-	{
-		r := mux.NewRouter()
-
-		// CORS middleware
-		r.Use(func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.Header().Set("Access-Control-Allow-Origin", "*")
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-				if r.Method == "OPTIONS" {
-					w.WriteHeader(http.StatusOK)
-					return
-				}
-
-				next.ServeHTTP(w, r)
-			})
-		})
-
-		r.Use(func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				ctx := context.WithValue(r.Context(), "framework", "gorilla")
-				next.ServeHTTP(w, r.WithContext(ctx))
-			})
-		})
-
-		r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("OK - Gorilla Mux"))
-		}).Methods("GET")
-
-		api := r.PathPrefix("/api").Subrouter()
-		api.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
-			app.Controllers.FrameworkInfo(w, r)
-		}).Methods("GET")
-
-		test := api.PathPrefix("/test").Subrouter()
-		test.HandleFunc("/simple", func(w http.ResponseWriter, r *http.Request) {
-			app.Controllers.SimpleRequest(w, r)
-		}).Methods("GET")
-
-		test.HandleFunc("/database", func(w http.ResponseWriter, r *http.Request) {
-			app.Controllers.DatabaseQuery(w, r)
-		}).Methods("GET")
-
-		test.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
-			app.Controllers.JsonResponse(w, r)
-		}).Methods("GET")
-
-		orders := api.PathPrefix("/orders").Subrouter()
-		orders.HandleFunc("/recent", func(w http.ResponseWriter, r *http.Request) {
-			app.Controllers.GetRecentOrders(w, r)
-		}).Methods("GET")
-
-		// Templ routes
-		r.HandleFunc("/templ", func(w http.ResponseWriter, r *http.Request) {
-			app.TemplController.HomePage(w, r)
-		}).Methods("GET")
-		r.HandleFunc("/templ/run-test", func(w http.ResponseWriter, r *http.Request) {
-			app.TemplController.RunTest(w, r)
-		}).Methods("GET")
-
-		server := &http.Server{
-			Addr:    ":8086",
-			Handler: r,
-		}
-		servers = append(servers, server)
-		
 		wg.Add(1)
-		go func() {
+		go func(fw config.FrameworkConfig, server frameworkServer) {
 			defer wg.Done()
-			log.Info("Starting Gorilla Mux server on port 8086")
-			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Er("Gorilla Mux server failed to start", err)
+			log.Info("Starting %s server on %s (%s)", fw.DisplayName, fw.Addr(), fw.Protocol)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Er("%s server failed to start", err, fw.DisplayName)
 			}
-		}()
+		}(fw, server)
 	}
 
 	// Wait for all servers to be ready
 	log.Info("All frameworks are starting up...")
-	log.Info("Standard Library: http://localhost:8081")
-	log.Info("Gin: http://localhost:8082")
-	log.Info("Fiber: http://localhost:8083")
-	log.Info("Echo: http://localhost:8084")
-	log.Info("Chi: http://localhost:8085")
-	log.Info("Gorilla Mux: http://localhost:8086")
+	for _, fw := range frameworks {
+		log.Info("%s: %s", fw.DisplayName, fw.BaseURL())
+	}
 
 	// Health check verification
-	time.Sleep(200 * time.Millisecond)
-	healthCheckServers(log)
+	if !fiber.IsChild() {
+		time.Sleep(200 * time.Millisecond)
+		healthCheckServers(frameworks, log)
+	}
 
 	done := make(chan bool, 1)
-	go gracefulShutdown(servers, app, done, shutdownChan, log, &wg)
+	go gracefulShutdown(servers, app, done, log)
 
 	<-done
 	log.Info("Graceful shutdown complete.")
 	wg.Wait()
 }
 
-func healthCheckServers(log logger.Logger) {
+func healthCheckServers(frameworks []config.FrameworkConfig, log logger.Logger) {
 	log.Info("Performing health checks on all servers...")
 
-	servers := []struct {
-		name string
-		url  string
-	}{
-		{"Standard Library", "http://localhost:8081/health"},
-		{"Gin", "http://localhost:8082/health"},
-		{"Fiber", "http://localhost:8083/health"},
-		{"Echo", "http://localhost:8084/health"},
-		{"Chi", "http://localhost:8085/health"},
-		{"Gorilla Mux", "http://localhost:8086/health"},
-	}
-
-	client := &http.Client{Timeout: 2 * time.Second}
 	allHealthy := true
 
-	for _, server := range servers {
-		resp, err := client.Get(server.url)
+	for _, fw := range frameworks {
+		if fw.Name == "grpc" {
+			serving, err := grpcHealthCheck(fw, 2*time.Second)
+			if err != nil || !serving {
+				log.Er("Health check failed for %s", err, fw.DisplayName)
+				allHealthy = false
+			} else {
+				log.Info("✓ %s is healthy", fw.DisplayName)
+			}
+			continue
+		}
+
+		client, err := httpclient.ForFramework(fw, 2*time.Second)
 		if err != nil {
-			log.Er("Health check failed for %s", err, server.name)
+			log.Er("Health check failed for %s", err, fw.DisplayName)
 			allHealthy = false
 			continue
 		}
-		defer resp.Body.Close()
+
+		resp, err := client.Get(fw.BaseURL() + "/health")
+		if err != nil {
+			log.Er("Health check failed for %s", err, fw.DisplayName)
+			allHealthy = false
+			continue
+		}
+		resp.Body.Close()
 
 		if resp.StatusCode == http.StatusOK {
-			log.Info("✓ %s is healthy", server.name)
+			log.Info("✓ %s is healthy", fw.DisplayName)
 		} else {
-			log.Er("Health check failed for %s with status %d", nil, server.name, resp.StatusCode)
+			log.Er("Health check failed for %s with status %d", nil, fw.DisplayName, resp.StatusCode)
 			allHealthy = false
 		}
 	}
@@ -696,27 +233,13 @@ func healthCheckServers(log logger.Logger) {
 	}
 }
 
-// fiberResponseWriter adapts Fiber's Ctx to http.ResponseWriter
-type fiberResponseWriter struct {
-	ctx *fiber.Ctx
-}
-
-func (w *fiberResponseWriter) Header() http.Header {
-	header := make(http.Header)
-	w.ctx.Response().Header.VisitAll(func(key, value []byte) {
-		header.Add(string(key), string(value))
-	})
-	return header
-}
-
-func (w *fiberResponseWriter) Write(data []byte) (int, error) {
-	err := w.ctx.Send(data)
-	if err != nil {
-		return 0, err
+// ensureCertificates creates the shared certificate if any served framework
+// listens over TLS.
+func ensureCertificates(cfg config.Config) error {
+	for _, fw := range cfg.ServedFrameworks() {
+		if fw.UsesTLS() {
+			return certs.Ensure(fw.TLS)
+		}
 	}
-	return len(data), nil
+	return nil
 }
-
-func (w *fiberResponseWriter) WriteHeader(statusCode int) {
-	w.ctx.Status(statusCode)
-}
\ No newline at end of file
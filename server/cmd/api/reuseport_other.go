@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import "net"
+
+// reusePortConfig falls back to a plain listener where SO_REUSEPORT isn't
+// available, so extra listeners fail to bind instead of silently sharing.
+func reusePortConfig() net.ListenConfig {
+	return net.ListenConfig{}
+}
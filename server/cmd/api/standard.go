@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bananas/internal/app"
+	"bananas/internal/config"
+	"bananas/internal/middleware"
+	"bananas/internal/problem"
+	"bananas/internal/ws"
+	"context"
+	"net/http"
+	"strings"
+)
+
+// newStandardServer builds the net/http ServeMux server.
+func newStandardServer(app *app.App, fw config.FrameworkConfig) frameworkServer {
+	mux := http.NewServeMux()
+
+	frameworkMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), "framework", "standard")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK - Standard Library"))
+	})
+
+	mux.HandleFunc("GET /api/test/simple", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.SimpleRequest(w, r)
+	})
+
+	mux.HandleFunc("GET /api/test/database", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.DatabaseQuery(w, r)
+	})
+
+	mux.HandleFunc("GET /api/test/json", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.JsonResponse(w, r)
+	})
+
+	mux.HandleFunc("POST /api/test/validate", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.ValidateOrder(w, r)
+	})
+
+	mux.HandleFunc("GET /api/info", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.FrameworkInfo(w, r)
+	})
+
+	mux.HandleFunc("GET /api/orders/recent", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GetRecentOrders(w, r)
+	})
+
+	mux.HandleFunc("POST /api/auth/register", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.Register(w, r)
+	})
+
+	mux.HandleFunc("POST /api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.Login(w, r)
+	})
+
+	// The recent orders again, behind JWT verification.
+	mux.Handle("GET /api/auth/orders/recent", middleware.RequireAuth(app.Controllers.Tokens)(
+		http.HandlerFunc(app.Controllers.GetRecentOrders)))
+
+	mux.HandleFunc("GET /api/orders/stream", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.StreamOrders(w, r)
+	})
+
+	mux.HandleFunc("POST /api/files/upload", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.UploadMultipart(w, r)
+	})
+
+	mux.HandleFunc("POST /api/files/raw", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.UploadRaw(w, r)
+	})
+
+	mux.HandleFunc("GET /api/files/download", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.DownloadFile(w, r)
+	})
+
+	mux.HandleFunc("GET /graphql", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GraphQL(w, r)
+	})
+
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		app.Controllers.GraphQL(w, r)
+	})
+
+	// WebSocket routes
+	hub := ws.NewHub()
+	mux.Handle("GET /ws/echo", ws.NewCoderHandler(ws.Echo))
+	mux.Handle("GET /ws/broadcast", ws.NewCoderHandler(hub.Serve))
+
+	// Templ routes
+	mux.HandleFunc("GET /templ", func(w http.ResponseWriter, r *http.Request) {
+		app.TemplController.HomePage(w, r)
+	})
+
+	mux.HandleFunc("GET /templ/run-test", func(w http.ResponseWriter, r *http.Request) {
+		app.TemplController.RunTest(w, r)
+	})
+
+	// ServeMux answers unmatched requests in plain text, so catch them here
+	// to answer with a problem like everything else.
+	mux.HandleFunc("/", standardNotFound(mux))
+
+	handler := middleware.Chain(frameworkMiddleware(mux), middleware.Stack(fw.Name, fw.Middleware)...)
+	return newHTTPServer(fw, handler)
+}
+
+// standardNotFound answers the requests no route matched. ServeMux sends a
+// request whose method no route accepts to the catch-all rather than
+// answering 405, so this tries the request's path with other methods and
+// answers 405, with the Allow header ServeMux would send, when one matches.
+func standardNotFound(mux *http.ServeMux) http.HandlerFunc {
+	methods := []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range methods {
+			probe := *r
+			probe.Method = method
+			if _, pattern := mux.Handler(&probe); pattern != "/" {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) == 0 {
+			problem.NotFoundHandler(w, r)
+			return
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		problem.MethodNotAllowedHandler(w, r)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bananas/internal/config"
+	"bananas/internal/logger"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// superviseFrameworks re-executes this binary once per enabled framework with
+// --framework set, so every framework gets its own runtime, GOMAXPROCS budget
+// and database pools. It forwards SIGINT/SIGTERM to the children and returns
+// once they have all exited.
+func superviseFrameworks(cfg config.Config, procs int, log logger.Logger) error {
+	log = log.Function("superviseFrameworks")
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	frameworks := cfg.ServedFrameworks()
+	if len(frameworks) == 0 {
+		return fmt.Errorf("no frameworks enabled, check FRAMEWORKS")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	children := make([]*exec.Cmd, 0, len(frameworks))
+	var wg sync.WaitGroup
+
+	for _, fw := range frameworks {
+		cmd := exec.Command(executable, "--framework="+fw.Name)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = os.Environ()
+		if procs > 0 {
+			cmd.Env = append(cmd.Env, "GOMAXPROCS="+strconv.Itoa(procs))
+		}
+
+		if err := cmd.Start(); err != nil {
+			stopChildren(children, log)
+			return fmt.Errorf("failed to start %s: %w", fw.Name, err)
+		}
+		log.Info("Started %s in process %d", fw.DisplayName, cmd.Process.Pid)
+		children = append(children, cmd)
+
+		wg.Add(1)
+		go func(fw config.FrameworkConfig, cmd *exec.Cmd) {
+			defer wg.Done()
+			if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+				log.Er("%s process exited unexpectedly", err, fw.DisplayName)
+			}
+		}(fw, cmd)
+	}
+
+	<-ctx.Done()
+	log.Info("Stopping %d framework processes", len(children))
+	stopChildren(children, log)
+
+	exited := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+		log.Info("All framework processes stopped")
+	case <-time.After(15 * time.Second):
+		log.Info("Framework processes did not stop in time, killing")
+		for _, cmd := range children {
+			cmd.Process.Kill()
+		}
+		<-exited
+	}
+
+	return nil
+}
+
+func stopChildren(children []*exec.Cmd, log logger.Logger) {
+	for _, cmd := range children {
+		if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+			log.Er("failed to signal process %d", err, cmd.Process.Pid)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// The user -auth runs sign in as. Registering it again on a later run is
+// answered with a 409, which signIn accepts.
+const (
+	benchEmail    = "bench@bananas.local"
+	benchPassword = "bench-password"
+)
+
+// protectedRecentOrders is /api/orders/recent behind JWT verification.
+const protectedRecentOrders = "/api/auth/orders/recent"
+
+// loginLoad returns the load for -auth login, where every call is a login and
+// so costs a password check and a token signature.
+func loginLoad(client *http.Client, baseURL, accept string) (load, error) {
+	if _, err := signIn(client, baseURL); err != nil {
+		return load{}, err
+	}
+	body, err := credentials()
+	if err != nil {
+		return load{}, err
+	}
+
+	url := baseURL + "/api/auth/login"
+	return load{url: url, newCaller: func() caller {
+		return postCaller(client, url, accept, body, http.StatusOK)
+	}}, nil
+}
+
+// signIn registers the bench user if it does not exist yet and logs in,
+// returning the token.
+func signIn(client *http.Client, baseURL string) (string, error) {
+	body, err := credentials()
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Post(baseURL+"/api/auth/register", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusConflict {
+		return "", fmt.Errorf("register answered %s", resp.Status)
+	}
+
+	resp, err = client.Post(baseURL+"/api/auth/login", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("login answered %s", resp.Status)
+	}
+	var token struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	return token.Token, nil
+}
+
+func credentials() ([]byte, error) {
+	return json.Marshal(map[string]string{"email": benchEmail, "password": benchPassword})
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bananas/internal/payload"
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// fileOptions configures a file transfer run against /api/files.
+type fileOptions struct {
+	Kind       string // upload (multipart), raw or download
+	Size       int64
+	Mode       string // how the server stores uploads: stream or memory
+	Range      string // Range header sent with downloads
+	Revalidate bool   // send the payload's ETag in If-None-Match
+}
+
+// fileLoad returns the load for a file transfer run. Payloads are generated
+// as they are sent, so the client holds no copy of them. For uploads the
+// recorded size is the payload sent rather than the small JSON reply.
+func fileLoad(client *http.Client, baseURL string, opts fileOptions) (load, error) {
+	switch opts.Kind {
+	case "upload":
+		url := withQuery(baseURL+"/api/files/upload", "mode", opts.Mode)
+		prefix, suffix, contentType, err := multipartFraming()
+		if err != nil {
+			return load{}, err
+		}
+		return load{url: url, newCaller: func() caller {
+			length := int64(len(prefix)) + opts.Size + int64(len(suffix))
+			return uploadCaller(client, url, contentType, length, opts.Size, func() io.Reader {
+				return io.MultiReader(bytes.NewReader(prefix), payload.NewReader(opts.Size), bytes.NewReader(suffix))
+			})
+		}}, nil
+
+	case "raw":
+		url := withQuery(baseURL+"/api/files/raw", "mode", opts.Mode)
+		return load{url: url, newCaller: func() caller {
+			return uploadCaller(client, url, "application/octet-stream", opts.Size, opts.Size, func() io.Reader {
+				return payload.NewReader(opts.Size)
+			})
+		}}, nil
+
+	case "download":
+		url := withQuery(baseURL+"/api/files/download", "size", payload.FormatSize(opts.Size))
+		base, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return load{}, err
+		}
+		if opts.Range != "" {
+			base.Header.Set("Range", opts.Range)
+		}
+		if opts.Revalidate {
+			base.Header.Set("If-None-Match", payload.ETag(opts.Size))
+		}
+		return load{url: url, newCaller: func() caller {
+			req := base.Clone(base.Context())
+			return func() (response, error) {
+				resp, err := client.Do(req)
+				if err != nil {
+					return response{}, err
+				}
+				n, err := io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				return response{
+					proto:  resp.Proto,
+					timing: resp.Header.Get("Server-Timing"),
+					size:   int(n),
+					failed: err != nil || resp.StatusCode >= 400,
+				}, nil
+			}
+		}}, nil
+	}
+	return load{}, fmt.Errorf("unknown file transfer %q, use upload, raw or download", opts.Kind)
+}
+
+// uploadCaller posts a fresh body from newBody on every call, since a
+// request body can only be read once. size is the payload within the body.
+func uploadCaller(client *http.Client, url, contentType string, contentLength, size int64, newBody func() io.Reader) caller {
+	return func() (response, error) {
+		req, err := http.NewRequest(http.MethodPost, url, newBody())
+		if err != nil {
+			return response{}, err
+		}
+		req.ContentLength = contentLength
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return response{}, err
+		}
+		_, err = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return response{
+			proto:  resp.Proto,
+			timing: resp.Header.Get("Server-Timing"),
+			size:   int(size),
+			failed: err != nil || resp.StatusCode >= 400,
+		}, nil
+	}
+}
+
+// multipartFraming returns the bytes a multipart/form-data body puts before
+// and after a single file part, and the matching Content-Type, so the file
+// itself can be streamed between them.
+func multipartFraming() (prefix, suffix []byte, contentType string, err error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if _, err := writer.CreateFormFile("file", "payload.bin"); err != nil {
+		return nil, nil, "", err
+	}
+	prefix = bytes.Clone(buf.Bytes())
+	buf.Reset()
+	if err := writer.Close(); err != nil {
+		return nil, nil, "", err
+	}
+	return prefix, bytes.Clone(buf.Bytes()), writer.FormDataContentType(), nil
+}
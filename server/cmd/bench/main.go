@@ -0,0 +1,807 @@
+package main
+
+import (
+	"bananas/internal/codec"
+	"bananas/internal/config"
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/httpclient"
+	"bananas/internal/logger"
+	"bananas/internal/payload"
+	"bananas/internal/problem"
+	"bananas/internal/validation"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Result summarises one load run against a single framework.
+type Result struct {
+	Framework  string        `json:"framework"`
+	URL        string        `json:"url"`
+	Isolation  string        `json:"isolation"`
+	GOMAXPROCS int           `json:"gomaxprocs"`
+	Middleware string        `json:"middleware"`
+	Protocol   string        `json:"protocol"`
+	Transport  string        `json:"transport"` // tcp or unix
+	Proto      string        `json:"proto"`     // as negotiated, e.g. HTTP/2.0
+	Requests   int64         `json:"requests"`
+	Errors     int64         `json:"errors"`
+	Duration   time.Duration `json:"duration"`
+	RPS        float64       `json:"rps"`
+	LatencyAvg time.Duration `json:"latencyAvg"`
+	LatencyP50 time.Duration `json:"latencyP50"`
+	LatencyP90 time.Duration `json:"latencyP90"`
+	LatencyP99 time.Duration `json:"latencyP99"`
+	LatencyMax time.Duration `json:"latencyMax"`
+
+	// ServerTiming averages each Server-Timing metric the server reported,
+	// e.g. "db" and "encode", over the responses that carried it.
+	ServerTiming map[string]time.Duration `json:"serverTiming,omitempty"`
+	Codec        string                   `json:"codec,omitempty"`
+
+	// Format is the response media type requested via Accept. BytesAvg is
+	// the mean body size as received, after any transfer compression is
+	// undone, and DecodeAvg the mean client-side decode time with -decode.
+	Format    string        `json:"format"`
+	BytesAvg  int64         `json:"bytesAvg"`
+	DecodeAvg time.Duration `json:"decodeAvg,omitempty"`
+
+	// Throughput is the body bytes moved per second in MB/s (MB meaning
+	// 1<<20 bytes): received bodies, or the payloads sent by -file uploads.
+	Throughput float64 `json:"throughput"`
+
+	// PeakHeap and PeakRSS are the highest server heap in use and resident
+	// set size seen with -mem or -file, sampled from /api/info while the
+	// run was in progress. RSS is only reported by Linux servers.
+	PeakHeap uint64 `json:"peakHeap,omitempty"`
+	PeakRSS  uint64 `json:"peakRSS,omitempty"`
+}
+
+// formats maps -format names to the media type sent in Accept.
+var formats = map[string]string{
+	"json":     codec.MediaJSON,
+	"msgpack":  codec.MediaMsgpack,
+	"cbor":     codec.MediaCBOR,
+	"protobuf": codec.MediaProtobuf,
+	"sse":      codec.MediaEventStream,
+	"ndjson":   codec.MediaNDJSON,
+}
+
+// protoMessages gives the message each endpoint returns, so Protobuf bodies
+// can be decoded; the other formats decode into a generic value.
+var protoMessages = map[string]func() proto.Message{
+	"/api/test/simple":    func() proto.Message { return &bananasv1.SimpleResponse{} },
+	"/api/test/json":      func() proto.Message { return &bananasv1.JsonResponse{} },
+	"/api/test/database":  func() proto.Message { return &bananasv1.DatabaseQueryResponse{} },
+	"/api/orders/recent":  func() proto.Message { return &bananasv1.RecentOrdersResponse{} },
+	protectedRecentOrders: func() proto.Message { return &bananasv1.RecentOrdersResponse{} },
+}
+
+// recentOrdersQuery asks /graphql for what /api/orders/recent returns, so
+// -graphql recent compares the two like for like.
+const recentOrdersQuery = `{
+  orders(limit: 100) {
+    id orderNumber orderDate status subtotal tax shipping total notes
+    customer { id firstName lastName email phone }
+    items {
+      id quantity unitPrice discount tax total
+      product { id sku name description weight dimensions isActive }
+    }
+  }
+}`
+
+func main() {
+	frameworks := flag.String("frameworks", "", "comma separated frameworks to benchmark (default: all enabled in config)")
+	endpoint := flag.String("endpoint", "/api/test/simple", "path and query to request")
+	concurrency := flag.Int("c", 50, "concurrent workers, or connections with -ws")
+	duration := flag.Duration("d", 10*time.Second, "duration per framework")
+	warmup := flag.Duration("warmup", time.Second, "warmup duration per framework, not measured")
+	out := flag.String("out", "", "write results as JSON to this file")
+	uds := flag.Bool("uds", false, "connect over each framework's unix socket instead of TCP")
+	jsonCodec := flag.String("codec", "", "JSON codec to request with ?codec= (default: the server's own)")
+	protocol := flag.String("proto", "", "protocol to speak: http1, https, h2, h2c or h3 (default: each framework's configured protocol)")
+	format := flag.String("format", "json", "response format to request via Accept: json, msgpack, cbor, protobuf, or sse or ndjson for /api/orders/stream")
+	decode := flag.Bool("decode", false, "decode every response body and report the client-side decode time (HTTP targets only)")
+	mem := flag.Bool("mem", false, "sample the server's heap and RSS from /api/info during each run and report their peaks")
+	fileKind := flag.String("file", "", "benchmark file transfers instead: upload (multipart), raw or download")
+	fileSize := flag.String("size", "1MB", "payload size for -file, from 1KB to 1GB")
+	uploadMode := flag.String("upload-mode", "stream", "how the server stores -file uploads: stream to disk, or buffer in memory first")
+	fileRange := flag.String("range", "", "Range header for -file download, e.g. bytes=0-1023")
+	revalidate := flag.Bool("revalidate", false, "send the payload's ETag in If-None-Match with -file download, so responses are 304s")
+	rpcWire := flag.String("rpc", "connect", "wire protocol for the connect target: connect, grpc or grpcweb")
+	wsMode := flag.String("ws", "", "benchmark WebSockets instead: echo or broadcast, with -c connections")
+	wsDial := flag.Int("ws-dial", 100, "WebSocket handshakes in flight while connecting")
+	wsSize := flag.Int("ws-size", 64, "WebSocket message size in bytes")
+	wsSenders := flag.Int("ws-senders", 10, "connections sending in broadcast mode")
+	wsInterval := flag.Duration("ws-interval", 100*time.Millisecond, "time between messages from each broadcast sender")
+	graphqlQuery := flag.String("graphql", "", "GraphQL query to send to /graphql instead of -endpoint, or recent for the equivalent of /api/orders/recent")
+	validateMode := flag.String("validate", "", "benchmark request validation instead: post an order to /api/test/validate in native or adapted mode")
+	invalid := flag.Bool("invalid", false, "make the -validate order break its rules, so every response is a 422")
+	expect := flag.Int("expect", 0, "status every response to -endpoint should have, such as 404 to benchmark an error path, where the body must also be a problem (default: any status below 400)")
+	authMode := flag.String("auth", "", "benchmark authentication instead: login, or recent for /api/orders/recent behind JWT verification")
+	flag.Parse()
+
+	log := logger.New("bench")
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Er("failed to initialize config", err)
+		os.Exit(1)
+	}
+
+	if *wsMode != "" && *wsMode != "echo" && *wsMode != "broadcast" {
+		log.Er("unknown WebSocket mode %q, use echo or broadcast", nil, *wsMode)
+		os.Exit(1)
+	}
+
+	var files fileOptions
+	if *fileKind != "" {
+		size, err := payload.ParseSize(*fileSize)
+		if err != nil {
+			log.Er("invalid -size", err)
+			os.Exit(1)
+		}
+		if *uploadMode != "stream" && *uploadMode != "memory" {
+			log.Er("unknown upload mode %q, use stream or memory", nil, *uploadMode)
+			os.Exit(1)
+		}
+		files = fileOptions{Kind: *fileKind, Size: size, Mode: *uploadMode, Range: *fileRange, Revalidate: *revalidate}
+	}
+
+	if *graphqlQuery != "" {
+		query := *graphqlQuery
+		if query == "recent" {
+			query = recentOrdersQuery
+		}
+		*endpoint = withQuery("/graphql", "query", query)
+	}
+
+	if *validateMode != "" && *validateMode != validation.ModeNative && *validateMode != validation.ModeAdapted {
+		log.Er("unknown validation mode %q, use native or adapted", nil, *validateMode)
+		os.Exit(1)
+	}
+
+	switch *authMode {
+	case "", "login":
+	case "recent":
+		*endpoint = protectedRecentOrders
+	default:
+		log.Er("unknown auth mode %q, use login or recent", nil, *authMode)
+		os.Exit(1)
+	}
+
+	mediaType, ok := formats[*format]
+	if !ok {
+		log.Er("unknown format %q, use json, msgpack, cbor, protobuf, sse or ndjson", nil, *format)
+		os.Exit(1)
+	}
+	decoder, err := newDecoder(mediaType, *endpoint)
+	if err != nil {
+		log.Er("cannot decode %s responses", err, *format)
+		os.Exit(1)
+	}
+	if !*decode {
+		decoder = nil
+	}
+
+	targets, err := selectTargets(cfg, *frameworks)
+	if err != nil {
+		log.Er("failed to select frameworks", err)
+		os.Exit(1)
+	}
+
+	// Frameworks run one after another so they never compete for CPU.
+	results := make([]Result, 0, len(targets))
+	var wsResults []WSResult
+	for _, fw := range targets {
+		if *protocol != "" {
+			fw.Protocol = *protocol
+		}
+
+		opts := httpclient.Options{
+			Protocol: fw.Protocol,
+			TLS:      fw.TLS,
+			MaxConns: *concurrency,
+			Timeout:  10 * time.Second,
+		}
+		if *fileKind != "" {
+			// A large transfer may take far longer than an API call.
+			opts.Timeout += *duration
+		}
+		transport := "tcp"
+		if *uds {
+			if fw.Socket == "" {
+				log.Er("%s has no unix socket, set SOCKET_DIR or %s_SOCKET", nil, fw.Name, strings.ToUpper(fw.Name))
+				os.Exit(1)
+			}
+			opts.Socket = fw.Socket
+			transport = "unix"
+		}
+
+		client, err := httpclient.New(opts)
+		if err != nil {
+			log.Er("failed to create client for %s", err, fw.Name)
+			os.Exit(1)
+		}
+
+		if *wsMode != "" {
+			if fw.IsRPC() {
+				log.Info("Skipping %s, it serves no WebSocket endpoints", fw.DisplayName)
+				continue
+			}
+			log.Info("Benchmarking %s WebSocket %s over %s/%s (connections=%d, d=%s)", fw.DisplayName, *wsMode, fw.Protocol, transport, *concurrency, *duration)
+			result, err := benchWebSocket(fw, opts, serverInfo(client, fw), wsOptions{
+				Mode:        *wsMode,
+				Connections: *concurrency,
+				DialWorkers: *wsDial,
+				Size:        *wsSize,
+				Senders:     *wsSenders,
+				Interval:    *wsInterval,
+				Warmup:      *warmup,
+				Duration:    *duration,
+			})
+			if err != nil {
+				log.Er("WebSocket benchmark of %s failed", err, fw.Name)
+				os.Exit(1)
+			}
+			result.Framework = fw.Name
+			result.Protocol = fw.Protocol
+			result.Transport = transport
+			wsResults = append(wsResults, result)
+			client.CloseIdleConnections()
+			continue
+		}
+
+		url := fw.BaseURL() + *endpoint
+		if *jsonCodec != "" {
+			url = withQuery(url, "codec", *jsonCodec)
+		}
+		// RPC targets answer the endpoint with the matching BananasService
+		// method, which always encodes Protobuf.
+		var target load
+		targetFormat := *format
+		closeTarget := func() {}
+		switch {
+		case *fileKind != "" && fw.IsRPC():
+			log.Info("Skipping %s, it serves no file endpoints", fw.DisplayName)
+			continue
+		case *fileKind != "":
+			target, err = fileLoad(client, fw.BaseURL(), files)
+			targetFormat = *fileKind
+		case *graphqlQuery != "" && fw.IsRPC():
+			log.Info("Skipping %s, it serves no GraphQL endpoint", fw.DisplayName)
+			continue
+		case *validateMode != "" && fw.IsRPC():
+			log.Info("Skipping %s, it serves no validation endpoint", fw.DisplayName)
+			continue
+		case *validateMode != "":
+			target, err = validateLoad(client, fw.BaseURL(), *validateMode, mediaType, *invalid)
+		case *authMode != "" && fw.IsRPC():
+			log.Info("Skipping %s, it serves no auth endpoints", fw.DisplayName)
+			continue
+		case *authMode == "login":
+			target, err = loginLoad(client, fw.BaseURL(), mediaType)
+		case *expect != 0 && fw.IsRPC():
+			log.Info("Skipping %s, it answers errors with RPC statuses", fw.DisplayName)
+			continue
+		case fw.IsRPC():
+			target, closeTarget, err = rpcLoad(fw, client, opts, *endpoint, *rpcWire)
+			targetFormat = "protobuf"
+		default:
+			header := http.Header{"Accept": {mediaType}}
+			if *authMode == "recent" {
+				var token string
+				token, err = signIn(client, fw.BaseURL())
+				header.Set("Authorization", "Bearer "+token)
+			}
+			target = load{url: url, decode: decoder}
+			if err == nil {
+				target.newCaller, err = httpCallers(client, url, header, decoder != nil, *expect)
+			}
+		}
+		if err != nil {
+			log.Er("failed to prepare %s", err, fw.Name)
+			os.Exit(1)
+		}
+		log.Info("Benchmarking %s at %s over %s/%s as %s (c=%d, d=%s)", fw.DisplayName, target.url, fw.Protocol, transport, targetFormat, *concurrency, *duration)
+
+		info := serverInfo(client, fw)
+
+		if *warmup > 0 {
+			run(target, *concurrency, *warmup)
+		}
+
+		var sampler *memSampler
+		if *mem || *fileKind != "" {
+			sampler = sampleMemory(client, fw)
+		}
+		result := run(target, *concurrency, *duration)
+		if sampler != nil {
+			result.PeakHeap, result.PeakRSS = sampler.stop()
+		}
+		result.Framework = fw.Name
+		result.Isolation = info.Isolation
+		result.GOMAXPROCS = info.GOMAXPROCS
+		result.Middleware = info.Middleware
+		result.Protocol = fw.Protocol
+		result.Transport = transport
+		if !fw.IsRPC() && *fileKind == "" {
+			result.Codec = *jsonCodec
+			if result.Codec == "" {
+				result.Codec = info.JSONCodec
+			}
+		}
+		result.Format = targetFormat
+		results = append(results, result)
+
+		closeTarget()
+		client.CloseIdleConnections()
+	}
+
+	var output any = results
+	if *wsMode != "" {
+		printWebSocketResults(wsResults)
+		output = wsResults
+	} else {
+		printResults(results)
+	}
+
+	if *out != "" {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			log.Er("failed to encode results", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*out, data, 0o644); err != nil {
+			log.Er("failed to write results", err)
+			os.Exit(1)
+		}
+		log.Info("Results written to %s", *out)
+	}
+}
+
+// selectTargets resolves the -frameworks flag against the config, defaulting
+// to every enabled framework.
+func selectTargets(cfg config.Config, names string) ([]config.FrameworkConfig, error) {
+	if names == "" {
+		return cfg.EnabledFrameworks(), nil
+	}
+
+	var targets []config.FrameworkConfig
+	for _, name := range strings.Split(names, ",") {
+		fw, ok := cfg.Framework(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown framework: %s", name)
+		}
+		targets = append(targets, fw)
+	}
+	return targets, nil
+}
+
+// targetInfo is the part of /api/info that describes how a server is deployed.
+type targetInfo struct {
+	Isolation  string `json:"isolation"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	Middleware string `json:"middleware"`
+	JSONCodec  string `json:"jsonCodec"`
+}
+
+// serverInfo asks the target how it is deployed so results record whether
+// the framework had a process to itself and which middleware stack it ran.
+func serverInfo(client *http.Client, fw config.FrameworkConfig) targetInfo {
+	unknown := targetInfo{Isolation: "unknown", Middleware: "unknown"}
+
+	resp, err := client.Get(fw.BaseURL() + "/api/info")
+	if err != nil {
+		return unknown
+	}
+	defer resp.Body.Close()
+
+	var info targetInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil || info.Isolation == "" {
+		return unknown
+	}
+	return info
+}
+
+// memSample is the memory usage /api/info reports.
+type memSample struct {
+	HeapInuse uint64 `json:"heapInuse"`
+	RSS       uint64 `json:"rss"`
+}
+
+// memSampler polls a server's /api/info for its heap in use and resident
+// set size, keeping the highest of each seen.
+type memSampler struct {
+	done chan struct{}
+	peak chan memSample
+}
+
+// sampleMemory starts polling every 100ms until stop is called.
+func sampleMemory(client *http.Client, fw config.FrameworkConfig) *memSampler {
+	s := &memSampler{done: make(chan struct{}), peak: make(chan memSample)}
+	go func() {
+		var peak memSample
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				s.peak <- peak
+				return
+			case <-ticker.C:
+				var sample memSample
+				resp, err := client.Get(fw.BaseURL() + "/api/info")
+				if err != nil {
+					continue
+				}
+				json.NewDecoder(resp.Body).Decode(&sample)
+				resp.Body.Close()
+				peak.HeapInuse = max(peak.HeapInuse, sample.HeapInuse)
+				peak.RSS = max(peak.RSS, sample.RSS)
+			}
+		}
+	}()
+	return s
+}
+
+// stop ends sampling and returns the peak heap and RSS, each 0 if never
+// reported.
+func (s *memSampler) stop() (heap, rss uint64) {
+	close(s.done)
+	peak := <-s.peak
+	return peak.HeapInuse, peak.RSS
+}
+
+// load is the call each worker makes repeatedly.
+type load struct {
+	url       string
+	newCaller func() caller
+	// decode, when set, parses each response body so its cost is measured.
+	decode func([]byte) error
+}
+
+// newDecoder returns a function decoding mediaType bodies from endpoint.
+func newDecoder(mediaType, endpoint string) (func([]byte) error, error) {
+	switch mediaType {
+	case codec.MediaEventStream:
+		return decodeEventStream, nil
+	case codec.MediaNDJSON:
+		return decodeNDJSON, nil
+	}
+	if mediaType != codec.MediaProtobuf {
+		return func(data []byte) error {
+			var v any
+			return codec.Unmarshal(mediaType, data, &v)
+		}, nil
+	}
+
+	path, _, _ := strings.Cut(endpoint, "?")
+	newMessage, ok := protoMessages[path]
+	if !ok {
+		return nil, fmt.Errorf("no protobuf message known for %s", path)
+	}
+	return func(data []byte) error {
+		return codec.Unmarshal(mediaType, data, newMessage())
+	}, nil
+}
+
+// decodeEventStream decodes the JSON data of every Server-Sent Event.
+func decodeEventStream(data []byte) error {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if payload, ok := bytes.CutPrefix(line, []byte("data: ")); ok {
+			var v any
+			if err := json.Unmarshal(payload, &v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeNDJSON decodes every line as a JSON document.
+func decodeNDJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var v any
+		if err := decoder.Decode(&v); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// workerStats accumulates body sizes and decode times for one worker.
+type workerStats struct {
+	bytes   int64
+	decoded int64
+	decode  time.Duration
+}
+
+// response is what the run loop records about one call.
+type response struct {
+	proto  string
+	timing string // Server-Timing header, if any
+	size   int
+	// body is kept for -decode. RPC clients decode as they receive, so
+	// their calls leave it nil.
+	body   []byte
+	failed bool
+}
+
+// caller makes one call against the target.
+type caller func() (response, error)
+
+// httpCallers returns a constructor for GET callers sending header. Each
+// worker gets its own request so nothing is shared between goroutines.
+// Bodies are only kept when keepBody is set, for -decode; otherwise they are
+// counted and discarded, so large streams do not pile up in the client. A
+// call fails unless it is answered with expect, when set, or else any status
+// below 400.
+func httpCallers(client *http.Client, url string, header http.Header, keepBody bool, expect int) (func() caller, error) {
+	base, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	base.Header = header
+
+	return func() caller {
+		req := base.Clone(base.Context())
+		return func() (response, error) {
+			resp, err := client.Do(req)
+			if err != nil {
+				return response{}, err
+			}
+			var body []byte
+			var size int64
+			if keepBody {
+				body, err = io.ReadAll(resp.Body)
+				size = int64(len(body))
+			} else {
+				size, err = io.Copy(io.Discard, resp.Body)
+			}
+			resp.Body.Close()
+			return response{
+				proto:  resp.Proto,
+				timing: resp.Header.Get("Server-Timing"),
+				size:   int(size),
+				body:   body,
+				failed: err != nil || !expected(resp, expect),
+			}, nil
+		}
+	}, nil
+}
+
+// expected reports whether resp is the answer -expect asks for. An error
+// status only counts when the body is a problem, so a framework answering in
+// its own format is caught rather than measured.
+func expected(resp *http.Response, expect int) bool {
+	if expect == 0 {
+		return resp.StatusCode < 400
+	}
+	if resp.StatusCode != expect {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return expect < 400 || mediaType == problem.MediaType
+}
+
+// run drives target with the given number of workers until duration elapses.
+func run(target load, concurrency int, duration time.Duration) Result {
+	var requests, errors atomic.Int64
+	var proto atomic.Value
+	latencies := make([][]time.Duration, concurrency)
+	timings := make([]timingTotals, concurrency)
+	stats := make([]workerStats, concurrency)
+
+	deadline := time.Now().Add(duration)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			call := target.newCaller()
+			for time.Now().Before(deadline) {
+				reqStart := time.Now()
+				resp, err := call()
+				if err != nil {
+					errors.Add(1)
+					continue
+				}
+				elapsed := time.Since(reqStart)
+				proto.CompareAndSwap(nil, resp.proto)
+				timings[worker].add(resp.timing)
+				stats[worker].bytes += int64(resp.size)
+
+				latencies[worker] = append(latencies[worker], elapsed)
+				requests.Add(1)
+				if resp.failed {
+					errors.Add(1)
+					continue
+				}
+
+				if target.decode != nil && resp.body != nil {
+					decodeStart := time.Now()
+					if err := target.decode(resp.body); err != nil {
+						errors.Add(1)
+						continue
+					}
+					stats[worker].decode += time.Since(decodeStart)
+					stats[worker].decoded++
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var all []time.Duration
+	for _, l := range latencies {
+		all = append(all, l...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	result := Result{
+		URL:      target.url,
+		Requests: requests.Load(),
+		Errors:   errors.Load(),
+		Duration: elapsed,
+		RPS:      float64(requests.Load()) / elapsed.Seconds(),
+	}
+	if p, ok := proto.Load().(string); ok {
+		result.Proto = p
+	}
+	result.ServerTiming = averageTimings(timings)
+
+	var totalBytes, decoded int64
+	var decodeTime time.Duration
+	for _, s := range stats {
+		totalBytes += s.bytes
+		decoded += s.decoded
+		decodeTime += s.decode
+	}
+	if result.Requests > 0 {
+		result.BytesAvg = totalBytes / result.Requests
+	}
+	result.Throughput = float64(totalBytes) / (1 << 20) / elapsed.Seconds()
+	if decoded > 0 {
+		result.DecodeAvg = decodeTime / time.Duration(decoded)
+	}
+
+	if len(all) > 0 {
+		var total time.Duration
+		for _, l := range all {
+			total += l
+		}
+		result.LatencyAvg = total / time.Duration(len(all))
+		result.LatencyP50 = percentile(all, 0.50)
+		result.LatencyP90 = percentile(all, 0.90)
+		result.LatencyP99 = percentile(all, 0.99)
+		result.LatencyMax = all[len(all)-1]
+	}
+
+	return result
+}
+
+// timingTotals sums Server-Timing durations per metric for one worker.
+type timingTotals struct {
+	sum   map[string]time.Duration
+	count map[string]int
+}
+
+// add parses a Server-Timing header such as `db;desc="sql";dur=1.5, encode;dur=0.2`.
+func (t *timingTotals) add(header string) {
+	if header == "" {
+		return
+	}
+	if t.sum == nil {
+		t.sum = map[string]time.Duration{}
+		t.count = map[string]int{}
+	}
+	for _, metric := range strings.Split(header, ",") {
+		params := strings.Split(strings.TrimSpace(metric), ";")
+		for _, param := range params[1:] {
+			value, ok := strings.CutPrefix(strings.TrimSpace(param), "dur=")
+			if !ok {
+				continue
+			}
+			ms, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			t.sum[params[0]] += time.Duration(ms * float64(time.Millisecond))
+			t.count[params[0]]++
+		}
+	}
+}
+
+func averageTimings(workers []timingTotals) map[string]time.Duration {
+	sum := map[string]time.Duration{}
+	count := map[string]int{}
+	for _, w := range workers {
+		for name, d := range w.sum {
+			sum[name] += d
+			count[name] += w.count[name]
+		}
+	}
+	if len(sum) == 0 {
+		return nil
+	}
+
+	avg := make(map[string]time.Duration, len(sum))
+	for name, d := range sum {
+		avg[name] = d / time.Duration(count[name])
+	}
+	return avg
+}
+
+// withQuery adds key=value to rawURL's query string.
+func withQuery(rawURL, key, value string) string {
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + url.QueryEscape(key) + "=" + url.QueryEscape(value)
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
+
+func printResults(results []Result) {
+	fmt.Printf("\n%-10s %-8s %-5s %-9s %-10s %5s %-8s %-8s %10s %8s %12s %10s %10s %10s %10s %10s %10s %10s %10s %10s %10s %10s\n",
+		"framework", "proto", "net", "isolation", "middleware", "procs", "codec", "format",
+		"requests", "errors", "rps", "avg", "p50", "p99", "max", "db", "encode", "bytes", "MB/s", "decode", "heap", "rss")
+	for _, r := range results {
+		fmt.Printf("%-10s %-8s %-5s %-9s %-10s %5d %-8s %-8s %10d %8d %12.1f %10s %10s %10s %10s %10s %10s %10d %10.1f %10s %10s %10s\n",
+			r.Framework, r.Proto, r.Transport, r.Isolation, r.Middleware, r.GOMAXPROCS, r.Codec, r.Format,
+			r.Requests, r.Errors, r.RPS,
+			r.LatencyAvg.Round(time.Microsecond),
+			r.LatencyP50.Round(time.Microsecond),
+			r.LatencyP99.Round(time.Microsecond),
+			r.LatencyMax.Round(time.Microsecond),
+			formatTiming(r.ServerTiming, "db"),
+			formatTiming(r.ServerTiming, "encode"),
+			r.BytesAvg,
+			r.Throughput,
+			r.DecodeAvg.Round(time.Microsecond),
+			formatBytes(r.PeakHeap),
+			formatBytes(r.PeakRSS))
+	}
+}
+
+// formatTiming renders the average of a Server-Timing metric, or "-" when
+// the server did not report it, as RPC servers do not for encoding.
+func formatTiming(timings map[string]time.Duration, name string) string {
+	d, ok := timings[name]
+	if !ok {
+		return "-"
+	}
+	return d.Round(time.Microsecond).String()
+}
+
+// formatBytes renders n in MiB, or "-" when it was not measured.
+func formatBytes(n uint64) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(float64(n)/(1<<20), 'f', 1, 64) + "MiB"
+}
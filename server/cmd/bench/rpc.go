@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bananas/internal/config"
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/gen/bananas/v1/bananasv1connect"
+	"bananas/internal/httpclient"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// rpcCall invokes one BananasService method, returning the response size,
+// the Server-Timing header and the error.
+type rpcCall func(ctx context.Context) (int, string, error)
+
+// rpcParams splits an HTTP endpoint into the path that selects the RPC and
+// the orm, limit and status query parameters that become request fields, so
+// -endpoint asks REST and RPC targets for the same data.
+func rpcParams(endpoint string) (path, orm string, limit int32, status string, err error) {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", "", 0, "", err
+	}
+	parsed, _ := strconv.Atoi(query.Get("limit"))
+	return path, query.Get("orm"), int32(parsed), query.Get("status"), nil
+}
+
+func grpcMethod(client bananasv1.BananasServiceClient, path, orm string, limit int32, status string) (rpcCall, error) {
+	unary := func(call func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error)) rpcCall {
+		return func(ctx context.Context) (int, string, error) {
+			var header metadata.MD
+			msg, err := call(ctx, grpc.Header(&header))
+			if err != nil {
+				return 0, "", err
+			}
+			return proto.Size(msg), strings.Join(header.Get("server-timing"), ", "), nil
+		}
+	}
+
+	switch path {
+	case "/api/test/simple":
+		return unary(func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.SimpleRequest(ctx, &bananasv1.SimpleRequestRequest{}, opts...)
+		}), nil
+	case "/api/test/json":
+		return unary(func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.JsonResponse(ctx, &bananasv1.JsonResponseRequest{}, opts...)
+		}), nil
+	case "/api/orders/recent":
+		return unary(func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.GetRecentOrders(ctx, &bananasv1.GetRecentOrdersRequest{Orm: orm, Limit: limit}, opts...)
+		}), nil
+	case "/api/orders":
+		return unary(func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ListOrders(ctx, &bananasv1.ListOrdersRequest{Orm: orm, PageSize: limit, Status: status}, opts...)
+		}), nil
+	case "/api/orders/stream":
+		return func(ctx context.Context) (int, string, error) {
+			stream, err := client.StreamOrders(ctx, &bananasv1.StreamOrdersRequest{Orm: orm, Limit: limit})
+			if err != nil {
+				return 0, "", err
+			}
+			size := 0
+			for {
+				msg, err := stream.Recv()
+				if err == io.EOF {
+					return size, "", nil
+				}
+				if err != nil {
+					return size, "", err
+				}
+				size += proto.Size(msg)
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("no RPC matches %s", path)
+}
+
+func connectMethod(client bananasv1connect.BananasServiceClient, path, orm string, limit int32, status string) (rpcCall, error) {
+	switch path {
+	case "/api/test/simple":
+		return func(ctx context.Context) (int, string, error) {
+			resp, err := client.SimpleRequest(ctx, connect.NewRequest(&bananasv1.SimpleRequestRequest{}))
+			if err != nil {
+				return 0, "", err
+			}
+			return proto.Size(resp.Msg), resp.Header().Get("Server-Timing"), nil
+		}, nil
+	case "/api/test/json":
+		return func(ctx context.Context) (int, string, error) {
+			resp, err := client.JsonResponse(ctx, connect.NewRequest(&bananasv1.JsonResponseRequest{}))
+			if err != nil {
+				return 0, "", err
+			}
+			return proto.Size(resp.Msg), resp.Header().Get("Server-Timing"), nil
+		}, nil
+	case "/api/orders/recent":
+		return func(ctx context.Context) (int, string, error) {
+			resp, err := client.GetRecentOrders(ctx, connect.NewRequest(&bananasv1.GetRecentOrdersRequest{Orm: orm, Limit: limit}))
+			if err != nil {
+				return 0, "", err
+			}
+			return proto.Size(resp.Msg), resp.Header().Get("Server-Timing"), nil
+		}, nil
+	case "/api/orders":
+		return func(ctx context.Context) (int, string, error) {
+			resp, err := client.ListOrders(ctx, connect.NewRequest(&bananasv1.ListOrdersRequest{Orm: orm, PageSize: limit, Status: status}))
+			if err != nil {
+				return 0, "", err
+			}
+			return proto.Size(resp.Msg), resp.Header().Get("Server-Timing"), nil
+		}, nil
+	case "/api/orders/stream":
+		return func(ctx context.Context) (int, string, error) {
+			stream, err := client.StreamOrders(ctx, connect.NewRequest(&bananasv1.StreamOrdersRequest{Orm: orm, Limit: limit}))
+			if err != nil {
+				return 0, "", err
+			}
+			defer stream.Close()
+			size := 0
+			for stream.Receive() {
+				size += proto.Size(stream.Msg())
+			}
+			return size, "", stream.Err()
+		}, nil
+	}
+	return nil, fmt.Errorf("no RPC matches %s", path)
+}
+
+// rpcLoad builds the load for an RPC target. grpc is reached with grpc-go
+// over its own HTTP/2 transport; connect with the Connect client over
+// httpClient, speaking wire (connect, grpc or grpcweb). The returned close
+// releases the gRPC connection.
+func rpcLoad(fw config.FrameworkConfig, httpClient *http.Client, opts httpclient.Options, endpoint, wire string) (load, func(), error) {
+	target := load{url: fw.Name + "://" + fw.DialAddr() + endpoint}
+
+	path, orm, limit, status, err := rpcParams(endpoint)
+	if err != nil {
+		return load{}, nil, err
+	}
+
+	var call rpcCall
+	var negotiated string
+	closeFn := func() {}
+
+	if fw.Name == "grpc" {
+		conn, err := httpclient.GRPC(fw.DialAddr(), opts)
+		if err != nil {
+			return load{}, nil, err
+		}
+		call, err = grpcMethod(bananasv1.NewBananasServiceClient(conn), path, orm, limit, status)
+		if err != nil {
+			conn.Close()
+			return load{}, nil, err
+		}
+		negotiated = "grpc"
+		closeFn = func() { conn.Close() }
+	} else {
+		var clientOpts []connect.ClientOption
+		switch wire {
+		case "connect":
+		case "grpc":
+			clientOpts = append(clientOpts, connect.WithGRPC())
+		case "grpcweb":
+			clientOpts = append(clientOpts, connect.WithGRPCWeb())
+		default:
+			return load{}, nil, fmt.Errorf("unknown RPC wire protocol %q, use connect, grpc or grpcweb", wire)
+		}
+		client := bananasv1connect.NewBananasServiceClient(httpClient, fw.BaseURL(), clientOpts...)
+
+		call, err = connectMethod(client, path, orm, limit, status)
+		if err != nil {
+			return load{}, nil, err
+		}
+		negotiated = wire
+	}
+
+	// A failed call still completed a round trip, like an HTTP error status,
+	// so it is recorded as a failed response rather than a transport error.
+	target.newCaller = func() caller {
+		return func() (response, error) {
+			size, timing, err := call(context.Background())
+			return response{proto: negotiated, timing: timing, size: size, failed: err != nil}, nil
+		}
+	}
+	return target, closeFn, nil
+}
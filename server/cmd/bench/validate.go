@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bananas/internal/validation"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// validateItems is the number of line items in the -validate payload, enough
+// for the per-item rules to be a real share of the work.
+const validateItems = 20
+
+// validateLoad returns the load for a -validate run, posting the same order
+// to /api/test/validate in the given mode on every call. With invalid set the
+// order breaks rules at every level, and the 422 it earns is the expected
+// answer rather than an error.
+func validateLoad(client *http.Client, baseURL, mode, accept string, invalid bool) (load, error) {
+	body, err := json.Marshal(validateOrder(invalid))
+	if err != nil {
+		return load{}, err
+	}
+
+	expect := http.StatusOK
+	if invalid {
+		expect = http.StatusUnprocessableEntity
+	}
+	url := withQuery(baseURL+"/api/test/validate", "mode", mode)
+	return load{url: url, newCaller: func() caller {
+		return postCaller(client, url, accept, body, expect)
+	}}, nil
+}
+
+// postCaller posts body as JSON on every call, counting any status but
+// expect as a failure.
+func postCaller(client *http.Client, url, accept string, body []byte, expect int) caller {
+	return func() (response, error) {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return response{}, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", accept)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return response{}, err
+		}
+		n, err := io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return response{
+			proto:  resp.Proto,
+			timing: resp.Header.Get("Server-Timing"),
+			size:   int(n),
+			failed: err != nil || resp.StatusCode != expect,
+		}, nil
+	}
+}
+
+// validateOrder builds the -validate payload.
+func validateOrder(invalid bool) validation.OrderRequest {
+	phone := "+15555550123"
+	notes := "Leave at the side door"
+	order := validation.OrderRequest{
+		OrderReference: "BENCH000123",
+		Customer: validation.CustomerInput{
+			FirstName: "Ada",
+			LastName:  "Lovelace",
+			Email:     "ada@example.com",
+			Phone:     &phone,
+		},
+		ShippingAddress: validation.AddressInput{
+			Line1:      "12 Analytical Way",
+			City:       "London",
+			PostalCode: "N1 9GU",
+			Country:    "GB",
+		},
+		Currency:       "GBP",
+		ShippingMethod: "express",
+		Notes:          &notes,
+	}
+	for i := range validateItems {
+		order.Items = append(order.Items, validation.ItemInput{
+			ProductID: fmt.Sprintf("00000000-0000-4000-8000-%012d", i+1),
+			SKU:       fmt.Sprintf("SKU-%05d", i+1),
+			Quantity:  i%5 + 1,
+			UnitPrice: 9.99 + float64(i),
+			Discount:  0.1,
+		})
+	}
+
+	if invalid {
+		order.OrderReference = "bad-ref"
+		order.Customer.Email = "not an email"
+		order.ShippingAddress.Country = "Great Britain"
+		order.Currency = "BTC"
+		for i := range order.Items {
+			if i%2 == 0 {
+				order.Items[i].Quantity = 0
+				order.Items[i].ProductID = "not-a-uuid"
+			}
+		}
+	}
+	return order
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bananas/internal/config"
+	"bananas/internal/httpclient"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// wsOptions configures a WebSocket run. Connections is -c; each run dials
+// them all, then exchanges messages for the duration.
+type wsOptions struct {
+	Mode        string // echo or broadcast
+	Connections int
+	DialWorkers int
+	Size        int
+	Senders     int
+	Interval    time.Duration
+	Warmup      time.Duration
+	Duration    time.Duration
+}
+
+// WSResult summarises one WebSocket run against a single framework.
+type WSResult struct {
+	Framework  string `json:"framework"`
+	URL        string `json:"url"`
+	Mode       string `json:"mode"`
+	Isolation  string `json:"isolation"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	Middleware string `json:"middleware"`
+	Protocol   string `json:"protocol"`
+	Transport  string `json:"transport"`
+
+	// Connection setup: how fast the handshakes completed with DialWorkers
+	// in flight, and how long each one took.
+	Connections  int           `json:"connections"`
+	DialErrors   int64         `json:"dialErrors"`
+	SetupTime    time.Duration `json:"setupTime"`
+	SetupRate    float64       `json:"setupRate"` // handshakes per second
+	HandshakeP50 time.Duration `json:"handshakeP50"`
+	HandshakeP99 time.Duration `json:"handshakeP99"`
+	HandshakeMax time.Duration `json:"handshakeMax"`
+
+	// Messages: for echo, Received counts replies and latency is the round
+	// trip; for broadcast, Received counts deliveries to every connection,
+	// Expected what a lossless hub would deliver, and latency runs from
+	// send to each delivery.
+	Duration    time.Duration `json:"duration"`
+	Sent        int64         `json:"sent"`
+	Received    int64         `json:"received"`
+	Expected    int64         `json:"expected,omitempty"`
+	Errors      int64         `json:"errors"`
+	MessageRate float64       `json:"messageRate"` // received per second
+	LatencyAvg  time.Duration `json:"latencyAvg"`
+	LatencyP50  time.Duration `json:"latencyP50"`
+	LatencyP90  time.Duration `json:"latencyP90"`
+	LatencyP99  time.Duration `json:"latencyP99"`
+	LatencyMax  time.Duration `json:"latencyMax"`
+}
+
+// wsPhase is what one message phase measured.
+type wsPhase struct {
+	sent, received, expected, errors int64
+	elapsed                          time.Duration
+	latencies                        []time.Duration
+}
+
+// benchWebSocket dials the target's /ws endpoint for the mode, then drives
+// the connections through a warmup and a measured phase.
+func benchWebSocket(fw config.FrameworkConfig, opts httpclient.Options, info targetInfo, wsOpts wsOptions) (WSResult, error) {
+	dialer, err := httpclient.WebSocket(opts)
+	if err != nil {
+		return WSResult{}, err
+	}
+	url := httpclient.WebSocketURL(fw.BaseURL()) + "/ws/" + wsOpts.Mode
+
+	conns, handshakes, dialErrors, setup := dialAll(dialer, url, wsOpts.Connections, wsOpts.DialWorkers)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	if len(conns) == 0 {
+		return WSResult{}, fmt.Errorf("no connections to %s succeeded", url)
+	}
+
+	result := WSResult{
+		URL:         url,
+		Mode:        wsOpts.Mode,
+		Isolation:   info.Isolation,
+		GOMAXPROCS:  info.GOMAXPROCS,
+		Middleware:  info.Middleware,
+		Connections: len(conns),
+		DialErrors:  dialErrors,
+		SetupTime:   setup,
+		SetupRate:   float64(len(conns)) / setup.Seconds(),
+	}
+	sort.Slice(handshakes, func(i, j int) bool { return handshakes[i] < handshakes[j] })
+	result.HandshakeP50 = percentile(handshakes, 0.50)
+	result.HandshakeP99 = percentile(handshakes, 0.99)
+	result.HandshakeMax = handshakes[len(handshakes)-1]
+
+	phase := wsEcho
+	if wsOpts.Mode == "broadcast" {
+		phase = wsBroadcast
+	}
+	if wsOpts.Warmup > 0 {
+		phase(conns, wsOpts, wsOpts.Warmup)
+	}
+	measured := phase(conns, wsOpts, wsOpts.Duration)
+
+	result.Duration = measured.elapsed
+	result.Sent = measured.sent
+	result.Received = measured.received
+	result.Expected = measured.expected
+	result.Errors = measured.errors
+	result.MessageRate = float64(measured.received) / measured.elapsed.Seconds()
+
+	all := measured.latencies
+	if len(all) > 0 {
+		sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+		var total time.Duration
+		for _, l := range all {
+			total += l
+		}
+		result.LatencyAvg = total / time.Duration(len(all))
+		result.LatencyP50 = percentile(all, 0.50)
+		result.LatencyP90 = percentile(all, 0.90)
+		result.LatencyP99 = percentile(all, 0.99)
+		result.LatencyMax = all[len(all)-1]
+	}
+	return result, nil
+}
+
+// dialAll opens n connections with workers handshakes in flight at a time,
+// returning the connections that succeeded, each handshake's duration, the
+// failure count and the total setup time.
+func dialAll(dialer *websocket.Dialer, url string, n, workers int) ([]*websocket.Conn, []time.Duration, int64, time.Duration) {
+	conns := make([]*websocket.Conn, n)
+	handshakes := make([]time.Duration, n)
+	var failures atomic.Int64
+	var next atomic.Int64
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= n {
+					return
+				}
+				dialStart := time.Now()
+				conn, resp, err := dialer.Dial(url, nil)
+				if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+					resp.Body.Close()
+				}
+				if err != nil {
+					failures.Add(1)
+					continue
+				}
+				handshakes[i] = time.Since(dialStart)
+				conns[i] = conn
+			}
+		}()
+	}
+	wg.Wait()
+	setup := time.Since(start)
+
+	var open []*websocket.Conn
+	var durations []time.Duration
+	for i, conn := range conns {
+		if conn != nil {
+			open = append(open, conn)
+			durations = append(durations, handshakes[i])
+		}
+	}
+	return open, durations, failures.Load(), setup
+}
+
+// wsEcho has every connection send a message and wait for its echo, over
+// and over, until duration elapses.
+func wsEcho(conns []*websocket.Conn, opts wsOptions, duration time.Duration) wsPhase {
+	latencies := make([][]time.Duration, len(conns))
+	var sent, received, errors atomic.Int64
+
+	deadline := time.Now().Add(duration)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(worker int, conn *websocket.Conn) {
+			defer wg.Done()
+			payload := make([]byte, opts.Size)
+			conn.SetReadDeadline(deadline.Add(5 * time.Second))
+			for time.Now().Before(deadline) {
+				msgStart := time.Now()
+				if err := conn.WriteMessage(websocket.BinaryMessage, payload); err != nil {
+					errors.Add(1)
+					return
+				}
+				sent.Add(1)
+				if _, _, err := conn.ReadMessage(); err != nil {
+					errors.Add(1)
+					return
+				}
+				latencies[worker] = append(latencies[worker], time.Since(msgStart))
+				received.Add(1)
+			}
+		}(i, conn)
+	}
+	wg.Wait()
+
+	return wsPhase{
+		sent:      sent.Load(),
+		received:  received.Load(),
+		errors:    errors.Load(),
+		elapsed:   time.Since(start),
+		latencies: flatten(latencies),
+	}
+}
+
+// broadcastStop is the send time carried by the message that tells every
+// reader the phase is over.
+const broadcastStop = ^uint64(0)
+
+// wsBroadcast has the first Senders connections each send a message every
+// Interval while every connection reads. Messages carry their send time, so
+// each delivery's latency is measured on arrival. Once sending stops and
+// in-flight messages have settled, a stop message ends the readers; a read
+// deadline catches any reader whose stop message was dropped.
+func wsBroadcast(conns []*websocket.Conn, opts wsOptions, duration time.Duration) wsPhase {
+	const settle = 250 * time.Millisecond
+	senders := min(opts.Senders, len(conns))
+	latencies := make([][]time.Duration, len(conns))
+	var sent, received, errors atomic.Int64
+
+	deadline := time.Now().Add(duration)
+	start := time.Now()
+
+	var readers sync.WaitGroup
+	for i, conn := range conns {
+		readers.Add(1)
+		go func(worker int, conn *websocket.Conn) {
+			defer readers.Done()
+			conn.SetReadDeadline(deadline.Add(10 * time.Second))
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					errors.Add(1)
+					return
+				}
+				if len(data) < 8 {
+					errors.Add(1)
+					continue
+				}
+				sentAt := binary.BigEndian.Uint64(data)
+				if sentAt == broadcastStop {
+					return
+				}
+				latencies[worker] = append(latencies[worker], time.Since(start)-time.Duration(sentAt))
+				received.Add(1)
+			}
+		}(i, conn)
+	}
+
+	var writers sync.WaitGroup
+	for _, conn := range conns[:senders] {
+		writers.Add(1)
+		go func(conn *websocket.Conn) {
+			defer writers.Done()
+			payload := make([]byte, max(opts.Size, 8))
+			ticker := time.NewTicker(opts.Interval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				if now.After(deadline) {
+					return
+				}
+				binary.BigEndian.PutUint64(payload, uint64(time.Since(start)))
+				if err := conn.WriteMessage(websocket.BinaryMessage, payload); err != nil {
+					errors.Add(1)
+					return
+				}
+				sent.Add(1)
+			}
+		}(conn)
+	}
+	writers.Wait()
+
+	time.Sleep(settle)
+	stop := make([]byte, 8)
+	binary.BigEndian.PutUint64(stop, broadcastStop)
+	if err := conns[0].WriteMessage(websocket.BinaryMessage, stop); err != nil {
+		errors.Add(1)
+	}
+	readers.Wait()
+
+	return wsPhase{
+		sent:      sent.Load(),
+		received:  received.Load(),
+		expected:  sent.Load() * int64(len(conns)),
+		errors:    errors.Load(),
+		elapsed:   time.Since(start),
+		latencies: flatten(latencies),
+	}
+}
+
+func flatten(perWorker [][]time.Duration) []time.Duration {
+	var all []time.Duration
+	for _, l := range perWorker {
+		all = append(all, l...)
+	}
+	return all
+}
+
+func printWebSocketResults(results []WSResult) {
+	fmt.Printf("\n%-10s %-9s %-6s %-5s %-10s %7s %6s %10s %10s %10s %10s %10s %8s %12s %10s %10s %10s %10s\n",
+		"framework", "mode", "proto", "net", "middleware", "conns", "dialx", "setup/s", "hs p50", "hs p99",
+		"sent", "received", "errors", "msg/s", "avg", "p50", "p99", "max")
+	for _, r := range results {
+		fmt.Printf("%-10s %-9s %-6s %-5s %-10s %7d %6d %10.1f %10s %10s %10d %10d %8d %12.1f %10s %10s %10s %10s\n",
+			r.Framework, r.Mode, r.Protocol, r.Transport, r.Middleware, r.Connections, r.DialErrors, r.SetupRate,
+			r.HandshakeP50.Round(time.Microsecond),
+			r.HandshakeP99.Round(time.Microsecond),
+			r.Sent, r.Received, r.Errors, r.MessageRate,
+			r.LatencyAvg.Round(time.Microsecond),
+			r.LatencyP50.Round(time.Microsecond),
+			r.LatencyP99.Round(time.Microsecond),
+			r.LatencyMax.Round(time.Microsecond))
+	}
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"bananas/internal/config"
 	"bananas/internal/database"
@@ -16,7 +19,10 @@ import (
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run cmd/migration/main.go [create-db|up|down|seed]")
+		fmt.Println("Usage: go run cmd/migration/main.go [create-db|up|down|seed|checksum|skew|snapshot]")
+		fmt.Println("       go run cmd/migration/main.go seed [--profile=medium] [--seed=42] [--tables=customers,sales_orders] [--dry-run] [--resume]")
+		fmt.Println("       go run cmd/migration/main.go seed grow [--profile=medium] --sales-orders=+1000000 [--purchase-orders=+100000]")
+		fmt.Println("       go run cmd/migration/main.go snapshot export|import [--dir=snapshot] [--compression=zstd] [--workers=4]")
 		os.Exit(1)
 	}
 
@@ -29,13 +35,42 @@ func main() {
 		os.Exit(1)
 	}
 
+	var seedOpts seedOptions
+	if command == "seed" {
+		if seedOpts, err = parseSeedFlags(os.Args[2:], cfg.SeedProfile); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			os.Exit(2)
+		}
+		if seedOpts.dryRun {
+			seedCfg, err := seedOpts.seedConfig()
+			if err != nil {
+				log.Er("invalid seeding profile", err)
+				os.Exit(1)
+			}
+			printSeedPlan(seedOpts.profile, seedCfg)
+			return
+		}
+	}
+
+	var snapshotOpts snapshotOptions
+	if command == "snapshot" {
+		if snapshotOpts, err = parseSnapshotFlags(os.Args[2:], cfg.SeedProfile); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			os.Exit(2)
+		}
+	}
+
 	switch command {
 	case "create-db":
 		err = createDatabase(cfg, log)
-	case "up", "down", "seed":
-		db, err := database.New(cfg)
-		if err != nil {
-			log.Er("failed to connect to database", err)
+	case "up", "down", "seed", "checksum", "skew", "snapshot":
+		db, dbErr := database.New(cfg)
+		if dbErr != nil {
+			log.Er("failed to connect to database", dbErr)
 			os.Exit(1)
 		}
 		defer db.Close()
@@ -45,16 +80,26 @@ func main() {
 			err = migrateUp(db)
 			if err == nil {
 				// Auto-check if seeding is needed
-				err = autoSeedIfNeeded(db, log)
+				err = autoSeedIfNeeded(db, cfg.SeedProfile, log)
 			}
 		case "down":
 			err = migrateDown(db)
 		case "seed":
-			err = seed(db)
+			if seedOpts.bulk {
+				err = seedProfile(db, seedOpts)
+			} else {
+				err = seed(db)
+			}
+		case "checksum":
+			err = checksum(db)
+		case "skew":
+			err = skew(db)
+		case "snapshot":
+			err = snapshot(db, snapshotOpts)
 		}
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
-		fmt.Println("Available commands: create-db, up, down, seed")
+		fmt.Println("Available commands: create-db, up, down, seed, checksum, skew, snapshot")
 		os.Exit(1)
 	}
 
@@ -443,6 +488,15 @@ func migrateUp(db *database.DB) error {
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_customer_addresses_customer_id ON customer_addresses(customer_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_customer_addresses_is_default ON customer_addresses(is_default)`,
+
+		// Authentication
+		`CREATE TABLE IF NOT EXISTS users (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			email VARCHAR(255) UNIQUE NOT NULL,
+			password_hash VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		)`,
 	}
 
 	for _, query := range queries {
@@ -462,6 +516,8 @@ func migrateDown(db *database.DB) error {
 
 	queries := []string{
 		// Drop tables in reverse order of dependencies
+		`DROP TABLE IF EXISTS seed_progress CASCADE`,
+		`DROP TABLE IF EXISTS users CASCADE`,
 		`DROP TABLE IF EXISTS customer_addresses CASCADE`,
 		`DROP TABLE IF EXISTS product_categories CASCADE`,
 		`DROP TABLE IF EXISTS purchase_order_receipts CASCADE`,
@@ -494,12 +550,14 @@ func migrateDown(db *database.DB) error {
 	return nil
 }
 
-// autoSeedIfNeeded checks if database needs seeding and runs the full seeder if needed
-func autoSeedIfNeeded(db *database.DB, log logger.Logger) error {
-	// Use SmallConfig for faster initial testing
-	// Change to DefaultConfig() for production millions of records
-	// cfg := seeder.SmallConfig()
-	cfg := seeder.DefaultConfig()
+// autoSeedIfNeeded checks if database needs seeding and runs the full seeder
+// on profile (SEED_PROFILE) if needed
+func autoSeedIfNeeded(db *database.DB, profile string, log logger.Logger) error {
+	cfg, err := seeder.LoadProfile(profile)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Using seeding profile %s", profile))
 
 	needsSeeding, err := seeder.CheckNeedsSeeding(db, cfg.Customers)
 	if err != nil {
@@ -557,6 +615,51 @@ func autoSeedIfNeeded(db *database.DB, log logger.Logger) error {
 	return nil
 }
 
+// checksum prints a content hash per seeded table and overall, to prove two
+// runs with the same seed produced the same data
+func checksum(db *database.DB) error {
+	sums, overall, err := seeder.Checksum(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	for _, sum := range sums {
+		fmt.Printf("%-24s %12d  %s\n", sum.Table, sum.Rows, sum.Hash)
+	}
+	fmt.Printf("%-24s %12s  %s\n", "overall", "", overall)
+	return nil
+}
+
+// skew prints the realized popularity, seasonality and basket size
+// distributions of the seeded sales orders
+func skew(db *database.DB) error {
+	stats, err := seeder.MeasureSkew(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range []struct {
+		name string
+		seeder.Popularity
+	}{{"products", stats.Products}, {"customers", stats.Customers}} {
+		fmt.Printf("%-10s %d of %d referenced, top 1%% %.1f%%, top 10%% %.1f%%, hottest %d of %d\n",
+			p.name, p.Referenced, p.Rows, p.Top1Share*100, p.Top10Share*100, p.Top, p.References)
+	}
+	fmt.Printf("%-10s mean %.2f, median %.0f, p99 %.0f, max %d\n",
+		"items", stats.ItemsMean, stats.ItemsMedian, stats.ItemsP99, stats.ItemsMax)
+
+	fmt.Printf("%-10s", "weekday")
+	for day, n := range stats.Weekday {
+		fmt.Printf(" %s %d", time.Weekday(day).String()[:3], n)
+	}
+	fmt.Println()
+	if stats.DayMean > 0 {
+		fmt.Printf("%-10s %s with %d orders, %.1fx the daily mean of %.0f\n", "peak",
+			stats.PeakDay.Format("2006-01-02"), stats.PeakDays, float64(stats.PeakDays)/stats.DayMean, stats.DayMean)
+	}
+	return nil
+}
+
 func seed(db *database.DB) error {
 	log := db.Logger.Function("seed")
 
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bananas/internal/database"
+	"bananas/internal/seeder"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// seedOptions are the flags of the seed command. Without any, seed loads the
+// small hand-written sample dataset; with any, it runs the bulk seeder on a
+// profile. seed grow appends orders to an already seeded database instead.
+type seedOptions struct {
+	profile string
+	seed    uint64
+	tables  []string
+	dryRun  bool
+	resume  bool
+	bulk    bool // any flag was given
+
+	grow           bool
+	salesOrders    int
+	purchaseOrders int
+}
+
+// growth is a count of rows to append, written +N
+type growth int
+
+func (g *growth) String() string { return fmt.Sprintf("+%d", int(*g)) }
+
+func (g *growth) Set(value string) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+	if err != nil || n < 0 {
+		return fmt.Errorf("expected +N rows to append, got %q", value)
+	}
+	*g = growth(n)
+	return nil
+}
+
+func parseSeedFlags(args []string, defaultProfile string) (seedOptions, error) {
+	var opts seedOptions
+	var tables string
+
+	name := "seed"
+	if len(args) > 0 && args[0] == "grow" {
+		name, opts.grow, args = "seed grow", true, args[1:]
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(&opts.profile, "profile", defaultProfile,
+		fmt.Sprintf("seeding profile: %s, or a YAML profile file", strings.Join(seeder.Profiles, ", ")))
+	flags.Uint64Var(&opts.seed, "seed", 0, "random seed; the same seed and profile reproduce the same data, 0 keeps the profile's")
+	flags.StringVar(&tables, "tables", "", "comma separated tables to seed (default: all); the tables they reference must already be seeded, "+
+		"with --seed or else by the last run, whose seed is then reused")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "validate the profile and print the estimated rows and disk size, without seeding")
+	if opts.grow {
+		flags.Var((*growth)(&opts.salesOrders), "sales-orders", "sales orders to append, as +N; with a ledger profile their shipments and reservations move inventory")
+		flags.Var((*growth)(&opts.purchaseOrders), "purchase-orders", "purchase orders to append, as +N; with a ledger profile their receipts move inventory")
+	} else {
+		flags.BoolVar(&opts.resume, "resume", false, "continue the last seeding run where it stopped, with the same profile and tables")
+	}
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	opts.bulk = flags.NFlag() > 0 || opts.grow
+	for _, table := range strings.Split(tables, ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			opts.tables = append(opts.tables, table)
+		}
+	}
+
+	if opts.grow && opts.salesOrders == 0 && opts.purchaseOrders == 0 {
+		err := errors.New("seed grow needs --sales-orders=+N or --purchase-orders=+N")
+		fmt.Fprintln(flags.Output(), err)
+		flags.Usage()
+		return opts, err
+	}
+	return opts, nil
+}
+
+// seedConfig loads the profile and applies the flags over it
+func (opts seedOptions) seedConfig() (*seeder.Config, error) {
+	cfg, err := seeder.LoadProfile(opts.profile)
+	if err != nil {
+		return nil, err
+	}
+	if opts.seed != 0 {
+		cfg.RandomSeed = opts.seed
+	}
+	if len(opts.tables) > 0 {
+		cfg.Tables = opts.tables
+	}
+
+	// Growing writes the orders appended, to the order tables selected
+	if opts.grow {
+		cfg.SalesOrders, cfg.PurchaseOrders = opts.salesOrders, opts.purchaseOrders
+		var tables []string
+		for _, table := range seeder.GrowTables {
+			if len(cfg.Tables) > 0 && !slices.Contains(cfg.Tables, table) {
+				continue
+			}
+			if strings.HasPrefix(table, "sales_") && cfg.SalesOrders > 0 ||
+				strings.HasPrefix(table, "purchase_") && cfg.PurchaseOrders > 0 {
+				tables = append(tables, table)
+			}
+		}
+		if len(tables) == 0 {
+			return nil, errors.New("none of the selected tables are grown")
+		}
+		cfg.Tables = tables
+	}
+	return cfg, cfg.Validate()
+}
+
+// printSeedPlan prints what seeding cfg would write, for seed --dry-run
+func printSeedPlan(profile string, cfg *seeder.Config) {
+	fmt.Printf("Profile %s is valid\n", profile)
+	if cfg.RandomSeed != 0 {
+		fmt.Printf("Random seed %d\n", cfg.RandomSeed)
+	}
+	fmt.Println()
+
+	fmt.Printf("%-24s %14s %12s\n", "table", "rows", "disk")
+	for _, e := range cfg.TableEstimates() {
+		fmt.Printf("%-24s %14d %12s\n", e.Table, e.Rows, seeder.FormatBytes(e.Bytes))
+	}
+	fmt.Printf("%-24s %14d %12s\n", "total", cfg.TotalRecordsEstimate(), seeder.FormatBytes(cfg.DiskSizeEstimate()))
+}
+
+// seedProfile empties the tables a profile seeds and seeds them, with
+// --resume continues the last run, or with grow appends orders
+func seedProfile(db *database.DB, opts seedOptions) error {
+	cfg, err := opts.seedConfig()
+	if err != nil {
+		return err
+	}
+
+	s := seeder.New(db, cfg)
+	ctx := context.Background()
+
+	if opts.grow {
+		if err := s.Grow(ctx); err != nil {
+			return fmt.Errorf("growing failed: %w", err)
+		}
+		return nil
+	}
+
+	if opts.resume {
+		if err := s.Resume(ctx); err != nil {
+			return fmt.Errorf("resuming seeding failed: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.Truncate(ctx); err != nil {
+		return err
+	}
+	if err := s.SeedAll(ctx); err != nil {
+		return fmt.Errorf("seeding failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bananas/internal/database"
+	"bananas/internal/seeder"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// snapshotOptions are the subcommand and flags of the snapshot command
+type snapshotOptions struct {
+	action string // export or import
+	dir    string
+	seeder.SnapshotOptions
+}
+
+func parseSnapshotFlags(args []string, defaultProfile string) (snapshotOptions, error) {
+	var opts snapshotOptions
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		fmt.Println("Usage: go run cmd/migration/main.go snapshot export|import [--dir=snapshot] [flags]")
+		return opts, errors.New("snapshot needs export or import")
+	}
+	opts.action = args[0]
+
+	flags := flag.NewFlagSet("snapshot "+opts.action, flag.ContinueOnError)
+	flags.StringVar(&opts.dir, "dir", "snapshot", "snapshot directory")
+	flags.IntVar(&opts.Workers, "workers", 4, "tables copied at once")
+	if opts.action == "export" {
+		flags.StringVar(&opts.Compression, "compression", "zstd",
+			fmt.Sprintf("file compression: %s", strings.Join(seeder.Compressions, ", ")))
+		flags.StringVar(&opts.Profile, "profile", defaultProfile, "profile the database was seeded with, recorded in the manifest")
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// snapshot exports the seeded tables to a snapshot directory, or replaces
+// them with one
+func snapshot(db *database.DB, opts snapshotOptions) error {
+	ctx := context.Background()
+
+	if opts.action == "export" {
+		manifest, err := seeder.ExportSnapshot(ctx, db, opts.dir, opts.SnapshotOptions)
+		if err != nil {
+			return fmt.Errorf("snapshot export failed: %w", err)
+		}
+		var rows, bytes int64
+		for _, t := range manifest.Tables {
+			rows += t.Rows
+			bytes += t.Bytes
+		}
+		fmt.Printf("Exported %d rows in %d tables to %s (%s)\n", rows, len(manifest.Tables), opts.dir, seeder.FormatBytes(bytes))
+		fmt.Printf("Checksum %s\n", manifest.Checksum)
+		return nil
+	}
+
+	manifest, stats, err := seeder.ImportSnapshot(ctx, db, opts.dir, opts.SnapshotOptions)
+	if err != nil {
+		return fmt.Errorf("snapshot import failed: %w", err)
+	}
+	fmt.Printf("%-24s %12s %10s %10s %10s\n", "table", "rows", "copy", "index", "analyze")
+	for _, st := range stats {
+		fmt.Printf("%-24s %12d %10s %10s %10s\n", st.Table, st.Rows,
+			seeder.FormatDuration(st.Copy), seeder.FormatDuration(st.Index), seeder.FormatDuration(st.Analyze))
+	}
+	if manifest.Profile != "" || manifest.Seed != 0 {
+		fmt.Printf("Imported profile %s, seed %d\n", manifest.Profile, manifest.Seed)
+	}
+	fmt.Printf("Checksum %s, compare with the checksum command\n", manifest.Checksum)
+	return nil
+}
@@ -6,11 +6,17 @@ import (
 	"bananas/internal/logger"
 	"bananas/internal/seeder"
 	"context"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 )
 
 func main() {
+	profile := flag.String("profile", "small", "seeding profile: "+strings.Join(seeder.Profiles, ", ")+", or a YAML profile file")
+	randomSeed := flag.Uint64("seed", 0, "random seed; the same seed reproduces the same data, 0 keeps the profile's")
+	flag.Parse()
+
 	log := logger.New("test-seeder")
 
 	cfg, err := config.New()
@@ -26,8 +32,14 @@ func main() {
 	}
 	defer db.Close()
 
-	// Use small config for testing
-	seedCfg := seeder.SmallConfig()
+	seedCfg, err := seeder.LoadProfile(*profile)
+	if err != nil {
+		log.Er("invalid seeding profile", err)
+		os.Exit(1)
+	}
+	if *randomSeed != 0 {
+		seedCfg.RandomSeed = *randomSeed
+	}
 	log.Info(fmt.Sprintf("Starting seeding with config: %d products, %d customers", seedCfg.Products, seedCfg.Customers))
 
 	s := seeder.New(db, seedCfg)
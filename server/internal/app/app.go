@@ -19,14 +19,20 @@ type App struct {
 }
 
 func New() (*App, error) {
-	log := logger.New("app")
-
 	cfg, err := config.New()
 	if err != nil {
-		log.Er("failed to initialize config", err)
+		logger.New("app").Er("failed to initialize config", err)
 		return &App{}, err
 	}
 
+	return NewWithConfig(cfg)
+}
+
+// NewWithConfig initializes the application from an already loaded config,
+// letting callers such as cmd/api adjust it from flags first.
+func NewWithConfig(cfg config.Config) (*App, error) {
+	log := logger.New("app")
+
 	db, err := database.New(cfg)
 	if err != nil {
 		log.Er("failed to create database", err)
@@ -50,8 +56,12 @@ func New() (*App, error) {
 		return &App{}, err
 	}
 
-	baseController := controllers.New(service)
-	templController := controllers.NewTemplController(service, logger.New("templ-controller"))
+	baseController, err := controllers.New(service, cfg)
+	if err != nil {
+		log.Er("failed to initialize controllers", err)
+		return &App{}, err
+	}
+	templController := controllers.NewTemplController(service, cfg, logger.New("templ-controller"))
 
 	app := &App{
 		Database:       db,
@@ -0,0 +1,130 @@
+// Package auth implements the authentication workload: password hashing for
+// registration and login, and the JWTs that protected routes verify on every
+// request. Both are deliberately real, since CPU-bound hashing and per-request
+// signature checks are the costs being measured.
+package auth
+
+import (
+	"bananas/internal/config"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrMismatch is returned when a password does not match its hash.
+var ErrMismatch = errors.New("password does not match")
+
+const (
+	argon2SaltBytes = 16
+	argon2KeyBytes  = 32
+)
+
+// Passwords hashes new passwords with the configured algorithm and cost, and
+// checks passwords against hashes made by either algorithm, so changing
+// PASSWORD_HASH does not lock out existing users.
+type Passwords struct {
+	cfg config.AuthConfig
+	// dummy is checked in place of a missing user's hash, so a login for an
+	// unknown email costs as much as one with a wrong password.
+	dummy string
+}
+
+func NewPasswords(cfg config.AuthConfig) (*Passwords, error) {
+	p := &Passwords{cfg: cfg}
+	dummy, err := p.Hash("not a real password")
+	if err != nil {
+		return nil, err
+	}
+	p.dummy = dummy
+	return p, nil
+}
+
+// Algorithm is the algorithm new hashes are made with.
+func (p *Passwords) Algorithm() string {
+	return p.cfg.PasswordHash
+}
+
+// Hash hashes password with a fresh salt. bcrypt hashes are in the usual
+// $2a$ form and argon2id ones in the PHC string format, so both carry their
+// own parameters.
+func (p *Passwords) Hash(password string) (string, error) {
+	if p.cfg.PasswordHash == config.PasswordArgon2id {
+		return p.hashArgon2id(password)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), p.cfg.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Check returns ErrMismatch unless password matches hash.
+func (p *Passwords) Check(hash, password string) error {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		return checkArgon2id(hash, password)
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatch
+	}
+	return err
+}
+
+// CheckMissing does the work of a Check for a user that does not exist, and
+// always fails.
+func (p *Passwords) CheckMissing(password string) error {
+	p.Check(p.dummy, password)
+	return ErrMismatch
+}
+
+func (p *Passwords) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.cfg.Argon2Time, p.cfg.Argon2MemoryKiB, p.cfg.Argon2Threads, argon2KeyBytes)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version,
+		p.cfg.Argon2MemoryKiB, p.cfg.Argon2Time, p.cfg.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// checkArgon2id rehashes password with the parameters and salt stored in
+// hash and compares the keys in constant time.
+func checkArgon2id(hash, password string) error {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return errors.New("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return fmt.Errorf("unsupported argon2id version %q", parts[2])
+	}
+	var memory, time uint32
+	var threads uint8
+	// argon2 panics on zero passes or lanes
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil || time < 1 || threads < 1 {
+		return fmt.Errorf("malformed argon2id parameters %q", parts[3])
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return errors.New("malformed argon2id salt")
+	}
+	// An empty key would match any password
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return errors.New("malformed argon2id key")
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
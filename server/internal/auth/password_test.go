@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"bananas/internal/config"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckArgon2id(t *testing.T) {
+	p, err := NewPasswords(config.AuthConfig{
+		PasswordHash:    config.PasswordArgon2id,
+		Argon2Time:      1,
+		Argon2MemoryKiB: 64,
+		Argon2Threads:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := p.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(hash, "$")
+	with := func(i int, part string) string {
+		edited := append([]string(nil), parts...)
+		edited[i] = part
+		return strings.Join(edited, "$")
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     error // nil, ErrMismatch, or errMalformed for any other error
+	}{
+		{name: "match", hash: hash, password: "secret"},
+		{name: "wrong password", hash: hash, password: "Secret", want: ErrMismatch},
+		{name: "empty password", hash: hash, password: "", want: ErrMismatch},
+		{name: "other salt", hash: with(4, "AAAAAAAAAAAAAAAAAAAAAA"), password: "secret", want: ErrMismatch},
+		{name: "other time", hash: with(3, "m=64,t=2,p=1"), password: "secret", want: ErrMismatch},
+		{name: "too few parts", hash: strings.Join(parts[:5], "$"), password: "secret", want: errMalformed},
+		{name: "too many parts", hash: hash + "$x", password: "secret", want: errMalformed},
+		{name: "other version", hash: with(2, "v=16"), password: "secret", want: errMalformed},
+		{name: "no version", hash: with(2, "19"), password: "secret", want: errMalformed},
+		{name: "malformed parameters", hash: with(3, "m=64;t=1;p=1"), password: "secret", want: errMalformed},
+		{name: "missing parameter", hash: with(3, "m=64,t=1"), password: "secret", want: errMalformed},
+		{name: "zero time", hash: with(3, "m=64,t=0,p=1"), password: "secret", want: errMalformed},
+		{name: "zero threads", hash: with(3, "m=64,t=1,p=0"), password: "secret", want: errMalformed},
+		{name: "threads overflow", hash: with(3, "m=64,t=1,p=256"), password: "secret", want: errMalformed},
+		{name: "malformed salt", hash: with(4, "!!!"), password: "secret", want: errMalformed},
+		{name: "malformed key", hash: with(5, "!!!"), password: "secret", want: errMalformed},
+		{name: "empty key", hash: with(5, ""), password: "secret", want: errMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkArgon2id(tt.hash, tt.password)
+			switch {
+			case tt.want == errMalformed:
+				if err == nil || errors.Is(err, ErrMismatch) {
+					t.Errorf("checkArgon2id = %v, want a malformed hash error", err)
+				}
+			case !errors.Is(err, tt.want):
+				t.Errorf("checkArgon2id = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// errMalformed stands for any error but ErrMismatch in test tables
+var errMalformed = errors.New("malformed")
+
+func TestCheckBothAlgorithms(t *testing.T) {
+	bcrypt, err := NewPasswords(config.AuthConfig{PasswordHash: config.PasswordBcrypt, BcryptCost: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon2id, err := NewPasswords(config.AuthConfig{
+		PasswordHash:    config.PasswordArgon2id,
+		Argon2Time:      1,
+		Argon2MemoryKiB: 64,
+		Argon2Threads:   1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hasher := range []*Passwords{bcrypt, argon2id} {
+		hash, err := hasher.Hash("secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Either checks hashes of both, so switching algorithm keeps users
+		for _, checker := range []*Passwords{bcrypt, argon2id} {
+			if err := checker.Check(hash, "secret"); err != nil {
+				t.Errorf("%s checking a %s hash: %v", checker.Algorithm(), hasher.Algorithm(), err)
+			}
+			if err := checker.Check(hash, "other"); !errors.Is(err, ErrMismatch) {
+				t.Errorf("%s checking a %s hash with the wrong password = %v, want ErrMismatch", checker.Algorithm(), hasher.Algorithm(), err)
+			}
+		}
+	}
+}
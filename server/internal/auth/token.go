@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"bananas/internal/config"
+	"bananas/internal/problem"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const issuer = "bananas"
+
+// Errors returned by Authenticate. Both mean the request is unauthorized.
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// Claims are what a token carries about the user it was issued to. The
+// user ID is the subject.
+type Claims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// UserID parses the subject back into the user's ID.
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// Tokens issues and verifies HS256 signed JWTs.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	parser *jwt.Parser
+}
+
+func NewTokens(cfg config.AuthConfig) *Tokens {
+	return &Tokens{
+		secret: []byte(cfg.JWTSecret),
+		ttl:    cfg.TokenTTL,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithIssuer(issuer),
+			jwt.WithExpirationRequired(),
+		),
+	}
+}
+
+// Issue signs a token for the user, returning it with its expiry.
+func (t *Tokens) Issue(userID uuid.UUID, email string) (string, time.Time, error) {
+	now := time.Now()
+	expires := now.Add(t.ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+	})
+	signed, err := token.SignedString(t.secret)
+	return signed, expires, err
+}
+
+// Verify checks token's signature, issuer and expiry and returns its claims.
+func (t *Tokens) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := t.parser.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return t.secret, nil
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// Authenticate verifies the bearer token in an Authorization header value.
+// It is what the auth middleware of every framework calls.
+func (t *Tokens) Authenticate(authorization string) (*Claims, error) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrMissingToken
+	}
+	return t.Verify(strings.TrimSpace(token))
+}
+
+// Challenge is the WWW-Authenticate value sent with a 401 for err.
+func Challenge(err error) string {
+	if errors.Is(err, ErrInvalidToken) {
+		return `Bearer realm="bananas", error="invalid_token"`
+	}
+	return `Bearer realm="bananas"`
+}
+
+// Unauthorized is the problem sent with a 401 for err.
+func Unauthorized(err error) problem.Problem {
+	return problem.New(http.StatusUnauthorized, err.Error())
+}
+
+type claimsKey struct{}
+
+// WithClaims returns ctx carrying the claims of an authenticated request.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFrom returns the claims the auth middleware put in ctx, if any.
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// WriteUnauthorized answers a net/http request that failed Authenticate.
+func WriteUnauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", Challenge(err))
+	problem.Write(w, r, Unauthorized(err))
+}
@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrUnsupported is returned by encoders that cannot represent a value, such
+// as Protobuf for a response that has no message definition.
+var ErrUnsupported = errors.New("response has no representation in this format")
+
+// msgpackEncoder writes MessagePack, reusing the json tags so field names
+// match the JSON responses.
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) Name() string { return "msgpack" }
+func (msgpackEncoder) Marshal(v any) ([]byte, error) {
+	enc := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(enc)
+
+	var buf bytes.Buffer
+	enc.Reset(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cborMode encodes times as RFC 3339 strings, like JSON, and sorts map keys
+// so output is deterministic. fxamacker/cbor reads json tags by default.
+var cborMode, _ = cbor.EncOptions{
+	Sort: cbor.SortCanonical,
+	Time: cbor.TimeRFC3339Nano,
+}.EncMode()
+
+type cborEncoder struct{}
+
+func (cborEncoder) Name() string                  { return "cbor" }
+func (cborEncoder) Marshal(v any) ([]byte, error) { return cborMode.Marshal(v) }
+
+// ProtoConvertible is implemented by responses that have a Protobuf form.
+type ProtoConvertible interface {
+	Proto() proto.Message
+}
+
+type protobufEncoder struct{}
+
+func (protobufEncoder) Name() string { return "protobuf" }
+func (protobufEncoder) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case proto.Message:
+		return proto.Marshal(m)
+	case ProtoConvertible:
+		return proto.Marshal(m.Proto())
+	}
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,40 @@
+// Package codec holds the interchangeable encoders used to serialise API
+// responses, so serialisation cost can be compared independently of the
+// framework serving the request.
+package codec
+
+import (
+	"sort"
+)
+
+// Encoder turns a response value into bytes.
+type Encoder interface {
+	// Name is the identifier used in config and the ?codec= query parameter.
+	Name() string
+	Marshal(v any) ([]byte, error)
+}
+
+// DefaultJSON is the JSON encoder used when nothing else is configured.
+const DefaultJSON = "std"
+
+var jsonEncoders = map[string]Encoder{}
+
+func registerJSON(e Encoder) {
+	jsonEncoders[e.Name()] = e
+}
+
+// JSON returns the JSON encoder registered under name.
+func JSON(name string) (Encoder, bool) {
+	e, ok := jsonEncoders[name]
+	return e, ok
+}
+
+// JSONNames lists the registered JSON encoders in a stable order.
+func JSONNames() []string {
+	names := make([]string, 0, len(jsonEncoders))
+	for name := range jsonEncoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,42 @@
+package codec
+
+import (
+	"encoding/json"
+
+	"github.com/bytedance/sonic"
+	gojson "github.com/goccy/go-json"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func init() {
+	registerJSON(stdJSON{})
+	registerJSON(sonicJSON{})
+	registerJSON(goJSON{})
+	registerJSON(jsoniterJSON{})
+}
+
+// stdJSON is encoding/json, the baseline every other encoder is measured against.
+type stdJSON struct{}
+
+func (stdJSON) Name() string                  { return "std" }
+func (stdJSON) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// sonicJSON is bytedance/sonic's JIT encoder, as used by Gin's sonic build tag.
+type sonicJSON struct{}
+
+func (sonicJSON) Name() string                  { return "sonic" }
+func (sonicJSON) Marshal(v any) ([]byte, error) { return sonic.Marshal(v) }
+
+// goJSON is goccy/go-json, as used by Gin's go_json build tag.
+type goJSON struct{}
+
+func (goJSON) Name() string                  { return "gojson" }
+func (goJSON) Marshal(v any) ([]byte, error) { return gojson.Marshal(v) }
+
+// jsoniterJSON is json-iterator configured to match encoding/json output.
+type jsoniterJSON struct{}
+
+func (jsoniterJSON) Name() string { return "jsoniter" }
+func (jsoniterJSON) Marshal(v any) ([]byte, error) {
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(v)
+}
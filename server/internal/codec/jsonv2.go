@@ -0,0 +1,20 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+package codec
+
+import (
+	jsonv2 "encoding/json/v2"
+)
+
+// json/v2 is only in the standard library when the toolchain builds with the
+// jsonv2 experiment; it is on by default from Go 1.27, the first release whose
+// json/v2 API this targets.
+func init() {
+	registerJSON(jsonV2{})
+}
+
+// jsonV2 is encoding/json/v2 with its default (v2) semantics.
+type jsonV2 struct{}
+
+func (jsonV2) Name() string                  { return "jsonv2" }
+func (jsonV2) Marshal(v any) ([]byte, error) { return jsonv2.Marshal(v) }
@@ -0,0 +1,143 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Media types the API can respond with.
+const (
+	MediaJSON     = "application/json"
+	MediaMsgpack  = "application/msgpack"
+	MediaCBOR     = "application/cbor"
+	MediaProtobuf = "application/x-protobuf"
+)
+
+// Media types for streamed responses, which carry one JSON document per
+// event or line.
+const (
+	MediaEventStream = "text/event-stream"
+	MediaNDJSON      = "application/x-ndjson"
+)
+
+// binaryEncoders maps each non-JSON media type, and the aliases clients
+// commonly send for it, to its encoder and canonical media type.
+var binaryEncoders = map[string]struct {
+	mediaType string
+	encoder   Encoder
+}{
+	MediaMsgpack:                      {MediaMsgpack, msgpackEncoder{}},
+	"application/x-msgpack":           {MediaMsgpack, msgpackEncoder{}},
+	"application/vnd.msgpack":         {MediaMsgpack, msgpackEncoder{}},
+	MediaCBOR:                         {MediaCBOR, cborEncoder{}},
+	MediaProtobuf:                     {MediaProtobuf, protobufEncoder{}},
+	"application/protobuf":            {MediaProtobuf, protobufEncoder{}},
+	"application/vnd.google.protobuf": {MediaProtobuf, protobufEncoder{}},
+}
+
+// Negotiate picks the response media type and encoder for an Accept header,
+// using jsonEncoder whenever JSON wins. An empty header or a wildcard means
+// JSON. ok is false when the client accepts none of the supported types.
+func Negotiate(accept string, jsonEncoder Encoder) (mediaType string, encoder Encoder, ok bool) {
+	if strings.TrimSpace(accept) == "" {
+		return MediaJSON, jsonEncoder, true
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		switch mediaRange {
+		case MediaJSON, "*/*", "application/*":
+			return MediaJSON, jsonEncoder, true
+		}
+		if b, found := binaryEncoders[mediaRange]; found {
+			return b.mediaType, b.encoder, true
+		}
+	}
+	return "", nil, false
+}
+
+// NegotiateStream picks the format of a streamed response: NDJSON when the
+// client prefers it, Server-Sent Events otherwise.
+func NegotiateStream(accept string) string {
+	for _, mediaRange := range parseAccept(accept) {
+		switch mediaRange {
+		case MediaNDJSON:
+			return MediaNDJSON
+		case MediaEventStream:
+			return MediaEventStream
+		}
+	}
+	return MediaEventStream
+}
+
+// AcceptsStream reports whether the client asks for a streamed format.
+func AcceptsStream(accept string) bool {
+	for _, mediaRange := range parseAccept(accept) {
+		if mediaRange == MediaEventStream || mediaRange == MediaNDJSON {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccept returns the acceptable media ranges ordered by preference,
+// dropping any with q=0.
+func parseAccept(accept string) []string {
+	type weighted struct {
+		mediaRange string
+		q          float64
+	}
+
+	var ranges []weighted
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, found := params["q"]; found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{mediaRange, q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	result := make([]string, len(ranges))
+	for i, r := range ranges {
+		result[i] = r.mediaRange
+	}
+	return result
+}
+
+// Unmarshal decodes a response body of the given media type into v. For
+// Protobuf v must be a proto.Message; the other formats accept any target,
+// including *any.
+func Unmarshal(mediaType string, data []byte, v any) error {
+	switch mediaType {
+	case MediaJSON:
+		return json.Unmarshal(data, v)
+	case MediaMsgpack:
+		return msgpack.Unmarshal(data, v)
+	case MediaCBOR:
+		return cbor.Unmarshal(data, v)
+	case MediaProtobuf:
+		m, ok := v.(proto.Message)
+		if !ok {
+			return ErrUnsupported
+		}
+		return proto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("unsupported media type: %s", mediaType)
+}
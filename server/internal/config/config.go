@@ -1,13 +1,37 @@
 package config
 
 import (
+	"bananas/internal/codec"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	ServerPort     string
 	DatabaseConfig DatabaseConfig
+	Frameworks     []FrameworkConfig
+
+	// LocalFrameworks restricts this process to serving the named frameworks.
+	// The rest of the enabled set is assumed to run in sibling processes (see
+	// cmd/api --supervise). Empty means this process serves every enabled framework.
+	LocalFrameworks []string
+
+	// UploadDir is where the file upload endpoints write to, and
+	// MaxUploadBytes the largest body they accept. Uploads bypass the
+	// middleware body limit, which is sized for API requests.
+	UploadDir      string
+	MaxUploadBytes int64
+
+	Auth AuthConfig
+
+	// SeedProfile is the seeder profile cmd/migration up seeds an empty
+	// database with: a built-in profile name or a YAML profile file.
+	SeedProfile string
 }
 
 type DatabaseConfig struct {
@@ -19,11 +43,139 @@ type DatabaseConfig struct {
 	SSLMode       string
 	AdminUser     string
 	AdminPassword string
+	MaxConns      int // per pool, 0 keeps each driver's default
+}
+
+// AuthConfig sets the cost of the authentication endpoints: how passwords
+// are hashed at registration and checked at login, and how the JWTs issued
+// at login are signed.
+type AuthConfig struct {
+	// PasswordHash is the algorithm new passwords are hashed with. Login
+	// checks a stored hash with whichever algorithm produced it.
+	PasswordHash string
+	BcryptCost   int
+
+	// Argon2id parameters: passes over memory, memory in KiB and lanes.
+	Argon2Time      uint32
+	Argon2MemoryKiB uint32
+	Argon2Threads   uint8
+
+	JWTSecret string
+	TokenTTL  time.Duration
+}
+
+// Password hash algorithms.
+const (
+	PasswordBcrypt   = "bcrypt"
+	PasswordArgon2id = "argon2id"
+)
+
+// FrameworkConfig describes a single HTTP framework server: whether it starts,
+// where it listens and the limits applied to its connections.
+type FrameworkConfig struct {
+	Name           string
+	DisplayName    string
+	Enabled        bool
+	Host           string
+	Port           string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	Middleware     MiddlewareConfig
+	Protocol       string
+	TLS            TLSConfig
+
+	// Socket, when set, is a Unix domain socket path served alongside TCP.
+	Socket string
+	// Listeners is the number of SO_REUSEPORT TCP listeners to accept on.
+	// Fiber maps anything above 1 onto Prefork instead.
+	Listeners int
+
+	// JSONCodec names the codec.Encoder used for JSON responses unless a
+	// request picks another with ?codec=.
+	JSONCodec string
+}
+
+// Listener protocols a framework can be served over.
+const (
+	ProtocolHTTP1 = "http1" // cleartext HTTP/1.1
+	ProtocolHTTPS = "https" // HTTP/1.1 over TLS
+	ProtocolH2    = "h2"    // HTTP/2 over TLS, HTTP/1.1 fallback via ALPN
+	ProtocolH2C   = "h2c"   // cleartext HTTP/2 with prior knowledge, plus HTTP/1.1
+	ProtocolH3    = "h3"    // HTTP/3 over QUIC, with h2 on TCP advertising it via Alt-Svc
+)
+
+// TLSConfig points at the certificate used by TLS listeners. When the files
+// don't exist a self-signed localhost certificate is generated there.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Middleware stack names, from cheapest to most complete.
+const (
+	MiddlewareNone       = "none"
+	MiddlewareMinimal    = "minimal"
+	MiddlewareProduction = "production"
+)
+
+// MiddlewareConfig selects the middleware stack a framework runs. Every
+// framework maps the same stack onto its own middleware so per-request
+// overhead stays comparable:
+//
+//	none       - nothing beyond routing
+//	minimal    - panic recovery and CORS
+//	production - request ID, access log, recovery, CORS, gzip/brotli,
+//	             request timeout and body size limit
+type MiddlewareConfig struct {
+	Stack          string
+	RequestTimeout time.Duration
+	MaxBodyBytes   int64
+}
+
+// Minimal reports whether recovery and CORS should be installed.
+func (m MiddlewareConfig) Minimal() bool {
+	return m.Stack == MiddlewareMinimal || m.Stack == MiddlewareProduction
+}
+
+// Production reports whether the full production stack should be installed.
+func (m MiddlewareConfig) Production() bool {
+	return m.Stack == MiddlewareProduction
+}
+
+// frameworkDefaults lists every supported framework in display order along
+// with the env prefix and port used when nothing is configured.
+var frameworkDefaults = []struct {
+	name        string
+	displayName string
+	envPrefix   string
+	port        string
+}{
+	{"standard", "Standard Library", "STANDARD", "8081"},
+	{"gin", "Gin", "GIN", "8082"},
+	{"fiber", "Fiber", "FIBER", "8083"},
+	{"echo", "Echo", "ECHO", "8084"},
+	{"chi", "Chi", "CHI", "8085"},
+	{"gorilla", "Gorilla Mux", "GORILLA", "8086"},
+	{"grpc", "gRPC", "GRPC", "8087"},
+	{"connect", "Connect", "CONNECT", "8088"},
 }
 
 func New() (Config, error) {
 	dbUser := getEnv("DB_USER", "bananas_user")
 	dbPassword := getEnv("DB_PASSWORD", "bananas_pass")
+	env := &envReader{}
+
+	frameworks, err := loadFrameworks()
+	if err != nil {
+		return Config{}, err
+	}
+
+	auth, err := loadAuth()
+	if err != nil {
+		return Config{}, err
+	}
 
 	config := Config{
 		ServerPort: getEnv("SERVER_PORT", "8080"),
@@ -36,12 +188,227 @@ func New() (Config, error) {
 			SSLMode:       getEnv("DB_SSL_MODE", "disable"),
 			AdminUser:     getEnv("DB_ADMIN_USER", dbUser),
 			AdminPassword: getEnv("DB_ADMIN_PASSWORD", dbPassword),
+			MaxConns:      env.getInt("DB_MAX_CONNS", 0),
 		},
+		Frameworks:     frameworks,
+		UploadDir:      getEnv("UPLOAD_DIR", os.TempDir()),
+		MaxUploadBytes: int64(env.getInt("MAX_UPLOAD_BYTES", 1<<30)),
+		Auth:           auth,
+		SeedProfile:    getEnv("SEED_PROFILE", "default"),
+	}
+	if env.err != nil {
+		return Config{}, env.err
 	}
 
 	return config, nil
 }
 
+// loadFrameworks builds the framework list from the environment. FRAMEWORKS
+// selects which servers start (comma separated, default all); BIND_HOST and the
+// SERVER_* timeouts apply to every server unless overridden by a
+// <FRAMEWORK>_HOST, <FRAMEWORK>_PORT or <FRAMEWORK>_READ_TIMEOUT style variable.
+// MIDDLEWARE_STACK picks none, minimal or production for every server, and
+// <FRAMEWORK>_MIDDLEWARE overrides it per framework. SERVER_PROTOCOL and
+// <FRAMEWORK>_PROTOCOL work the same way for the listener protocol.
+// SOCKET_DIR adds a <dir>/<framework>.sock Unix socket listener to every
+// server (<FRAMEWORK>_SOCKET sets an explicit path), and SERVER_LISTENERS or
+// <FRAMEWORK>_LISTENERS opens that many SO_REUSEPORT TCP listeners.
+// JSON_CODEC and <FRAMEWORK>_JSON_CODEC choose the response JSON encoder.
+func loadFrameworks() ([]FrameworkConfig, error) {
+	enabled := map[string]bool{}
+	for _, name := range getEnvList("FRAMEWORKS") {
+		enabled[name] = true
+	}
+	for name := range enabled {
+		if !isKnownFramework(name) {
+			return nil, fmt.Errorf("unknown framework in FRAMEWORKS: %s", name)
+		}
+	}
+
+	env := &envReader{}
+	host := getEnv("BIND_HOST", "")
+	readTimeout := env.getDuration("SERVER_READ_TIMEOUT", 5*time.Second)
+	writeTimeout := env.getDuration("SERVER_WRITE_TIMEOUT", 10*time.Second)
+	idleTimeout := env.getDuration("SERVER_IDLE_TIMEOUT", 60*time.Second)
+	// Fiber allocates its read buffer at this size per connection, so keep the
+	// shared default modest rather than net/http's 1MB.
+	maxHeaderBytes := env.getInt("SERVER_MAX_HEADER_BYTES", 8192)
+	stack := getEnv("MIDDLEWARE_STACK", MiddlewareMinimal)
+	requestTimeout := env.getDuration("REQUEST_TIMEOUT", 5*time.Second)
+	maxBodyBytes := env.getInt("MAX_BODY_BYTES", 1<<20)
+	protocol := getEnv("SERVER_PROTOCOL", ProtocolHTTP1)
+	if !isKnownProtocol(protocol) {
+		return nil, fmt.Errorf("unknown SERVER_PROTOCOL: %s", protocol)
+	}
+	jsonCodec := getEnv("JSON_CODEC", codec.DefaultJSON)
+	socketDir := getEnv("SOCKET_DIR", "")
+	listeners := env.getInt("SERVER_LISTENERS", 1)
+	tlsConfig := TLSConfig{
+		CertFile: getEnv("TLS_CERT_FILE", "certs/localhost.crt"),
+		KeyFile:  getEnv("TLS_KEY_FILE", "certs/localhost.key"),
+	}
+
+	frameworks := make([]FrameworkConfig, 0, len(frameworkDefaults))
+	for _, d := range frameworkDefaults {
+		fwStack := getEnv(d.envPrefix+"_MIDDLEWARE", stack)
+		if !isKnownMiddlewareStack(fwStack) {
+			return nil, fmt.Errorf("unknown middleware stack for %s: %s", d.name, fwStack)
+		}
+
+		fwProtocol := protocol
+		if override := os.Getenv(d.envPrefix + "_PROTOCOL"); override != "" {
+			if !isKnownProtocol(override) || !supportsProtocol(d.name, override) {
+				return nil, fmt.Errorf("%s cannot be served over %s", d.name, override)
+			}
+			fwProtocol = override
+		} else if !supportsProtocol(d.name, fwProtocol) {
+			fwProtocol = fallbackProtocol(d.name, fwProtocol)
+		}
+
+		socket := getEnv(d.envPrefix+"_SOCKET", "")
+		if socket == "" && socketDir != "" {
+			socket = filepath.Join(socketDir, d.name+".sock")
+		}
+		if socket != "" && fwProtocol == ProtocolH3 {
+			return nil, fmt.Errorf("%s cannot serve h3 over a unix socket", d.name)
+		}
+
+		fwListeners := env.getInt(d.envPrefix+"_LISTENERS", listeners)
+		if fwListeners < 1 {
+			return nil, fmt.Errorf("%s needs at least one listener", d.name)
+		}
+		if d.name == "fiber" && fwListeners > 1 && socket != "" {
+			return nil, fmt.Errorf("fiber prefork cannot be combined with a unix socket")
+		}
+
+		fwCodec := getEnv(d.envPrefix+"_JSON_CODEC", jsonCodec)
+		if _, ok := codec.JSON(fwCodec); !ok {
+			return nil, fmt.Errorf("unknown JSON codec for %s: %s (available: %s)",
+				d.name, fwCodec, strings.Join(codec.JSONNames(), ", "))
+		}
+
+		frameworks = append(frameworks, FrameworkConfig{
+			Name:           d.name,
+			DisplayName:    d.displayName,
+			Enabled:        len(enabled) == 0 || enabled[d.name],
+			Host:           getEnv(d.envPrefix+"_HOST", host),
+			Port:           getEnv(d.envPrefix+"_PORT", d.port),
+			ReadTimeout:    env.getDuration(d.envPrefix+"_READ_TIMEOUT", readTimeout),
+			WriteTimeout:   env.getDuration(d.envPrefix+"_WRITE_TIMEOUT", writeTimeout),
+			IdleTimeout:    env.getDuration(d.envPrefix+"_IDLE_TIMEOUT", idleTimeout),
+			MaxHeaderBytes: env.getInt(d.envPrefix+"_MAX_HEADER_BYTES", maxHeaderBytes),
+			Middleware: MiddlewareConfig{
+				Stack:          fwStack,
+				RequestTimeout: requestTimeout,
+				MaxBodyBytes:   int64(maxBodyBytes),
+			},
+			Protocol:  fwProtocol,
+			TLS:       tlsConfig,
+			Socket:    socket,
+			Listeners: fwListeners,
+			JSONCodec: fwCodec,
+		})
+	}
+	if env.err != nil {
+		return nil, env.err
+	}
+
+	return frameworks, nil
+}
+
+// loadAuth reads the auth settings. PASSWORD_HASH picks bcrypt (cost
+// BCRYPT_COST) or argon2id (ARGON2_TIME, ARGON2_MEMORY_KIB, ARGON2_THREADS);
+// the defaults are the usual interactive login costs. JWT_SECRET signs tokens
+// and must be shared by every process so a token from one framework is
+// accepted by the rest.
+func loadAuth() (AuthConfig, error) {
+	env := &envReader{}
+	auth := AuthConfig{
+		PasswordHash:    getEnv("PASSWORD_HASH", PasswordBcrypt),
+		BcryptCost:      env.getInt("BCRYPT_COST", 10),
+		Argon2Time:      uint32(env.getInt("ARGON2_TIME", 2)),
+		Argon2MemoryKiB: uint32(env.getInt("ARGON2_MEMORY_KIB", 19*1024)),
+		Argon2Threads:   uint8(env.getInt("ARGON2_THREADS", 1)),
+		JWTSecret:       getEnv("JWT_SECRET", "bananas-dev-secret"),
+		TokenTTL:        env.getDuration("JWT_TTL", 15*time.Minute),
+	}
+	if env.err != nil {
+		return AuthConfig{}, env.err
+	}
+
+	switch auth.PasswordHash {
+	case PasswordBcrypt:
+		if auth.BcryptCost < 4 || auth.BcryptCost > 31 {
+			return AuthConfig{}, fmt.Errorf("BCRYPT_COST must be between 4 and 31, got %d", auth.BcryptCost)
+		}
+	case PasswordArgon2id:
+		if auth.Argon2Time < 1 || auth.Argon2MemoryKiB < 8*uint32(auth.Argon2Threads) || auth.Argon2Threads < 1 {
+			return AuthConfig{}, fmt.Errorf("invalid argon2id parameters: time %d, memory %dKiB, threads %d",
+				auth.Argon2Time, auth.Argon2MemoryKiB, auth.Argon2Threads)
+		}
+	default:
+		return AuthConfig{}, fmt.Errorf("unknown PASSWORD_HASH: %s (available: %s, %s)", auth.PasswordHash, PasswordBcrypt, PasswordArgon2id)
+	}
+	if auth.TokenTTL <= 0 {
+		return AuthConfig{}, fmt.Errorf("JWT_TTL must be positive")
+	}
+	return auth, nil
+}
+
+func isKnownFramework(name string) bool {
+	for _, d := range frameworkDefaults {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isKnownMiddlewareStack(stack string) bool {
+	switch stack {
+	case MiddlewareNone, MiddlewareMinimal, MiddlewareProduction:
+		return true
+	}
+	return false
+}
+
+func isKnownProtocol(protocol string) bool {
+	switch protocol {
+	case ProtocolHTTP1, ProtocolHTTPS, ProtocolH2, ProtocolH2C, ProtocolH3:
+		return true
+	}
+	return false
+}
+
+// supportsProtocol reports whether a framework can be served over protocol.
+// Fiber runs on fasthttp, which only speaks HTTP/1.1, and gRPC needs HTTP/2;
+// everything else is served by net/http and can use any protocol.
+func supportsProtocol(framework, protocol string) bool {
+	switch framework {
+	case "fiber":
+		return protocol == ProtocolHTTP1 || protocol == ProtocolHTTPS
+	case "grpc":
+		return protocol == ProtocolH2 || protocol == ProtocolH2C
+	}
+	return true
+}
+
+// fallbackProtocol picks the closest protocol a framework can speak when the
+// global one is unsupported, keeping TLS if TLS was asked for.
+func fallbackProtocol(framework, protocol string) string {
+	tls := protocol == ProtocolHTTPS || protocol == ProtocolH2 || protocol == ProtocolH3
+	if framework == "grpc" {
+		if tls {
+			return ProtocolH2
+		}
+		return ProtocolH2C
+	}
+	if tls {
+		return ProtocolHTTPS
+	}
+	return ProtocolHTTP1
+}
+
 func (c Config) GetDatabaseDSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.DatabaseConfig.Host,
@@ -53,9 +420,144 @@ func (c Config) GetDatabaseDSN() string {
 	)
 }
 
+// EnabledFrameworks returns the frameworks that should be started, in display order.
+func (c Config) EnabledFrameworks() []FrameworkConfig {
+	enabled := make([]FrameworkConfig, 0, len(c.Frameworks))
+	for _, fw := range c.Frameworks {
+		if fw.Enabled {
+			enabled = append(enabled, fw)
+		}
+	}
+	return enabled
+}
+
+// ServedFrameworks returns the enabled frameworks this process should start.
+func (c Config) ServedFrameworks() []FrameworkConfig {
+	if len(c.LocalFrameworks) == 0 {
+		return c.EnabledFrameworks()
+	}
+
+	served := make([]FrameworkConfig, 0, len(c.LocalFrameworks))
+	for _, fw := range c.EnabledFrameworks() {
+		for _, name := range c.LocalFrameworks {
+			if fw.Name == name {
+				served = append(served, fw)
+			}
+		}
+	}
+	return served
+}
+
+// IsolationMode reports how the frameworks served by this process are
+// isolated: "process" when it serves exactly one, "shared" otherwise.
+func (c Config) IsolationMode() string {
+	if len(c.ServedFrameworks()) == 1 {
+		return "process"
+	}
+	return "shared"
+}
+
+// Framework looks up a framework by name, whether or not it is enabled.
+func (c Config) Framework(name string) (FrameworkConfig, bool) {
+	for _, fw := range c.Frameworks {
+		if fw.Name == name {
+			return fw, true
+		}
+	}
+	return FrameworkConfig{}, false
+}
+
+// Addr returns the listen address in host:port form.
+func (f FrameworkConfig) Addr() string {
+	return net.JoinHostPort(f.Host, f.Port)
+}
+
+// UsesTLS reports whether the framework's listener is served over TLS.
+func (f FrameworkConfig) UsesTLS() bool {
+	switch f.Protocol {
+	case ProtocolHTTPS, ProtocolH2, ProtocolH3:
+		return true
+	}
+	return false
+}
+
+// IsRPC reports whether the framework serves BananasService rather than the
+// HTTP API endpoints.
+func (f FrameworkConfig) IsRPC() bool {
+	return f.Name == "grpc" || f.Name == "connect"
+}
+
+// DialAddr returns the host:port clients on this machine dial, substituting
+// localhost for a wildcard bind address.
+func (f FrameworkConfig) DialAddr() string {
+	host := f.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, f.Port)
+}
+
+// BaseURL returns the URL clients on this machine use to reach the server.
+func (f FrameworkConfig) BaseURL() string {
+	scheme := "http"
+	if f.UsesTLS() {
+		scheme = "https"
+	}
+	return scheme + "://" + f.DialAddr()
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// envReader reads numeric settings and keeps the first one that fails to
+// parse, so a mistyped value stops startup instead of quietly becoming the
+// default.
+type envReader struct {
+	err error
+}
+
+func (r *envReader) getInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		r.fail(fmt.Errorf("%s must be an integer, got %q", key, value))
+		return defaultValue
+	}
+	return parsed
+}
+
+func (r *envReader) getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		r.fail(fmt.Errorf("%s must be a duration such as 5s, got %q", key, value))
+		return defaultValue
+	}
+	return parsed
+}
+
+func (r *envReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
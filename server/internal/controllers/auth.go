@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"bananas/internal/auth"
+	"bananas/internal/models"
+	"bananas/internal/repositories"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Password length limits. bcrypt ignores everything past 72 bytes, so longer
+// passwords are refused rather than silently truncated.
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// Credentials is the body /api/auth/register and /api/auth/login take.
+type Credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RegisterResponse is the user a registration created.
+type RegisterResponse struct {
+	User      *models.User `json:"user"`
+	Algorithm string       `json:"algorithm"`
+	Framework string       `json:"framework"`
+}
+
+// TokenResponse carries the bearer token a login issued.
+type TokenResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Framework string    `json:"framework"`
+}
+
+// Register creates a user with a hashed password, answering 409 when the
+// email is taken. The hash and the insert are reported separately in
+// Server-Timing, as the hash is usually the larger cost.
+func (c *BaseController) Register(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	creds, ok := c.readCredentials(w, r)
+	if !ok {
+		return
+	}
+	if addr, err := mail.ParseAddress(creds.Email); err != nil || addr.Address != creds.Email {
+		c.WriteError(w, r, http.StatusBadRequest, "Invalid email address")
+		return
+	}
+	if len(creds.Password) < minPasswordLength || len(creds.Password) > maxPasswordLength {
+		c.WriteError(w, r, http.StatusBadRequest, "Password must be between 8 and 72 bytes")
+		return
+	}
+
+	hashStart := time.Now()
+	hash, err := c.passwords.Hash(creds.Password)
+	hashTime := time.Since(hashStart)
+	if err != nil {
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to hash password")
+		c.Logger.Er("failed to hash password", err)
+		return
+	}
+
+	ormType := r.URL.Query().Get("orm")
+	if ormType == "" {
+		ormType = "sql"
+	}
+	user := &models.User{Email: creds.Email, PasswordHash: hash}
+	dbStart := time.Now()
+	err = c.Service.CreateUser(r.Context(), ormType, user)
+	dbTime := time.Since(dbStart)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		c.WriteError(w, r, http.StatusConflict, "Email is already registered")
+		return
+	}
+	if err != nil {
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to create user")
+		return
+	}
+
+	framework, _ := r.Context().Value("framework").(string)
+	err = c.Respond(w, r, http.StatusCreated, RegisterResponse{
+		User:      user,
+		Algorithm: c.passwords.Algorithm(),
+		Framework: framework,
+	},
+		Timing{Name: "hash", Desc: c.passwords.Algorithm(), Duration: hashTime},
+		Timing{Name: "db", Desc: ormType, Duration: dbTime},
+	)
+	if err != nil {
+		c.Logger.Er("failed to write response", err)
+		return
+	}
+
+	c.Logger.Info("User registered - ORM: %s, Hash: %dms, DB: %dms, Total: %dms",
+		ormType, hashTime.Milliseconds(), dbTime.Milliseconds(), time.Since(start).Milliseconds())
+}
+
+// Login checks the password and issues a JWT for the protected routes. An
+// unknown email and a wrong password get the same 401, after the same
+// hashing work, so neither the answer nor its timing says which it was.
+func (c *BaseController) Login(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	creds, ok := c.readCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	ormType := r.URL.Query().Get("orm")
+	if ormType == "" {
+		ormType = "sql"
+	}
+	dbStart := time.Now()
+	user, err := c.Service.GetUserByEmail(r.Context(), ormType, creds.Email)
+	dbTime := time.Since(dbStart)
+	if err != nil {
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to query user")
+		return
+	}
+
+	hashStart := time.Now()
+	if user == nil {
+		err = c.passwords.CheckMissing(creds.Password)
+	} else {
+		err = c.passwords.Check(user.PasswordHash, creds.Password)
+	}
+	hashTime := time.Since(hashStart)
+	if errors.Is(err, auth.ErrMismatch) {
+		c.WriteError(w, r, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+	if err != nil {
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to check password")
+		c.Logger.Er("failed to check password", err)
+		return
+	}
+
+	signStart := time.Now()
+	token, expires, err := c.Tokens.Issue(user.ID, user.Email)
+	signTime := time.Since(signStart)
+	if err != nil {
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to issue token")
+		c.Logger.Er("failed to issue token", err)
+		return
+	}
+
+	framework, _ := r.Context().Value("framework").(string)
+	err = c.Respond(w, r, http.StatusOK, TokenResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: expires,
+		Framework: framework,
+	},
+		Timing{Name: "db", Desc: ormType, Duration: dbTime},
+		Timing{Name: "hash", Duration: hashTime},
+		Timing{Name: "sign", Desc: "HS256", Duration: signTime},
+	)
+	if err != nil {
+		c.Logger.Er("failed to write response", err)
+		return
+	}
+
+	c.Logger.Info("User logged in - ORM: %s, DB: %dms, Hash: %dms, Total: %dms",
+		ormType, dbTime.Milliseconds(), hashTime.Milliseconds(), time.Since(start).Milliseconds())
+}
+
+// readCredentials decodes the JSON credentials body, normalizing the email
+// to lower case, and answers 400 itself when the body is unusable.
+func (c *BaseController) readCredentials(w http.ResponseWriter, r *http.Request) (Credentials, bool) {
+	var creds Credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		c.WriteError(w, r, http.StatusBadRequest, "Invalid JSON body")
+		return creds, false
+	}
+	creds.Email = strings.ToLower(strings.TrimSpace(creds.Email))
+	if creds.Email == "" || creds.Password == "" {
+		c.WriteError(w, r, http.StatusBadRequest, "Email and password are required")
+		return creds, false
+	}
+	return creds, true
+}
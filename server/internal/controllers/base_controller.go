@@ -1,44 +1,180 @@
 package controllers
 
 import (
+	"bananas/internal/auth"
+	"bananas/internal/codec"
+	"bananas/internal/config"
+	"bananas/internal/graphql"
 	"bananas/internal/logger"
+	"bananas/internal/problem"
 	"bananas/internal/services"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type BaseController struct {
 	Service *services.Service
+	Config  config.Config
 	Logger  logger.Logger
+	// Tokens issues JWTs at login, and is what each framework's auth
+	// middleware verifies them with.
+	Tokens    *auth.Tokens
+	graphql   *graphql.Executor
+	passwords *auth.Passwords
 }
 
-func New(service *services.Service) *BaseController {
-	return &BaseController{
-		Service: service,
-		Logger:  logger.New("controller"),
+func New(service *services.Service, cfg config.Config) (*BaseController, error) {
+	passwords, err := auth.NewPasswords(cfg.Auth)
+	if err != nil {
+		return nil, err
 	}
+
+	return &BaseController{
+		Service:   service,
+		Config:    cfg,
+		Logger:    logger.New("controller"),
+		Tokens:    auth.NewTokens(cfg.Auth),
+		graphql:   graphql.New(service),
+		passwords: passwords,
+	}, nil
+}
+
+// Timing is one Server-Timing metric reported alongside a response.
+type Timing struct {
+	Name     string
+	Desc     string
+	Duration time.Duration
 }
 
 // Common response methods
-func (c *BaseController) WriteJSON(w http.ResponseWriter, status int, data interface{}) error {
-	w.Header().Set("Content-Type", "application/json")
+
+// Respond encodes data in the format the Accept header asks for: JSON by
+// default, or MessagePack, CBOR or Protobuf. JSON uses the request's codec
+// (?codec=, falling back to the framework's configured one). The encode time,
+// plus any extra timings, is reported in the Server-Timing header.
+func (c *BaseController) Respond(w http.ResponseWriter, r *http.Request, status int, data interface{}, timings ...Timing) error {
+	jsonEncoder, err := c.jsonEncoder(r)
+	if err != nil {
+		return c.writeProblem(w, r, problem.New(http.StatusBadRequest, err.Error()))
+	}
+
+	mediaType, encoder, ok := codec.Negotiate(r.Header.Get("Accept"), jsonEncoder)
+	if !ok {
+		return c.writeNotAcceptable(w, r)
+	}
+
+	err = c.writeEncoded(w, r, status, mediaType, encoder, data, timings)
+	if errors.Is(err, codec.ErrUnsupported) {
+		return c.writeNotAcceptable(w, r)
+	}
+	return err
+}
+
+// WriteError answers with a problem for status, whatever format Accept asks
+// for.
+func (c *BaseController) WriteError(w http.ResponseWriter, r *http.Request, status int, message string) error {
+	return c.writeProblem(w, r, problem.New(status, message))
+}
+
+// writeProblem sends p as problem+json, encoded with the request's JSON
+// codec, or the default one when ?codec= is what was wrong.
+func (c *BaseController) writeProblem(w http.ResponseWriter, r *http.Request, p problem.Problem) error {
+	encoder, err := c.jsonEncoder(r)
+	if err != nil {
+		encoder = c.defaultEncoder()
+	}
+	return c.writeEncoded(w, r, p.Status, problem.MediaType, encoder, p.For(w, r), nil)
+}
+
+// writeNotAcceptable answers when the client accepts no format the response
+// can be encoded in.
+func (c *BaseController) writeNotAcceptable(w http.ResponseWriter, r *http.Request) error {
+	message := "not acceptable, supported: " + strings.Join([]string{codec.MediaJSON, codec.MediaMsgpack, codec.MediaCBOR, codec.MediaProtobuf}, ", ")
+	return c.writeProblem(w, r, problem.New(http.StatusNotAcceptable, message))
+}
+
+func (c *BaseController) writeEncoded(w http.ResponseWriter, r *http.Request, status int, mediaType string, encoder codec.Encoder, data interface{}, timings []Timing) error {
+	start := time.Now()
+	body, err := encoder.Marshal(data)
+	if errors.Is(err, codec.ErrUnsupported) {
+		return err
+	}
+	if err != nil {
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "Failed to encode response"))
+		return err
+	}
+	timings = append(timings, Timing{Name: "encode", Desc: encoder.Name(), Duration: time.Since(start)})
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("Server-Timing", serverTiming(timings))
 	w.WriteHeader(status)
-	return json.NewEncoder(w).Encode(data)
+	_, err = w.Write(body)
+	return err
+}
+
+// jsonEncoder picks the encoder named by ?codec=, or the one configured for
+// the framework serving the request.
+func (c *BaseController) jsonEncoder(r *http.Request) (codec.Encoder, error) {
+	name := r.URL.Query().Get("codec")
+	if name == "" {
+		framework, _ := r.Context().Value("framework").(string)
+		if fw, ok := c.Config.Framework(framework); ok {
+			name = fw.JSONCodec
+		}
+	}
+	if name == "" {
+		return c.defaultEncoder(), nil
+	}
+
+	encoder, ok := codec.JSON(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %q, available: %s", name, strings.Join(codec.JSONNames(), ", "))
+	}
+	return encoder, nil
+}
+
+func (c *BaseController) defaultEncoder() codec.Encoder {
+	encoder, _ := codec.JSON(codec.DefaultJSON)
+	return encoder
 }
 
-func (c *BaseController) WriteError(w http.ResponseWriter, status int, message string) error {
-	return c.WriteJSON(w, status, map[string]string{"error": message})
+// frameworkConfig returns the config of the framework serving r, or the zero
+// value, with no timeouts, when the framework is unknown.
+func (c *BaseController) frameworkConfig(r *http.Request) config.FrameworkConfig {
+	framework, _ := r.Context().Value("framework").(string)
+	fw, _ := c.Config.Framework(framework)
+	return fw
+}
+
+// serverTiming formats timings as a Server-Timing header value, with
+// durations in fractional milliseconds.
+func serverTiming(timings []Timing) string {
+	parts := make([]string, 0, len(timings))
+	for _, t := range timings {
+		part := t.Name
+		if t.Desc != "" {
+			part += ";desc=" + strconv.Quote(t.Desc)
+		}
+		part += ";dur=" + strconv.FormatFloat(float64(t.Duration.Microseconds())/1000, 'f', 3, 64)
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
 }
 
 // Test endpoints
 func (c *BaseController) SimpleRequest(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	
-	err := c.WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "Simple request successful",
-		"framework": r.Context().Value("framework").(string),
+	err := c.Respond(w, r, http.StatusOK, SimpleResponse{
+		Message:   "Simple request successful",
+		Framework: r.Context().Value("framework").(string),
 	})
 	
 	if err != nil {
@@ -68,15 +204,16 @@ func (c *BaseController) DatabaseQuery(w http.ResponseWriter, r *http.Request) {
 
 	results, err := c.Service.GetTestResults(r.Context(), ormType, limit)
 	if err != nil {
-		c.WriteError(w, http.StatusInternalServerError, "Failed to query database")
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to query database")
 		return
 	}
 
-	err = c.WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"results":   results,
-		"count":     len(results),
-		"orm":       ormType,
-		"framework": r.Context().Value("framework"),
+	framework, _ := r.Context().Value("framework").(string)
+	err = c.Respond(w, r, http.StatusOK, DatabaseQueryResponse{
+		Results:   results,
+		Count:     len(results),
+		ORM:       ormType,
+		Framework: framework,
 	})
 
 	if err != nil {
@@ -92,18 +229,18 @@ func (c *BaseController) DatabaseQuery(w http.ResponseWriter, r *http.Request) {
 func (c *BaseController) JsonResponse(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	
-	data := map[string]interface{}{
-		"message": "JSON response successful",
-		"framework": r.Context().Value("framework").(string),
-		"timestamp": time.Now().Unix(),
-		"data": []map[string]interface{}{
-			{"id": 1, "name": "Item 1", "value": 100.5},
-			{"id": 2, "name": "Item 2", "value": 200.3},
-			{"id": 3, "name": "Item 3", "value": 150.7},
+	data := JsonResponseBody{
+		Message:   "JSON response successful",
+		Framework: r.Context().Value("framework").(string),
+		Timestamp: time.Now().Unix(),
+		Data: []JsonItem{
+			{ID: 1, Name: "Item 1", Value: 100.5},
+			{ID: 2, Name: "Item 2", Value: 200.3},
+			{ID: 3, Name: "Item 3", Value: 150.7},
 		},
 	}
 	
-	err := c.WriteJSON(w, http.StatusOK, data)
+	err := c.Respond(w, r, http.StatusOK, data)
 	if err != nil {
 		c.Logger.Er("failed to write response", err)
 		return
@@ -115,8 +252,8 @@ func (c *BaseController) JsonResponse(w http.ResponseWriter, r *http.Request) {
 
 func (c *BaseController) FrameworkInfo(w http.ResponseWriter, r *http.Request) {
 	framework := r.Context().Value("framework").(string)
-	
-	err := c.WriteJSON(w, http.StatusOK, map[string]interface{}{
+
+	info := map[string]interface{}{
 		"framework": framework,
 		"type": "backend",
 		"endpoints": []string{
@@ -125,7 +262,39 @@ func (c *BaseController) FrameworkInfo(w http.ResponseWriter, r *http.Request) {
 			"/api/test/json",
 			"/api/info",
 		},
-	})
+	}
+
+	if fw, ok := c.Config.Framework(framework); ok {
+		info["name"] = fw.DisplayName
+		info["address"] = fw.Addr()
+		info["readTimeout"] = fw.ReadTimeout.String()
+		info["writeTimeout"] = fw.WriteTimeout.String()
+		info["idleTimeout"] = fw.IdleTimeout.String()
+		info["maxHeaderBytes"] = fw.MaxHeaderBytes
+		info["middleware"] = fw.Middleware.Stack
+		info["protocol"] = fw.Protocol
+		info["socket"] = fw.Socket
+		info["listeners"] = fw.Listeners
+		info["jsonCodec"] = fw.JSONCodec
+	}
+
+	enabled := make([]string, 0, len(c.Config.Frameworks))
+	for _, fw := range c.Config.EnabledFrameworks() {
+		enabled = append(enabled, fw.Name)
+	}
+	info["frameworks"] = enabled
+	info["isolation"] = c.Config.IsolationMode()
+	info["pid"] = os.Getpid()
+	info["gomaxprocs"] = runtime.GOMAXPROCS(0)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	info["heapInuse"] = mem.HeapInuse
+	if rss, ok := residentBytes(); ok {
+		info["rss"] = rss
+	}
+
+	err := c.Respond(w, r, http.StatusOK, info)
 	
 	if err != nil {
 		c.Logger.Er("failed to write response", err)
@@ -133,6 +302,25 @@ func (c *BaseController) FrameworkInfo(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// residentBytes returns the process's resident set size from /proc, which
+// only Linux has. Unlike heapInuse it counts everything the process holds,
+// including freed heap not yet returned to the OS.
+func residentBytes() (uint64, bool) {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, false
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return pages * uint64(os.Getpagesize()), true
+}
+
 func (c *BaseController) GetRecentOrders(w http.ResponseWriter, r *http.Request) {
 	totalStart := time.Now()
 
@@ -149,25 +337,30 @@ func (c *BaseController) GetRecentOrders(w http.ResponseWriter, r *http.Request)
 		ormType = "sql"
 	}
 
-	orders, dbTimeMs, err := c.Service.GetRecentOrders(r.Context(), ormType, limit)
+	orders, dbTime, err := c.Service.GetRecentOrders(r.Context(), ormType, limit)
 	if err != nil {
-		c.WriteError(w, http.StatusInternalServerError, "Failed to query orders")
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to query orders")
 		c.Logger.Er("failed to get recent orders", err)
 		return
 	}
 
-	totalTimeMs := time.Since(totalStart).Milliseconds()
-	frameworkTimeMs := totalTimeMs - dbTimeMs
+	totalTime := time.Since(totalStart)
+	dbTimeMs := dbTime.Milliseconds()
+	totalTimeMs := totalTime.Milliseconds()
+	frameworkTimeMs := (totalTime - dbTime).Milliseconds()
 
-	err = c.WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"orders":        orders,
-		"count":         len(orders),
-		"orm":           ormType,
-		"framework":     r.Context().Value("framework"),
-		"dbTime":        dbTimeMs,
-		"totalTime":     totalTimeMs,
-		"frameworkTime": frameworkTimeMs,
-	})
+	// Encoding happens after totalTime is taken, so its cost is reported in
+	// the Server-Timing header alongside the DB time rather than in the body.
+	framework, _ := r.Context().Value("framework").(string)
+	err = c.Respond(w, r, http.StatusOK, RecentOrdersResponse{
+		Orders:        orders,
+		Count:         len(orders),
+		ORM:           ormType,
+		Framework:     framework,
+		DBTime:        dbTimeMs,
+		TotalTime:     totalTimeMs,
+		FrameworkTime: frameworkTimeMs,
+	}, Timing{Name: "db", Desc: ormType, Duration: dbTime})
 
 	if err != nil {
 		c.Logger.Er("failed to write response", err)
@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"bananas/internal/payload"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Upload modes, chosen with ?mode=. Both end with the upload on disk; they
+// differ in whether the body is buffered whole on the way.
+const (
+	UploadStream = "stream" // copy the body to disk as it arrives
+	UploadMemory = "memory" // read the whole body into memory, then write it
+)
+
+// defaultDownloadSize is served when a download names no ?size=.
+const defaultDownloadSize = 1 << 20
+
+// UploadResponse reports what an upload endpoint received. Throughput is in
+// MB/s, with MB meaning 1<<20 bytes as in payload sizes.
+type UploadResponse struct {
+	Mode       string  `json:"mode"`
+	Files      int     `json:"files"`
+	Bytes      int64   `json:"bytes"`
+	Framework  string  `json:"framework"`
+	TotalTime  int64   `json:"totalTime"`
+	Throughput float64 `json:"throughput"`
+}
+
+// UploadMultipart accepts a multipart/form-data body and stores every file
+// part in it, reading the parts straight off the request body rather than
+// through ParseMultipartForm so the mode decides what is buffered.
+func (c *BaseController) UploadMultipart(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	mode, ok := c.uploadMode(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = c.uploadBody(w, r)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		c.WriteError(w, r, http.StatusBadRequest, "Expected a multipart/form-data body")
+		return
+	}
+
+	files := 0
+	var total int64
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			c.writeUploadError(w, r, err)
+			return
+		}
+		if part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		// A part's length is unknown, so the request's is the best hint.
+		n, err := c.saveUpload(part, mode, r.ContentLength)
+		part.Close()
+		if err != nil {
+			c.writeUploadError(w, r, err)
+			return
+		}
+		files++
+		total += n
+	}
+
+	c.respondUpload(w, r, mode, files, total, start)
+}
+
+// UploadRaw stores the request body itself as one file.
+func (c *BaseController) UploadRaw(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	mode, ok := c.uploadMode(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := c.saveUpload(c.uploadBody(w, r), mode, r.ContentLength)
+	if err != nil {
+		c.writeUploadError(w, r, err)
+		return
+	}
+
+	c.respondUpload(w, r, mode, 1, n, start)
+}
+
+func (c *BaseController) uploadMode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	mode := r.URL.Query().Get("mode")
+	switch mode {
+	case "":
+		return UploadStream, true
+	case UploadStream, UploadMemory:
+		return mode, true
+	}
+	c.WriteError(w, r, http.StatusBadRequest, "Unknown upload mode, use stream or memory")
+	return "", false
+}
+
+// uploadBody caps the body at MaxUploadBytes and, as uploads outlast the
+// server's timeouts, extends the deadlines on every read instead.
+func (c *BaseController) uploadBody(w http.ResponseWriter, r *http.Request) io.ReadCloser {
+	body := http.MaxBytesReader(w, r.Body, c.Config.MaxUploadBytes)
+	fw := c.frameworkConfig(r)
+	if fw.ReadTimeout > 0 || fw.WriteTimeout > 0 {
+		return &deadlineReader{
+			ReadCloser:   body,
+			rc:           http.NewResponseController(w),
+			readTimeout:  fw.ReadTimeout,
+			writeTimeout: fw.WriteTimeout,
+		}
+	}
+	return body
+}
+
+// saveUpload writes src to a new file in UploadDir and removes it again:
+// the endpoints measure receiving and storing uploads, not keeping them.
+// sizeHint, when known, sizes the memory mode buffer up front.
+func (c *BaseController) saveUpload(src io.Reader, mode string, sizeHint int64) (int64, error) {
+	file, err := os.CreateTemp(c.Config.UploadDir, "upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if mode == UploadStream {
+		return io.Copy(file, src)
+	}
+
+	var buf bytes.Buffer
+	if sizeHint > 0 && sizeHint <= c.Config.MaxUploadBytes {
+		buf.Grow(int(sizeHint))
+	}
+	if _, err := buf.ReadFrom(src); err != nil {
+		return 0, err
+	}
+	n, err := file.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// writeUploadError answers 413 for a body over MaxUploadBytes, 500 when the
+// upload could not be stored and 400 for a body that failed to arrive.
+func (c *BaseController) writeUploadError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.WriteError(w, r, http.StatusRequestEntityTooLarge, "Upload exceeds "+payload.FormatSize(tooLarge.Limit))
+	case errors.As(err, &pathErr):
+		c.Logger.Er("failed to store upload", err)
+		c.WriteError(w, r, http.StatusInternalServerError, "Failed to store upload")
+	default:
+		c.WriteError(w, r, http.StatusBadRequest, "Failed to read upload")
+	}
+}
+
+func (c *BaseController) respondUpload(w http.ResponseWriter, r *http.Request, mode string, files int, total int64, start time.Time) {
+	elapsed := time.Since(start)
+	framework, _ := r.Context().Value("framework").(string)
+	err := c.Respond(w, r, http.StatusOK, UploadResponse{
+		Mode:       mode,
+		Files:      files,
+		Bytes:      total,
+		Framework:  framework,
+		TotalTime:  elapsed.Milliseconds(),
+		Throughput: float64(total) / (1 << 20) / elapsed.Seconds(),
+	})
+	if err != nil {
+		c.Logger.Er("failed to write response", err)
+		return
+	}
+
+	c.Logger.Info("Upload completed - Mode: %s, Files: %d, Bytes: %d, Total: %dms", mode, files, total, elapsed.Milliseconds())
+}
+
+// FileDownload is a parsed /api/files/download request: a generated payload
+// of ?size= bytes, and what to send of it once If-None-Match, Range and
+// If-Range are applied. net/http frameworks serve it with DownloadFile;
+// Fiber sends Body through its own body stream.
+type FileDownload struct {
+	size   int64
+	etag   string
+	status int
+	offset int64
+	length int64
+}
+
+// NewFileDownload reads the size parameter and the conditional and range
+// headers. Only single byte ranges are honoured; a multi-range request gets
+// the whole payload, which RFC 9110 allows. When the size is invalid it
+// answers with the error itself and returns false.
+func (c *BaseController) NewFileDownload(w http.ResponseWriter, r *http.Request) (*FileDownload, bool) {
+	size := int64(defaultDownloadSize)
+	if raw := r.URL.Query().Get("size"); raw != "" {
+		parsed, err := payload.ParseSize(raw)
+		if err != nil {
+			c.WriteError(w, r, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		size = parsed
+	}
+
+	d := &FileDownload{size: size, etag: payload.ETag(size), status: http.StatusOK, length: size}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, d.etag) {
+		d.status, d.length = http.StatusNotModified, 0
+		return d, true
+	}
+	if ranges := r.Header.Get("Range"); ranges != "" {
+		// If-Range holds the ETag the client's partial copy came from; when
+		// it is stale the client needs the whole payload again.
+		if ifRange := r.Header.Get("If-Range"); ifRange == "" || ifRange == d.etag {
+			d.applyRange(ranges)
+		}
+	}
+	return d, true
+}
+
+// applyRange narrows the download to a single "bytes=" range, or marks it
+// unsatisfiable. Anything it does not understand leaves the whole payload.
+func (d *FileDownload) applyRange(header string) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return
+	}
+
+	var start, end int64
+	if first == "" {
+		// A suffix range: the final n bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return
+		}
+		if n == 0 {
+			d.status, d.length = http.StatusRequestedRangeNotSatisfiable, 0
+			return
+		}
+		start, end = max(d.size-n, 0), d.size-1
+	} else {
+		var err error
+		if start, err = strconv.ParseInt(first, 10, 64); err != nil || start < 0 {
+			return
+		}
+		end = d.size - 1
+		if last != "" {
+			if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+				return
+			}
+			end = min(end, d.size-1)
+		}
+		if start >= d.size {
+			d.status, d.length = http.StatusRequestedRangeNotSatisfiable, 0
+			return
+		}
+	}
+
+	d.status, d.offset, d.length = http.StatusPartialContent, start, end-start+1
+}
+
+// etagMatches applies If-None-Match's weak comparison of a list of tags, or
+// "*", against etag.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the response status: 200, 206, 304 or 416.
+func (d *FileDownload) Status() int { return d.status }
+
+// Length returns how many body bytes will be sent.
+func (d *FileDownload) Length() int64 { return d.length }
+
+// SetHeaders sets the response headers for Status.
+func (d *FileDownload) SetHeaders(h http.Header) {
+	h.Set("ETag", d.etag)
+	h.Set("Accept-Ranges", "bytes")
+	switch d.status {
+	case http.StatusNotModified:
+		return
+	case http.StatusRequestedRangeNotSatisfiable:
+		h.Set("Content-Range", "bytes */"+strconv.FormatInt(d.size, 10))
+		return
+	case http.StatusPartialContent:
+		h.Set("Content-Range", "bytes "+strconv.FormatInt(d.offset, 10)+"-"+
+			strconv.FormatInt(d.offset+d.length-1, 10)+"/"+strconv.FormatInt(d.size, 10))
+	}
+	h.Set("Content-Type", "application/octet-stream")
+	h.Set("Content-Length", strconv.FormatInt(d.length, 10))
+}
+
+// Body returns the bytes to send, or nil when the status carries none.
+func (d *FileDownload) Body() io.Reader {
+	if d.length == 0 {
+		return nil
+	}
+	return payload.NewRangeReader(d.offset, d.length)
+}
+
+// DownloadFile serves a generated payload with support for byte ranges and
+// If-None-Match revalidation. The payload is generated as it is written, so
+// memory stays flat whatever the size.
+func (c *BaseController) DownloadFile(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	download, ok := c.NewFileDownload(w, r)
+	if !ok {
+		return
+	}
+
+	download.SetHeaders(w.Header())
+	w.WriteHeader(download.Status())
+	body := download.Body()
+	if body == nil || r.Method == http.MethodHead {
+		return
+	}
+
+	var dst io.Writer = w
+	if timeout := c.frameworkConfig(r).WriteTimeout; timeout > 0 {
+		dst = &deadlineWriter{w: w, rc: http.NewResponseController(w), timeout: timeout}
+	}
+	if n, err := io.Copy(dst, body); err != nil {
+		c.Logger.Info("Client went away after %d of %d bytes", n, download.Length())
+		return
+	}
+
+	c.Logger.Info("Download completed - Status: %d, Bytes: %d, Total: %dms", download.Status(), download.Length(), time.Since(start).Milliseconds())
+}
+
+// deadlineReader pushes the connection's read deadline back before every
+// read, so ReadTimeout catches a stalled upload rather than a large one.
+// net/http starts the WriteTimeout clock when the request arrives, so the
+// write deadline is pushed back too, leaving the response its full timeout.
+type deadlineReader struct {
+	io.ReadCloser
+	rc           *http.ResponseController
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	now := time.Now()
+	if d.readTimeout > 0 {
+		d.rc.SetReadDeadline(now.Add(d.readTimeout))
+	}
+	if d.writeTimeout > 0 {
+		d.rc.SetWriteDeadline(now.Add(d.writeTimeout))
+	}
+	return d.ReadCloser.Read(p)
+}
+
+// deadlineWriter does the same for writes and WriteTimeout.
+type deadlineWriter struct {
+	w       io.Writer
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func (d *deadlineWriter) Write(p []byte) (int, error) {
+	d.rc.SetWriteDeadline(time.Now().Add(d.timeout))
+	return d.w.Write(p)
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFileDownloadApplyRange(t *testing.T) {
+	const size = 1000
+
+	tests := []struct {
+		header string
+		status int
+		offset int64
+		length int64
+	}{
+		{header: "bytes=0-99", status: http.StatusPartialContent, offset: 0, length: 100},
+		{header: "bytes=100-199", status: http.StatusPartialContent, offset: 100, length: 100},
+		{header: "bytes=500-", status: http.StatusPartialContent, offset: 500, length: 500},
+		{header: "bytes=999-999", status: http.StatusPartialContent, offset: 999, length: 1},
+		{header: "bytes=900-5000", status: http.StatusPartialContent, offset: 900, length: 100},
+		{header: "bytes=-100", status: http.StatusPartialContent, offset: 900, length: 100},
+		{header: "bytes=-5000", status: http.StatusPartialContent, offset: 0, length: size},
+		{header: "bytes= 10-19 ", status: http.StatusPartialContent, offset: 10, length: 10},
+		{header: "bytes=-0", status: http.StatusRequestedRangeNotSatisfiable},
+		{header: "bytes=1000-", status: http.StatusRequestedRangeNotSatisfiable},
+		{header: "bytes=1000-1999", status: http.StatusRequestedRangeNotSatisfiable},
+		// Whole payload for anything else
+		{header: "bytes=0-9,20-29", status: http.StatusOK, length: size},
+		{header: "items=0-9", status: http.StatusOK, length: size},
+		{header: "bytes=9-0", status: http.StatusOK, length: size},
+		{header: "bytes=a-9", status: http.StatusOK, length: size},
+		{header: "bytes=0-b", status: http.StatusOK, length: size},
+		{header: "bytes=-1-", status: http.StatusOK, length: size},
+		{header: "bytes=10", status: http.StatusOK, length: size},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			d := &FileDownload{size: size, status: http.StatusOK, length: size}
+			d.applyRange(tt.header)
+			if d.status != tt.status || d.offset != tt.offset || d.length != tt.length {
+				t.Errorf("status %d, offset %d, length %d; want %d, %d, %d",
+					d.status, d.offset, d.length, tt.status, tt.offset, tt.length)
+			}
+		})
+	}
+}
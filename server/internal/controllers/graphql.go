@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"bananas/internal/codec"
+	"bananas/internal/graphql"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// GraphQL serves /graphql, taking the query as a JSON POST body or as GET
+// parameters. ?orm= picks the ORM every read goes through, as it does on the
+// REST endpoints, and ?codec= the JSON encoder. ?batch=off resolves nested
+// fields with a query each, for measuring what the dataloaders save. Query
+// errors are part of a GraphQL response and come back with 200; only a
+// request that cannot be read is rejected with 400.
+func (c *BaseController) GraphQL(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	encoder, err := c.jsonEncoder(r)
+	if err != nil {
+		c.writeGraphQLError(w, r, http.StatusBadRequest, c.defaultEncoder(), err)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		c.writeGraphQLError(w, r, http.StatusMethodNotAllowed, encoder, errors.New("method not allowed, use GET or POST"))
+		return
+	}
+
+	req, err := graphqlRequest(r)
+	if err != nil {
+		c.writeGraphQLError(w, r, http.StatusBadRequest, encoder, err)
+		return
+	}
+
+	ormType := r.URL.Query().Get("orm")
+	if ormType == "" {
+		ormType = "sql"
+	}
+
+	batched := r.URL.Query().Get("batch") != "off"
+	response, stats := c.graphql.Execute(r.Context(), ormType, batched, req)
+	totalTime := time.Since(start)
+
+	err = c.writeEncoded(w, r, http.StatusOK, codec.MediaJSON, encoder, response, []Timing{
+		{Name: "db", Desc: ormType, Duration: time.Duration(stats.DBTime * float64(time.Millisecond))},
+		{Name: "graphql", Duration: totalTime},
+	})
+	if err != nil {
+		c.Logger.Er("failed to write response", err)
+		return
+	}
+
+	c.Logger.Info("GraphQL query completed - ORM: %s, Queries: %d, DB: %.1fms, Total: %dms",
+		ormType, stats.Queries, stats.DBTime, totalTime.Milliseconds())
+}
+
+// graphqlRequest reads the query, operation name and variables from a JSON
+// body, or from the query string of a GET.
+func graphqlRequest(r *http.Request) (graphql.Request, error) {
+	var req graphql.Request
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return req, errors.New("variables must be a JSON object")
+			}
+		}
+	} else {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "application/json" {
+			return req, errors.New("expected an application/json body")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, errors.New("invalid JSON body")
+		}
+	}
+
+	if req.Query == "" {
+		return req, errors.New("missing query")
+	}
+	return req, nil
+}
+
+// writeGraphQLError answers with a GraphQL response holding only err, so
+// clients find it where they look for every other error.
+func (c *BaseController) writeGraphQLError(w http.ResponseWriter, r *http.Request, status int, encoder codec.Encoder, err error) {
+	response := &graphqlgo.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}}
+	c.writeEncoded(w, r, status, codec.MediaJSON, encoder, response, nil)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/models"
+	"bananas/internal/protoconv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Typed response bodies for the endpoints that can be negotiated to
+// Protobuf. Their json tags are what JSON, MessagePack and CBOR emit; Proto
+// maps them onto the messages in proto/bananas/v1/api.proto.
+
+type SimpleResponse struct {
+	Message   string `json:"message"`
+	Framework string `json:"framework"`
+}
+
+func (r SimpleResponse) Proto() proto.Message {
+	return &bananasv1.SimpleResponse{Message: r.Message, Framework: r.Framework}
+}
+
+type JsonItem struct {
+	ID    int64   `json:"id"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+type JsonResponseBody struct {
+	Message   string     `json:"message"`
+	Framework string     `json:"framework"`
+	Timestamp int64      `json:"timestamp"`
+	Data      []JsonItem `json:"data"`
+}
+
+func (r JsonResponseBody) Proto() proto.Message {
+	items := make([]*bananasv1.JsonResponse_Item, len(r.Data))
+	for i, item := range r.Data {
+		items[i] = &bananasv1.JsonResponse_Item{Id: item.ID, Name: item.Name, Value: item.Value}
+	}
+	return &bananasv1.JsonResponse{
+		Message:   r.Message,
+		Framework: r.Framework,
+		Timestamp: r.Timestamp,
+		Data:      items,
+	}
+}
+
+type DatabaseQueryResponse struct {
+	Results   []*models.TestResult `json:"results"`
+	Count     int                  `json:"count"`
+	ORM       string               `json:"orm"`
+	Framework string               `json:"framework"`
+}
+
+func (r DatabaseQueryResponse) Proto() proto.Message {
+	return &bananasv1.DatabaseQueryResponse{
+		Results:   protoconv.TestResults(r.Results),
+		Count:     int32(r.Count),
+		Orm:       r.ORM,
+		Framework: r.Framework,
+	}
+}
+
+type RecentOrdersResponse struct {
+	Orders        []*models.OrderWithDetails `json:"orders"`
+	Count         int                        `json:"count"`
+	ORM           string                     `json:"orm"`
+	Framework     string                     `json:"framework"`
+	DBTime        int64                      `json:"dbTime"`
+	TotalTime     int64                      `json:"totalTime"`
+	FrameworkTime int64                      `json:"frameworkTime"`
+}
+
+func (r RecentOrdersResponse) Proto() proto.Message {
+	return &bananasv1.RecentOrdersResponse{
+		Orders:        protoconv.OrdersWithDetails(r.Orders),
+		Count:         int32(r.Count),
+		Orm:           r.ORM,
+		Framework:     r.Framework,
+		DbTime:        r.DBTime,
+		TotalTime:     r.TotalTime,
+		FrameworkTime: r.FrameworkTime,
+	}
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"bananas/internal/codec"
+	"bananas/internal/models"
+	"bananas/internal/problem"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// streamFlushEvery is how many orders are written between flushes, trading a
+// little latency for far fewer writes on large exports.
+const streamFlushEvery = 100
+
+// StreamSummary is the final Server-Sent Event of a completed stream.
+type StreamSummary struct {
+	Count     int    `json:"count"`
+	ORM       string `json:"orm"`
+	Framework string `json:"framework"`
+	TotalTime int64  `json:"totalTime"`
+}
+
+// OrderStream is a parsed /api/orders/stream request. net/http frameworks
+// serve it with StreamOrders; Fiber, whose handlers cannot stream through an
+// http.ResponseWriter, writes it from its own body stream writer.
+type OrderStream struct {
+	controller  *BaseController
+	encoder     codec.Encoder
+	contentType string
+	orm         string
+	status      string
+	limit       int
+	framework   string
+}
+
+// NewOrderStream reads the orm, status and limit parameters, where a missing
+// or zero limit streams every order. When the request is invalid it answers
+// with the error itself and returns false.
+func (c *BaseController) NewOrderStream(w http.ResponseWriter, r *http.Request) (*OrderStream, bool) {
+	encoder, err := c.jsonEncoder(r)
+	if err != nil {
+		c.WriteError(w, r, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	limit := 0
+	if parsedLimit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsedLimit > 0 {
+		limit = parsedLimit
+	}
+
+	ormType := r.URL.Query().Get("orm")
+	if ormType == "" {
+		ormType = "sql"
+	}
+
+	framework, _ := r.Context().Value("framework").(string)
+	return &OrderStream{
+		controller:  c,
+		encoder:     encoder,
+		contentType: codec.NegotiateStream(r.Header.Get("Accept")),
+		orm:         ormType,
+		status:      r.URL.Query().Get("status"),
+		limit:       limit,
+		framework:   framework,
+	}, true
+}
+
+// SetHeaders sets the response headers, which must go out before the first
+// order is read.
+func (s *OrderStream) SetHeaders(h http.Header) {
+	h.Set("Content-Type", s.contentType)
+	h.Set("Cache-Control", "no-cache")
+	// Tell nginx style proxies not to buffer the stream.
+	h.Set("X-Accel-Buffering", "no")
+}
+
+// Write streams orders to w as they come off the database cursor, calling
+// flush every streamFlushEvery orders and at the end. It stops early when ctx
+// is done or a write or flush fails, which is how a client disconnect shows
+// up. A database error ends the stream with an error event or line.
+func (s *OrderStream) Write(ctx context.Context, w io.Writer, flush func() error) {
+	log := s.controller.Logger.Function("StreamOrders")
+	start := time.Now()
+	sse := s.contentType == codec.MediaEventStream
+
+	var buf bytes.Buffer
+	var writeErr error
+	count := 0
+	err := s.controller.Service.StreamOrders(ctx, s.orm, s.status, s.limit, func(order *models.SalesOrder) error {
+		data, err := s.encoder.Marshal(order)
+		if err != nil {
+			return err
+		}
+		count++
+
+		buf.Reset()
+		if sse {
+			buf.WriteString("id: ")
+			buf.WriteString(strconv.Itoa(count))
+			buf.WriteString("\nevent: order\ndata: ")
+			buf.Write(data)
+			buf.WriteString("\n\n")
+		} else {
+			buf.Write(data)
+			buf.WriteByte('\n')
+		}
+		if _, writeErr = w.Write(buf.Bytes()); writeErr != nil {
+			return writeErr
+		}
+		if count%streamFlushEvery == 0 {
+			writeErr = flush()
+		}
+		return writeErr
+	})
+
+	switch {
+	case writeErr != nil || ctx.Err() != nil:
+		log.Info("Client went away after %d orders - ORM: %s", count, s.orm)
+		return
+	case err != nil:
+		log.Er("failed to stream orders", err)
+		message, _ := s.encoder.Marshal(problem.New(http.StatusInternalServerError, "Failed to stream orders"))
+		s.writeFinal(w, "error", message)
+	case sse:
+		summary, _ := s.encoder.Marshal(StreamSummary{
+			Count:     count,
+			ORM:       s.orm,
+			Framework: s.framework,
+			TotalTime: time.Since(start).Milliseconds(),
+		})
+		s.writeFinal(w, "end", summary)
+	}
+	flush()
+
+	log.Info("Orders stream completed - ORM: %s, Orders: %d, Total: %dms", s.orm, count, time.Since(start).Milliseconds())
+}
+
+// writeFinal writes the closing event. NDJSON has no events, so only errors
+// are written, as a last line.
+func (s *OrderStream) writeFinal(w io.Writer, event string, data []byte) {
+	if s.contentType == codec.MediaEventStream {
+		io.WriteString(w, "event: "+event+"\ndata: "+string(data)+"\n\n")
+		return
+	}
+	if event == "error" {
+		io.WriteString(w, string(data)+"\n")
+	}
+}
+
+// StreamOrders streams orders straight from the database cursor as
+// Server-Sent Events, or as NDJSON when Accept prefers it, so memory stays
+// flat however many orders are exported.
+func (c *BaseController) StreamOrders(w http.ResponseWriter, r *http.Request) {
+	stream, ok := c.NewOrderStream(w, r)
+	if !ok {
+		return
+	}
+
+	// Each flush buys the stream another write timeout, so the server's
+	// WriteTimeout catches stalled clients rather than long exports.
+	rc := http.NewResponseController(w)
+	writeTimeout := c.frameworkConfig(r).WriteTimeout
+	flush := func() error {
+		if writeTimeout > 0 {
+			rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		}
+		return rc.Flush()
+	}
+
+	stream.SetHeaders(w.Header())
+	w.WriteHeader(http.StatusOK)
+	if err := flush(); err != nil {
+		return
+	}
+	stream.Write(r.Context(), w, flush)
+}
@@ -5,32 +5,53 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"bananas/internal/config"
+	"bananas/internal/httpclient"
 	"bananas/internal/logger"
+	"bananas/internal/problem"
 	"bananas/internal/services"
 	"bananas/internal/templates"
 )
 
 type TemplController struct {
 	Service *services.Service
+	Config  config.Config
 	Logger  logger.Logger
 }
 
-func NewTemplController(service *services.Service, log logger.Logger) *TemplController {
+func NewTemplController(service *services.Service, cfg config.Config, log logger.Logger) *TemplController {
 	return &TemplController{
 		Service: service,
+		Config:  cfg,
 		Logger:  log,
 	}
 }
 
 // HomePage renders the main testing interface
 func (c *TemplController) HomePage(w http.ResponseWriter, r *http.Request) {
-	component := templates.Home()
+	enabled := c.Config.EnabledFrameworks()
+	frameworks := make([]templates.Framework, 0, len(enabled))
+	for _, fw := range enabled {
+		// RPC servers don't serve the HTTP endpoints the page exercises.
+		if fw.IsRPC() {
+			continue
+		}
+		port, _ := strconv.Atoi(fw.Port)
+		frameworks = append(frameworks, templates.Framework{
+			Name:  fw.DisplayName,
+			Value: fw.Name,
+			Port:  port,
+		})
+	}
+
+	component := templates.Home(frameworks)
 	err := component.Render(r.Context(), w)
 	if err != nil {
 		c.Logger.Er("failed to render home template", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "Failed to render page"))
 	}
 }
 
@@ -43,17 +64,23 @@ func (c *TemplController) RunTest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get parameters from query string
-	frameworkPort := r.URL.Query().Get("framework")
+	targetFramework := r.URL.Query().Get("framework")
 	orm := r.URL.Query().Get("orm")
 	endpoint := r.URL.Query().Get("endpoint")
 
-	if frameworkPort == "" || endpoint == "" {
+	if targetFramework == "" || endpoint == "" {
 		c.renderError(w, r, "Missing required parameters", frameworkName, orm)
 		return
 	}
 
+	target, ok := c.Config.Framework(targetFramework)
+	if !ok || !target.Enabled || target.IsRPC() {
+		c.renderError(w, r, fmt.Sprintf("Framework %s is not enabled", targetFramework), frameworkName, orm)
+		return
+	}
+
 	// Build the target URL
-	targetURL := fmt.Sprintf("http://localhost:%s%s", frameworkPort, endpoint)
+	targetURL := target.BaseURL() + endpoint
 
 	// Add ORM parameter if endpoint is database test
 	if endpoint == "/api/test/database?limit=10" && orm != "" {
@@ -62,9 +89,16 @@ func (c *TemplController) RunTest(w http.ResponseWriter, r *http.Request) {
 
 	c.Logger.Info("running test", "framework", frameworkName, "target", targetURL, "orm", orm)
 
+	client, err := httpclient.ForFramework(target, 30*time.Second)
+	if err != nil {
+		c.Logger.Er("failed to create client", err)
+		c.renderError(w, r, err.Error(), frameworkName, orm)
+		return
+	}
+
 	// Make the request and measure duration
 	startTime := time.Now()
-	resp, err := http.Get(targetURL)
+	resp, err := client.Get(targetURL)
 	duration := time.Since(startTime).Milliseconds()
 
 	if err != nil {
@@ -89,12 +123,9 @@ func (c *TemplController) RunTest(w http.ResponseWriter, r *http.Request) {
 		responseData = string(body)
 	}
 
-	// Get framework name from port
-	frameworkDisplayName := getFrameworkName(frameworkPort)
-
 	// Render results template
 	result := templates.TestResult{
-		Framework: frameworkDisplayName,
+		Framework: target.DisplayName,
 		ORM:       getORMName(orm),
 		Response:  responseData,
 		Duration:  float64(duration),
@@ -105,7 +136,7 @@ func (c *TemplController) RunTest(w http.ResponseWriter, r *http.Request) {
 	err = component.Render(r.Context(), w)
 	if err != nil {
 		c.Logger.Er("failed to render results template", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "Failed to render page"))
 	}
 }
 
@@ -122,23 +153,8 @@ func (c *TemplController) renderError(w http.ResponseWriter, r *http.Request, er
 	err := component.Render(r.Context(), w)
 	if err != nil {
 		c.Logger.Er("failed to render error template", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-	}
-}
-
-func getFrameworkName(port string) string {
-	frameworks := map[string]string{
-		"8081": "Standard Library",
-		"8082": "Gin",
-		"8083": "Fiber",
-		"8084": "Echo",
-		"8085": "Chi",
-		"8086": "Gorilla Mux",
-	}
-	if name, ok := frameworks[port]; ok {
-		return name
+		problem.Write(w, r, problem.New(http.StatusInternalServerError, "Failed to render page"))
 	}
-	return "Unknown"
 }
 
 func getORMName(value string) string {
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"bananas/internal/validation"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ValidateOrder is the adapted mode of /api/test/validate: the order is
+// decoded with encoding/json and checked by the shared validator. The summary
+// goes out through Respond like any other response, and a rejected order as
+// a problem. Frameworks with a binder of their own serve native mode
+// themselves and only hand ?mode=adapted here.
+func (c *BaseController) ValidateOrder(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	mode := r.URL.Query().Get("mode")
+	if mode != "" && mode != validation.ModeNative && mode != validation.ModeAdapted {
+		c.WriteError(w, r, http.StatusBadRequest, "Unknown validation mode, use native or adapted")
+		return
+	}
+
+	var req validation.OrderRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err == nil {
+		err = validation.Validate(&req)
+	}
+	if err != nil {
+		if err := c.writeProblem(w, r, validation.Problem(err)); err != nil {
+			c.Logger.Er("failed to write response", err)
+		}
+		return
+	}
+
+	framework, _ := r.Context().Value("framework").(string)
+	err = c.Respond(w, r, http.StatusOK, validation.Summarize(&req, validation.ModeAdapted, framework))
+	if err != nil {
+		c.Logger.Er("failed to write response", err)
+		return
+	}
+
+	c.logTestResult("validation", time.Since(start).Milliseconds(), true)
+}
@@ -33,6 +33,10 @@ func New(cfg config.Config) (*DB, error) {
 		return nil, err
 	}
 
+	if maxConns := cfg.DatabaseConfig.MaxConns; maxConns > 0 {
+		sqlDB.SetMaxOpenConns(maxConns)
+	}
+
 	if err := sqlDB.Ping(); err != nil {
 		log.Er("failed to ping database/sql", err)
 		return nil, err
@@ -46,6 +50,14 @@ func New(cfg config.Config) (*DB, error) {
 		log.Er("failed to open GORM connection", err)
 		return nil, err
 	}
+	if maxConns := cfg.DatabaseConfig.MaxConns; maxConns > 0 {
+		gormSQL, err := gormDB.DB()
+		if err != nil {
+			log.Er("failed to get GORM connection pool", err)
+			return nil, err
+		}
+		gormSQL.SetMaxOpenConns(maxConns)
+	}
 	log.Info("GORM connection established")
 
 	sqlxDB, err := sqlx.Connect("postgres", dsn)
@@ -53,9 +65,21 @@ func New(cfg config.Config) (*DB, error) {
 		log.Er("failed to open SQLx connection", err)
 		return nil, err
 	}
+	if maxConns := cfg.DatabaseConfig.MaxConns; maxConns > 0 {
+		sqlxDB.SetMaxOpenConns(maxConns)
+	}
 	log.Info("SQLx connection established")
 
-	pgxPool, err := pgxpool.New(context.Background(), dsn)
+	pgxConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		log.Er("failed to parse PGX config", err)
+		return nil, err
+	}
+	if maxConns := cfg.DatabaseConfig.MaxConns; maxConns > 0 {
+		pgxConfig.MaxConns = int32(maxConns)
+	}
+
+	pgxPool, err := pgxpool.NewWithConfig(context.Background(), pgxConfig)
 	if err != nil {
 		log.Er("failed to create PGX pool", err)
 		return nil, err
@@ -0,0 +1,508 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: bananas/v1/api.proto
+
+package bananasv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// GET /api/test/simple
+type SimpleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Framework     string                 `protobuf:"bytes,2,opt,name=framework,proto3" json:"framework,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimpleResponse) Reset() {
+	*x = SimpleResponse{}
+	mi := &file_bananas_v1_api_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimpleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimpleResponse) ProtoMessage() {}
+
+func (x *SimpleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_api_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimpleResponse.ProtoReflect.Descriptor instead.
+func (*SimpleResponse) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_api_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SimpleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SimpleResponse) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+// GET /api/test/json
+type JsonResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Framework     string                 `protobuf:"bytes,2,opt,name=framework,proto3" json:"framework,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Data          []*JsonResponse_Item   `protobuf:"bytes,4,rep,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonResponse) Reset() {
+	*x = JsonResponse{}
+	mi := &file_bananas_v1_api_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonResponse) ProtoMessage() {}
+
+func (x *JsonResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_api_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonResponse.ProtoReflect.Descriptor instead.
+func (*JsonResponse) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *JsonResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *JsonResponse) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+func (x *JsonResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *JsonResponse) GetData() []*JsonResponse_Item {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// GET /api/test/database
+type DatabaseQueryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*TestResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Orm           string                 `protobuf:"bytes,3,opt,name=orm,proto3" json:"orm,omitempty"`
+	Framework     string                 `protobuf:"bytes,4,opt,name=framework,proto3" json:"framework,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseQueryResponse) Reset() {
+	*x = DatabaseQueryResponse{}
+	mi := &file_bananas_v1_api_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseQueryResponse) ProtoMessage() {}
+
+func (x *DatabaseQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_api_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseQueryResponse.ProtoReflect.Descriptor instead.
+func (*DatabaseQueryResponse) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DatabaseQueryResponse) GetResults() []*TestResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *DatabaseQueryResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DatabaseQueryResponse) GetOrm() string {
+	if x != nil {
+		return x.Orm
+	}
+	return ""
+}
+
+func (x *DatabaseQueryResponse) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+// GET /api/orders/recent
+type RecentOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*OrderWithDetails    `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Orm           string                 `protobuf:"bytes,3,opt,name=orm,proto3" json:"orm,omitempty"`
+	Framework     string                 `protobuf:"bytes,4,opt,name=framework,proto3" json:"framework,omitempty"`
+	DbTime        int64                  `protobuf:"varint,5,opt,name=db_time,json=dbTime,proto3" json:"db_time,omitempty"`
+	TotalTime     int64                  `protobuf:"varint,6,opt,name=total_time,json=totalTime,proto3" json:"total_time,omitempty"`
+	FrameworkTime int64                  `protobuf:"varint,7,opt,name=framework_time,json=frameworkTime,proto3" json:"framework_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecentOrdersResponse) Reset() {
+	*x = RecentOrdersResponse{}
+	mi := &file_bananas_v1_api_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecentOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecentOrdersResponse) ProtoMessage() {}
+
+func (x *RecentOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_api_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecentOrdersResponse.ProtoReflect.Descriptor instead.
+func (*RecentOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RecentOrdersResponse) GetOrders() []*OrderWithDetails {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *RecentOrdersResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RecentOrdersResponse) GetOrm() string {
+	if x != nil {
+		return x.Orm
+	}
+	return ""
+}
+
+func (x *RecentOrdersResponse) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+func (x *RecentOrdersResponse) GetDbTime() int64 {
+	if x != nil {
+		return x.DbTime
+	}
+	return 0
+}
+
+func (x *RecentOrdersResponse) GetTotalTime() int64 {
+	if x != nil {
+		return x.TotalTime
+	}
+	return 0
+}
+
+func (x *RecentOrdersResponse) GetFrameworkTime() int64 {
+	if x != nil {
+		return x.FrameworkTime
+	}
+	return 0
+}
+
+// Any endpoint's error body.
+type ErrorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorResponse) Reset() {
+	*x = ErrorResponse{}
+	mi := &file_bananas_v1_api_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorResponse) ProtoMessage() {}
+
+func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_api_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
+func (*ErrorResponse) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_api_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ErrorResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type JsonResponse_Item struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonResponse_Item) Reset() {
+	*x = JsonResponse_Item{}
+	mi := &file_bananas_v1_api_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonResponse_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonResponse_Item) ProtoMessage() {}
+
+func (x *JsonResponse_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_api_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonResponse_Item.ProtoReflect.Descriptor instead.
+func (*JsonResponse_Item) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_api_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *JsonResponse_Item) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *JsonResponse_Item) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JsonResponse_Item) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+var File_bananas_v1_api_proto protoreflect.FileDescriptor
+
+const file_bananas_v1_api_proto_rawDesc = "" +
+	"\n" +
+	"\x14bananas/v1/api.proto\x12\n" +
+	"bananas.v1\x1a\x17bananas/v1/models.proto\"H\n" +
+	"\x0eSimpleResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1c\n" +
+	"\tframework\x18\x02 \x01(\tR\tframework\"\xd9\x01\n" +
+	"\fJsonResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1c\n" +
+	"\tframework\x18\x02 \x01(\tR\tframework\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x121\n" +
+	"\x04data\x18\x04 \x03(\v2\x1d.bananas.v1.JsonResponse.ItemR\x04data\x1a@\n" +
+	"\x04Item\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"\x8f\x01\n" +
+	"\x15DatabaseQueryResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.bananas.v1.TestResultR\aresults\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x10\n" +
+	"\x03orm\x18\x03 \x01(\tR\x03orm\x12\x1c\n" +
+	"\tframework\x18\x04 \x01(\tR\tframework\"\xf1\x01\n" +
+	"\x14RecentOrdersResponse\x124\n" +
+	"\x06orders\x18\x01 \x03(\v2\x1c.bananas.v1.OrderWithDetailsR\x06orders\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x10\n" +
+	"\x03orm\x18\x03 \x01(\tR\x03orm\x12\x1c\n" +
+	"\tframework\x18\x04 \x01(\tR\tframework\x12\x17\n" +
+	"\adb_time\x18\x05 \x01(\x03R\x06dbTime\x12\x1d\n" +
+	"\n" +
+	"total_time\x18\x06 \x01(\x03R\ttotalTime\x12%\n" +
+	"\x0eframework_time\x18\a \x01(\x03R\rframeworkTime\"%\n" +
+	"\rErrorResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05errorB\x8e\x01\n" +
+	"\x0ecom.bananas.v1B\bApiProtoP\x01Z)bananas/internal/gen/bananas/v1;bananasv1\xa2\x02\x03BXX\xaa\x02\n" +
+	"Bananas.V1\xca\x02\n" +
+	"Bananas\\V1\xe2\x02\x16Bananas\\V1\\GPBMetadata\xea\x02\vBananas::V1b\x06proto3"
+
+var (
+	file_bananas_v1_api_proto_rawDescOnce sync.Once
+	file_bananas_v1_api_proto_rawDescData []byte
+)
+
+func file_bananas_v1_api_proto_rawDescGZIP() []byte {
+	file_bananas_v1_api_proto_rawDescOnce.Do(func() {
+		file_bananas_v1_api_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bananas_v1_api_proto_rawDesc), len(file_bananas_v1_api_proto_rawDesc)))
+	})
+	return file_bananas_v1_api_proto_rawDescData
+}
+
+var file_bananas_v1_api_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_bananas_v1_api_proto_goTypes = []any{
+	(*SimpleResponse)(nil),        // 0: bananas.v1.SimpleResponse
+	(*JsonResponse)(nil),          // 1: bananas.v1.JsonResponse
+	(*DatabaseQueryResponse)(nil), // 2: bananas.v1.DatabaseQueryResponse
+	(*RecentOrdersResponse)(nil),  // 3: bananas.v1.RecentOrdersResponse
+	(*ErrorResponse)(nil),         // 4: bananas.v1.ErrorResponse
+	(*JsonResponse_Item)(nil),     // 5: bananas.v1.JsonResponse.Item
+	(*TestResult)(nil),            // 6: bananas.v1.TestResult
+	(*OrderWithDetails)(nil),      // 7: bananas.v1.OrderWithDetails
+}
+var file_bananas_v1_api_proto_depIdxs = []int32{
+	5, // 0: bananas.v1.JsonResponse.data:type_name -> bananas.v1.JsonResponse.Item
+	6, // 1: bananas.v1.DatabaseQueryResponse.results:type_name -> bananas.v1.TestResult
+	7, // 2: bananas.v1.RecentOrdersResponse.orders:type_name -> bananas.v1.OrderWithDetails
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_bananas_v1_api_proto_init() }
+func file_bananas_v1_api_proto_init() {
+	if File_bananas_v1_api_proto != nil {
+		return
+	}
+	file_bananas_v1_models_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bananas_v1_api_proto_rawDesc), len(file_bananas_v1_api_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bananas_v1_api_proto_goTypes,
+		DependencyIndexes: file_bananas_v1_api_proto_depIdxs,
+		MessageInfos:      file_bananas_v1_api_proto_msgTypes,
+	}.Build()
+	File_bananas_v1_api_proto = out.File
+	file_bananas_v1_api_proto_goTypes = nil
+	file_bananas_v1_api_proto_depIdxs = nil
+}
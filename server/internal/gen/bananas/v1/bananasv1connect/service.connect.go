@@ -0,0 +1,237 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: bananas/v1/service.proto
+
+package bananasv1connect
+
+import (
+	v1 "bananas/internal/gen/bananas/v1"
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// BananasServiceName is the fully-qualified name of the BananasService service.
+	BananasServiceName = "bananas.v1.BananasService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// BananasServiceSimpleRequestProcedure is the fully-qualified name of the BananasService's
+	// SimpleRequest RPC.
+	BananasServiceSimpleRequestProcedure = "/bananas.v1.BananasService/SimpleRequest"
+	// BananasServiceJsonResponseProcedure is the fully-qualified name of the BananasService's
+	// JsonResponse RPC.
+	BananasServiceJsonResponseProcedure = "/bananas.v1.BananasService/JsonResponse"
+	// BananasServiceGetRecentOrdersProcedure is the fully-qualified name of the BananasService's
+	// GetRecentOrders RPC.
+	BananasServiceGetRecentOrdersProcedure = "/bananas.v1.BananasService/GetRecentOrders"
+	// BananasServiceListOrdersProcedure is the fully-qualified name of the BananasService's ListOrders
+	// RPC.
+	BananasServiceListOrdersProcedure = "/bananas.v1.BananasService/ListOrders"
+	// BananasServiceStreamOrdersProcedure is the fully-qualified name of the BananasService's
+	// StreamOrders RPC.
+	BananasServiceStreamOrdersProcedure = "/bananas.v1.BananasService/StreamOrders"
+)
+
+// BananasServiceClient is a client for the bananas.v1.BananasService service.
+type BananasServiceClient interface {
+	// Mirrors GET /api/test/simple.
+	SimpleRequest(context.Context, *connect.Request[v1.SimpleRequestRequest]) (*connect.Response[v1.SimpleResponse], error)
+	// Mirrors GET /api/test/json.
+	JsonResponse(context.Context, *connect.Request[v1.JsonResponseRequest]) (*connect.Response[v1.JsonResponse], error)
+	// Mirrors GET /api/orders/recent.
+	GetRecentOrders(context.Context, *connect.Request[v1.GetRecentOrdersRequest]) (*connect.Response[v1.RecentOrdersResponse], error)
+	// Pages through sales orders, newest first, without their details.
+	ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error)
+	// Sends the most recent orders one message at a time as they are read
+	// from a database cursor, without their customer or items.
+	StreamOrders(context.Context, *connect.Request[v1.StreamOrdersRequest]) (*connect.ServerStreamForClient[v1.OrderWithDetails], error)
+}
+
+// NewBananasServiceClient constructs a client for the bananas.v1.BananasService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewBananasServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) BananasServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	bananasServiceMethods := v1.File_bananas_v1_service_proto.Services().ByName("BananasService").Methods()
+	return &bananasServiceClient{
+		simpleRequest: connect.NewClient[v1.SimpleRequestRequest, v1.SimpleResponse](
+			httpClient,
+			baseURL+BananasServiceSimpleRequestProcedure,
+			connect.WithSchema(bananasServiceMethods.ByName("SimpleRequest")),
+			connect.WithClientOptions(opts...),
+		),
+		jsonResponse: connect.NewClient[v1.JsonResponseRequest, v1.JsonResponse](
+			httpClient,
+			baseURL+BananasServiceJsonResponseProcedure,
+			connect.WithSchema(bananasServiceMethods.ByName("JsonResponse")),
+			connect.WithClientOptions(opts...),
+		),
+		getRecentOrders: connect.NewClient[v1.GetRecentOrdersRequest, v1.RecentOrdersResponse](
+			httpClient,
+			baseURL+BananasServiceGetRecentOrdersProcedure,
+			connect.WithSchema(bananasServiceMethods.ByName("GetRecentOrders")),
+			connect.WithClientOptions(opts...),
+		),
+		listOrders: connect.NewClient[v1.ListOrdersRequest, v1.ListOrdersResponse](
+			httpClient,
+			baseURL+BananasServiceListOrdersProcedure,
+			connect.WithSchema(bananasServiceMethods.ByName("ListOrders")),
+			connect.WithClientOptions(opts...),
+		),
+		streamOrders: connect.NewClient[v1.StreamOrdersRequest, v1.OrderWithDetails](
+			httpClient,
+			baseURL+BananasServiceStreamOrdersProcedure,
+			connect.WithSchema(bananasServiceMethods.ByName("StreamOrders")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// bananasServiceClient implements BananasServiceClient.
+type bananasServiceClient struct {
+	simpleRequest   *connect.Client[v1.SimpleRequestRequest, v1.SimpleResponse]
+	jsonResponse    *connect.Client[v1.JsonResponseRequest, v1.JsonResponse]
+	getRecentOrders *connect.Client[v1.GetRecentOrdersRequest, v1.RecentOrdersResponse]
+	listOrders      *connect.Client[v1.ListOrdersRequest, v1.ListOrdersResponse]
+	streamOrders    *connect.Client[v1.StreamOrdersRequest, v1.OrderWithDetails]
+}
+
+// SimpleRequest calls bananas.v1.BananasService.SimpleRequest.
+func (c *bananasServiceClient) SimpleRequest(ctx context.Context, req *connect.Request[v1.SimpleRequestRequest]) (*connect.Response[v1.SimpleResponse], error) {
+	return c.simpleRequest.CallUnary(ctx, req)
+}
+
+// JsonResponse calls bananas.v1.BananasService.JsonResponse.
+func (c *bananasServiceClient) JsonResponse(ctx context.Context, req *connect.Request[v1.JsonResponseRequest]) (*connect.Response[v1.JsonResponse], error) {
+	return c.jsonResponse.CallUnary(ctx, req)
+}
+
+// GetRecentOrders calls bananas.v1.BananasService.GetRecentOrders.
+func (c *bananasServiceClient) GetRecentOrders(ctx context.Context, req *connect.Request[v1.GetRecentOrdersRequest]) (*connect.Response[v1.RecentOrdersResponse], error) {
+	return c.getRecentOrders.CallUnary(ctx, req)
+}
+
+// ListOrders calls bananas.v1.BananasService.ListOrders.
+func (c *bananasServiceClient) ListOrders(ctx context.Context, req *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error) {
+	return c.listOrders.CallUnary(ctx, req)
+}
+
+// StreamOrders calls bananas.v1.BananasService.StreamOrders.
+func (c *bananasServiceClient) StreamOrders(ctx context.Context, req *connect.Request[v1.StreamOrdersRequest]) (*connect.ServerStreamForClient[v1.OrderWithDetails], error) {
+	return c.streamOrders.CallServerStream(ctx, req)
+}
+
+// BananasServiceHandler is an implementation of the bananas.v1.BananasService service.
+type BananasServiceHandler interface {
+	// Mirrors GET /api/test/simple.
+	SimpleRequest(context.Context, *connect.Request[v1.SimpleRequestRequest]) (*connect.Response[v1.SimpleResponse], error)
+	// Mirrors GET /api/test/json.
+	JsonResponse(context.Context, *connect.Request[v1.JsonResponseRequest]) (*connect.Response[v1.JsonResponse], error)
+	// Mirrors GET /api/orders/recent.
+	GetRecentOrders(context.Context, *connect.Request[v1.GetRecentOrdersRequest]) (*connect.Response[v1.RecentOrdersResponse], error)
+	// Pages through sales orders, newest first, without their details.
+	ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error)
+	// Sends the most recent orders one message at a time as they are read
+	// from a database cursor, without their customer or items.
+	StreamOrders(context.Context, *connect.Request[v1.StreamOrdersRequest], *connect.ServerStream[v1.OrderWithDetails]) error
+}
+
+// NewBananasServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewBananasServiceHandler(svc BananasServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	bananasServiceMethods := v1.File_bananas_v1_service_proto.Services().ByName("BananasService").Methods()
+	bananasServiceSimpleRequestHandler := connect.NewUnaryHandler(
+		BananasServiceSimpleRequestProcedure,
+		svc.SimpleRequest,
+		connect.WithSchema(bananasServiceMethods.ByName("SimpleRequest")),
+		connect.WithHandlerOptions(opts...),
+	)
+	bananasServiceJsonResponseHandler := connect.NewUnaryHandler(
+		BananasServiceJsonResponseProcedure,
+		svc.JsonResponse,
+		connect.WithSchema(bananasServiceMethods.ByName("JsonResponse")),
+		connect.WithHandlerOptions(opts...),
+	)
+	bananasServiceGetRecentOrdersHandler := connect.NewUnaryHandler(
+		BananasServiceGetRecentOrdersProcedure,
+		svc.GetRecentOrders,
+		connect.WithSchema(bananasServiceMethods.ByName("GetRecentOrders")),
+		connect.WithHandlerOptions(opts...),
+	)
+	bananasServiceListOrdersHandler := connect.NewUnaryHandler(
+		BananasServiceListOrdersProcedure,
+		svc.ListOrders,
+		connect.WithSchema(bananasServiceMethods.ByName("ListOrders")),
+		connect.WithHandlerOptions(opts...),
+	)
+	bananasServiceStreamOrdersHandler := connect.NewServerStreamHandler(
+		BananasServiceStreamOrdersProcedure,
+		svc.StreamOrders,
+		connect.WithSchema(bananasServiceMethods.ByName("StreamOrders")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/bananas.v1.BananasService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case BananasServiceSimpleRequestProcedure:
+			bananasServiceSimpleRequestHandler.ServeHTTP(w, r)
+		case BananasServiceJsonResponseProcedure:
+			bananasServiceJsonResponseHandler.ServeHTTP(w, r)
+		case BananasServiceGetRecentOrdersProcedure:
+			bananasServiceGetRecentOrdersHandler.ServeHTTP(w, r)
+		case BananasServiceListOrdersProcedure:
+			bananasServiceListOrdersHandler.ServeHTTP(w, r)
+		case BananasServiceStreamOrdersProcedure:
+			bananasServiceStreamOrdersHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedBananasServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedBananasServiceHandler struct{}
+
+func (UnimplementedBananasServiceHandler) SimpleRequest(context.Context, *connect.Request[v1.SimpleRequestRequest]) (*connect.Response[v1.SimpleResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bananas.v1.BananasService.SimpleRequest is not implemented"))
+}
+
+func (UnimplementedBananasServiceHandler) JsonResponse(context.Context, *connect.Request[v1.JsonResponseRequest]) (*connect.Response[v1.JsonResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bananas.v1.BananasService.JsonResponse is not implemented"))
+}
+
+func (UnimplementedBananasServiceHandler) GetRecentOrders(context.Context, *connect.Request[v1.GetRecentOrdersRequest]) (*connect.Response[v1.RecentOrdersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bananas.v1.BananasService.GetRecentOrders is not implemented"))
+}
+
+func (UnimplementedBananasServiceHandler) ListOrders(context.Context, *connect.Request[v1.ListOrdersRequest]) (*connect.Response[v1.ListOrdersResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("bananas.v1.BananasService.ListOrders is not implemented"))
+}
+
+func (UnimplementedBananasServiceHandler) StreamOrders(context.Context, *connect.Request[v1.StreamOrdersRequest], *connect.ServerStream[v1.OrderWithDetails]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("bananas.v1.BananasService.StreamOrders is not implemented"))
+}
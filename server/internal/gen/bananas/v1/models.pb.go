@@ -0,0 +1,876 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: bananas/v1/models.proto
+
+package bananasv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SalesOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderNumber   string                 `protobuf:"bytes,2,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	CustomerId    string                 `protobuf:"bytes,3,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	OrderDate     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Subtotal      float64                `protobuf:"fixed64,6,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Tax           float64                `protobuf:"fixed64,7,opt,name=tax,proto3" json:"tax,omitempty"`
+	Shipping      float64                `protobuf:"fixed64,8,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	Total         float64                `protobuf:"fixed64,9,opt,name=total,proto3" json:"total,omitempty"`
+	Notes         *string                `protobuf:"bytes,10,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SalesOrder) Reset() {
+	*x = SalesOrder{}
+	mi := &file_bananas_v1_models_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SalesOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SalesOrder) ProtoMessage() {}
+
+func (x *SalesOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SalesOrder.ProtoReflect.Descriptor instead.
+func (*SalesOrder) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SalesOrder) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SalesOrder) GetOrderNumber() string {
+	if x != nil {
+		return x.OrderNumber
+	}
+	return ""
+}
+
+func (x *SalesOrder) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *SalesOrder) GetOrderDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OrderDate
+	}
+	return nil
+}
+
+func (x *SalesOrder) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SalesOrder) GetSubtotal() float64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *SalesOrder) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *SalesOrder) GetShipping() float64 {
+	if x != nil {
+		return x.Shipping
+	}
+	return 0
+}
+
+func (x *SalesOrder) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SalesOrder) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *SalesOrder) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SalesOrder) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *SalesOrder) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type SalesOrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SalesOrderId  string                 `protobuf:"bytes,2,opt,name=sales_order_id,json=salesOrderId,proto3" json:"sales_order_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice     float64                `protobuf:"fixed64,5,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Discount      float64                `protobuf:"fixed64,6,opt,name=discount,proto3" json:"discount,omitempty"`
+	Tax           float64                `protobuf:"fixed64,7,opt,name=tax,proto3" json:"tax,omitempty"`
+	Total         float64                `protobuf:"fixed64,8,opt,name=total,proto3" json:"total,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SalesOrderItem) Reset() {
+	*x = SalesOrderItem{}
+	mi := &file_bananas_v1_models_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SalesOrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SalesOrderItem) ProtoMessage() {}
+
+func (x *SalesOrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SalesOrderItem.ProtoReflect.Descriptor instead.
+func (*SalesOrderItem) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SalesOrderItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SalesOrderItem) GetSalesOrderId() string {
+	if x != nil {
+		return x.SalesOrderId
+	}
+	return ""
+}
+
+func (x *SalesOrderItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SalesOrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *SalesOrderItem) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *SalesOrderItem) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *SalesOrderItem) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *SalesOrderItem) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SalesOrderItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SalesOrderItem) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Customer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FirstName     string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         *string                `protobuf:"bytes,5,opt,name=phone,proto3,oneof" json:"phone,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	mi := &file_bananas_v1_models_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Customer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Customer) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Customer) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Customer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Customer) GetPhone() string {
+	if x != nil && x.Phone != nil {
+		return *x.Phone
+	}
+	return ""
+}
+
+func (x *Customer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Customer) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Customer) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   *string                `protobuf:"bytes,4,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Weight        *float64               `protobuf:"fixed64,5,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
+	Dimensions    *string                `protobuf:"bytes,6,opt,name=dimensions,proto3,oneof" json:"dimensions,omitempty"`
+	IsActive      bool                   `protobuf:"varint,7,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_bananas_v1_models_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *Product) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Product) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *Product) GetWeight() float64 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+func (x *Product) GetDimensions() string {
+	if x != nil && x.Dimensions != nil {
+		return *x.Dimensions
+	}
+	return ""
+}
+
+func (x *Product) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Product) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Product) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type OrderItemWithProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *SalesOrderItem        `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Product       *Product               `protobuf:"bytes,2,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItemWithProduct) Reset() {
+	*x = OrderItemWithProduct{}
+	mi := &file_bananas_v1_models_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItemWithProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItemWithProduct) ProtoMessage() {}
+
+func (x *OrderItemWithProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItemWithProduct.ProtoReflect.Descriptor instead.
+func (*OrderItemWithProduct) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderItemWithProduct) GetItem() *SalesOrderItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *OrderItemWithProduct) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type OrderWithDetails struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Order         *SalesOrder             `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Customer      *Customer               `protobuf:"bytes,2,opt,name=customer,proto3" json:"customer,omitempty"`
+	Items         []*OrderItemWithProduct `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderWithDetails) Reset() {
+	*x = OrderWithDetails{}
+	mi := &file_bananas_v1_models_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderWithDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderWithDetails) ProtoMessage() {}
+
+func (x *OrderWithDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderWithDetails.ProtoReflect.Descriptor instead.
+func (*OrderWithDetails) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderWithDetails) GetOrder() *SalesOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderWithDetails) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *OrderWithDetails) GetItems() []*OrderItemWithProduct {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type TestResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Framework     string                 `protobuf:"bytes,2,opt,name=framework,proto3" json:"framework,omitempty"`
+	TestType      string                 `protobuf:"bytes,3,opt,name=test_type,json=testType,proto3" json:"test_type,omitempty"`
+	ExecutionMs   int64                  `protobuf:"varint,4,opt,name=execution_ms,json=executionMs,proto3" json:"execution_ms,omitempty"`
+	Success       bool                   `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestResult) Reset() {
+	*x = TestResult{}
+	mi := &file_bananas_v1_models_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestResult) ProtoMessage() {}
+
+func (x *TestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_models_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestResult.ProtoReflect.Descriptor instead.
+func (*TestResult) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_models_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TestResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TestResult) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+func (x *TestResult) GetTestType() string {
+	if x != nil {
+		return x.TestType
+	}
+	return ""
+}
+
+func (x *TestResult) GetExecutionMs() int64 {
+	if x != nil {
+		return x.ExecutionMs
+	}
+	return 0
+}
+
+func (x *TestResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TestResult) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TestResult) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_bananas_v1_models_proto protoreflect.FileDescriptor
+
+const file_bananas_v1_models_proto_rawDesc = "" +
+	"\n" +
+	"\x17bananas/v1/models.proto\x12\n" +
+	"bananas.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe9\x03\n" +
+	"\n" +
+	"SalesOrder\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\forder_number\x18\x02 \x01(\tR\vorderNumber\x12\x1f\n" +
+	"\vcustomer_id\x18\x03 \x01(\tR\n" +
+	"customerId\x129\n" +
+	"\n" +
+	"order_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\torderDate\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\bsubtotal\x18\x06 \x01(\x01R\bsubtotal\x12\x10\n" +
+	"\x03tax\x18\a \x01(\x01R\x03tax\x12\x1a\n" +
+	"\bshipping\x18\b \x01(\x01R\bshipping\x12\x14\n" +
+	"\x05total\x18\t \x01(\x01R\x05total\x12\x19\n" +
+	"\x05notes\x18\n" +
+	" \x01(\tH\x00R\x05notes\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAtB\b\n" +
+	"\x06_notes\"\xda\x02\n" +
+	"\x0eSalesOrderItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12$\n" +
+	"\x0esales_order_id\x18\x02 \x01(\tR\fsalesOrderId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x05 \x01(\x01R\tunitPrice\x12\x1a\n" +
+	"\bdiscount\x18\x06 \x01(\x01R\bdiscount\x12\x10\n" +
+	"\x03tax\x18\a \x01(\x01R\x03tax\x12\x14\n" +
+	"\x05total\x18\b \x01(\x01R\x05total\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc2\x02\n" +
+	"\bCustomer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x19\n" +
+	"\x05phone\x18\x05 \x01(\tH\x00R\x05phone\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAtB\b\n" +
+	"\x06_phone\"\xa0\x03\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12%\n" +
+	"\vdescription\x18\x04 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x1b\n" +
+	"\x06weight\x18\x05 \x01(\x01H\x01R\x06weight\x88\x01\x01\x12#\n" +
+	"\n" +
+	"dimensions\x18\x06 \x01(\tH\x02R\n" +
+	"dimensions\x88\x01\x01\x12\x1b\n" +
+	"\tis_active\x18\a \x01(\bR\bisActive\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAtB\x0e\n" +
+	"\f_descriptionB\t\n" +
+	"\a_weightB\r\n" +
+	"\v_dimensions\"u\n" +
+	"\x14OrderItemWithProduct\x12.\n" +
+	"\x04item\x18\x01 \x01(\v2\x1a.bananas.v1.SalesOrderItemR\x04item\x12-\n" +
+	"\aproduct\x18\x02 \x01(\v2\x13.bananas.v1.ProductR\aproduct\"\xaa\x01\n" +
+	"\x10OrderWithDetails\x12,\n" +
+	"\x05order\x18\x01 \x01(\v2\x16.bananas.v1.SalesOrderR\x05order\x120\n" +
+	"\bcustomer\x18\x02 \x01(\v2\x14.bananas.v1.CustomerR\bcustomer\x126\n" +
+	"\x05items\x18\x03 \x03(\v2 .bananas.v1.OrderItemWithProductR\x05items\"\x8a\x02\n" +
+	"\n" +
+	"TestResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1c\n" +
+	"\tframework\x18\x02 \x01(\tR\tframework\x12\x1b\n" +
+	"\ttest_type\x18\x03 \x01(\tR\btestType\x12!\n" +
+	"\fexecution_ms\x18\x04 \x01(\x03R\vexecutionMs\x12\x18\n" +
+	"\asuccess\x18\x05 \x01(\bR\asuccess\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x91\x01\n" +
+	"\x0ecom.bananas.v1B\vModelsProtoP\x01Z)bananas/internal/gen/bananas/v1;bananasv1\xa2\x02\x03BXX\xaa\x02\n" +
+	"Bananas.V1\xca\x02\n" +
+	"Bananas\\V1\xe2\x02\x16Bananas\\V1\\GPBMetadata\xea\x02\vBananas::V1b\x06proto3"
+
+var (
+	file_bananas_v1_models_proto_rawDescOnce sync.Once
+	file_bananas_v1_models_proto_rawDescData []byte
+)
+
+func file_bananas_v1_models_proto_rawDescGZIP() []byte {
+	file_bananas_v1_models_proto_rawDescOnce.Do(func() {
+		file_bananas_v1_models_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bananas_v1_models_proto_rawDesc), len(file_bananas_v1_models_proto_rawDesc)))
+	})
+	return file_bananas_v1_models_proto_rawDescData
+}
+
+var file_bananas_v1_models_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_bananas_v1_models_proto_goTypes = []any{
+	(*SalesOrder)(nil),            // 0: bananas.v1.SalesOrder
+	(*SalesOrderItem)(nil),        // 1: bananas.v1.SalesOrderItem
+	(*Customer)(nil),              // 2: bananas.v1.Customer
+	(*Product)(nil),               // 3: bananas.v1.Product
+	(*OrderItemWithProduct)(nil),  // 4: bananas.v1.OrderItemWithProduct
+	(*OrderWithDetails)(nil),      // 5: bananas.v1.OrderWithDetails
+	(*TestResult)(nil),            // 6: bananas.v1.TestResult
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_bananas_v1_models_proto_depIdxs = []int32{
+	7,  // 0: bananas.v1.SalesOrder.order_date:type_name -> google.protobuf.Timestamp
+	7,  // 1: bananas.v1.SalesOrder.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 2: bananas.v1.SalesOrder.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 3: bananas.v1.SalesOrder.deleted_at:type_name -> google.protobuf.Timestamp
+	7,  // 4: bananas.v1.SalesOrderItem.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 5: bananas.v1.SalesOrderItem.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 6: bananas.v1.Customer.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 7: bananas.v1.Customer.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 8: bananas.v1.Customer.deleted_at:type_name -> google.protobuf.Timestamp
+	7,  // 9: bananas.v1.Product.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 10: bananas.v1.Product.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 11: bananas.v1.Product.deleted_at:type_name -> google.protobuf.Timestamp
+	1,  // 12: bananas.v1.OrderItemWithProduct.item:type_name -> bananas.v1.SalesOrderItem
+	3,  // 13: bananas.v1.OrderItemWithProduct.product:type_name -> bananas.v1.Product
+	0,  // 14: bananas.v1.OrderWithDetails.order:type_name -> bananas.v1.SalesOrder
+	2,  // 15: bananas.v1.OrderWithDetails.customer:type_name -> bananas.v1.Customer
+	4,  // 16: bananas.v1.OrderWithDetails.items:type_name -> bananas.v1.OrderItemWithProduct
+	7,  // 17: bananas.v1.TestResult.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 18: bananas.v1.TestResult.updated_at:type_name -> google.protobuf.Timestamp
+	19, // [19:19] is the sub-list for method output_type
+	19, // [19:19] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_bananas_v1_models_proto_init() }
+func file_bananas_v1_models_proto_init() {
+	if File_bananas_v1_models_proto != nil {
+		return
+	}
+	file_bananas_v1_models_proto_msgTypes[0].OneofWrappers = []any{}
+	file_bananas_v1_models_proto_msgTypes[2].OneofWrappers = []any{}
+	file_bananas_v1_models_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bananas_v1_models_proto_rawDesc), len(file_bananas_v1_models_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bananas_v1_models_proto_goTypes,
+		DependencyIndexes: file_bananas_v1_models_proto_depIdxs,
+		MessageInfos:      file_bananas_v1_models_proto_msgTypes,
+	}.Build()
+	File_bananas_v1_models_proto = out.File
+	file_bananas_v1_models_proto_goTypes = nil
+	file_bananas_v1_models_proto_depIdxs = nil
+}
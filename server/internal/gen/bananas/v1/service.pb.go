@@ -0,0 +1,459 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: bananas/v1/service.proto
+
+package bananasv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SimpleRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimpleRequestRequest) Reset() {
+	*x = SimpleRequestRequest{}
+	mi := &file_bananas_v1_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimpleRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimpleRequestRequest) ProtoMessage() {}
+
+func (x *SimpleRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimpleRequestRequest.ProtoReflect.Descriptor instead.
+func (*SimpleRequestRequest) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
+type JsonResponseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonResponseRequest) Reset() {
+	*x = JsonResponseRequest{}
+	mi := &file_bananas_v1_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonResponseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonResponseRequest) ProtoMessage() {}
+
+func (x *JsonResponseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonResponseRequest.ProtoReflect.Descriptor instead.
+func (*JsonResponseRequest) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_service_proto_rawDescGZIP(), []int{1}
+}
+
+type GetRecentOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sql, gorm, sqlx or pgx; defaults to sql.
+	Orm string `protobuf:"bytes,1,opt,name=orm,proto3" json:"orm,omitempty"`
+	// Defaults to 100, at most 1000.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecentOrdersRequest) Reset() {
+	*x = GetRecentOrdersRequest{}
+	mi := &file_bananas_v1_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecentOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecentOrdersRequest) ProtoMessage() {}
+
+func (x *GetRecentOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecentOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetRecentOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRecentOrdersRequest) GetOrm() string {
+	if x != nil {
+		return x.Orm
+	}
+	return ""
+}
+
+func (x *GetRecentOrdersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sql, gorm, sqlx or pgx; defaults to sql.
+	Orm string `protobuf:"bytes,1,opt,name=orm,proto3" json:"orm,omitempty"`
+	// Only return orders in this status when set.
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Defaults to 100, at most 1000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from a previous response.
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_bananas_v1_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListOrdersRequest) GetOrm() string {
+	if x != nil {
+		return x.Orm
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOrdersResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Orders []*SalesOrder          `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Orm           string `protobuf:"bytes,3,opt,name=orm,proto3" json:"orm,omitempty"`
+	Framework     string `protobuf:"bytes,4,opt,name=framework,proto3" json:"framework,omitempty"`
+	DbTime        int64  `protobuf:"varint,5,opt,name=db_time,json=dbTime,proto3" json:"db_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_bananas_v1_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*SalesOrder {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersResponse) GetOrm() string {
+	if x != nil {
+		return x.Orm
+	}
+	return ""
+}
+
+func (x *ListOrdersResponse) GetFramework() string {
+	if x != nil {
+		return x.Framework
+	}
+	return ""
+}
+
+func (x *ListOrdersResponse) GetDbTime() int64 {
+	if x != nil {
+		return x.DbTime
+	}
+	return 0
+}
+
+type StreamOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sql, gorm, sqlx or pgx; defaults to sql.
+	Orm string `protobuf:"bytes,1,opt,name=orm,proto3" json:"orm,omitempty"`
+	// Defaults to 100, at most 1000.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrdersRequest) Reset() {
+	*x = StreamOrdersRequest{}
+	mi := &file_bananas_v1_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrdersRequest) ProtoMessage() {}
+
+func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bananas_v1_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_bananas_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamOrdersRequest) GetOrm() string {
+	if x != nil {
+		return x.Orm
+	}
+	return ""
+}
+
+func (x *StreamOrdersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+var File_bananas_v1_service_proto protoreflect.FileDescriptor
+
+const file_bananas_v1_service_proto_rawDesc = "" +
+	"\n" +
+	"\x18bananas/v1/service.proto\x12\n" +
+	"bananas.v1\x1a\x14bananas/v1/api.proto\x1a\x17bananas/v1/models.proto\"\x16\n" +
+	"\x14SimpleRequestRequest\"\x15\n" +
+	"\x13JsonResponseRequest\"@\n" +
+	"\x16GetRecentOrdersRequest\x12\x10\n" +
+	"\x03orm\x18\x01 \x01(\tR\x03orm\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"y\n" +
+	"\x11ListOrdersRequest\x12\x10\n" +
+	"\x03orm\x18\x01 \x01(\tR\x03orm\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\xb5\x01\n" +
+	"\x12ListOrdersResponse\x12.\n" +
+	"\x06orders\x18\x01 \x03(\v2\x16.bananas.v1.SalesOrderR\x06orders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x10\n" +
+	"\x03orm\x18\x03 \x01(\tR\x03orm\x12\x1c\n" +
+	"\tframework\x18\x04 \x01(\tR\tframework\x12\x17\n" +
+	"\adb_time\x18\x05 \x01(\x03R\x06dbTime\"=\n" +
+	"\x13StreamOrdersRequest\x12\x10\n" +
+	"\x03orm\x18\x01 \x01(\tR\x03orm\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit2\xa1\x03\n" +
+	"\x0eBananasService\x12M\n" +
+	"\rSimpleRequest\x12 .bananas.v1.SimpleRequestRequest\x1a\x1a.bananas.v1.SimpleResponse\x12I\n" +
+	"\fJsonResponse\x12\x1f.bananas.v1.JsonResponseRequest\x1a\x18.bananas.v1.JsonResponse\x12W\n" +
+	"\x0fGetRecentOrders\x12\".bananas.v1.GetRecentOrdersRequest\x1a .bananas.v1.RecentOrdersResponse\x12K\n" +
+	"\n" +
+	"ListOrders\x12\x1d.bananas.v1.ListOrdersRequest\x1a\x1e.bananas.v1.ListOrdersResponse\x12O\n" +
+	"\fStreamOrders\x12\x1f.bananas.v1.StreamOrdersRequest\x1a\x1c.bananas.v1.OrderWithDetails0\x01B\x92\x01\n" +
+	"\x0ecom.bananas.v1B\fServiceProtoP\x01Z)bananas/internal/gen/bananas/v1;bananasv1\xa2\x02\x03BXX\xaa\x02\n" +
+	"Bananas.V1\xca\x02\n" +
+	"Bananas\\V1\xe2\x02\x16Bananas\\V1\\GPBMetadata\xea\x02\vBananas::V1b\x06proto3"
+
+var (
+	file_bananas_v1_service_proto_rawDescOnce sync.Once
+	file_bananas_v1_service_proto_rawDescData []byte
+)
+
+func file_bananas_v1_service_proto_rawDescGZIP() []byte {
+	file_bananas_v1_service_proto_rawDescOnce.Do(func() {
+		file_bananas_v1_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bananas_v1_service_proto_rawDesc), len(file_bananas_v1_service_proto_rawDesc)))
+	})
+	return file_bananas_v1_service_proto_rawDescData
+}
+
+var file_bananas_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_bananas_v1_service_proto_goTypes = []any{
+	(*SimpleRequestRequest)(nil),   // 0: bananas.v1.SimpleRequestRequest
+	(*JsonResponseRequest)(nil),    // 1: bananas.v1.JsonResponseRequest
+	(*GetRecentOrdersRequest)(nil), // 2: bananas.v1.GetRecentOrdersRequest
+	(*ListOrdersRequest)(nil),      // 3: bananas.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),     // 4: bananas.v1.ListOrdersResponse
+	(*StreamOrdersRequest)(nil),    // 5: bananas.v1.StreamOrdersRequest
+	(*SalesOrder)(nil),             // 6: bananas.v1.SalesOrder
+	(*SimpleResponse)(nil),         // 7: bananas.v1.SimpleResponse
+	(*JsonResponse)(nil),           // 8: bananas.v1.JsonResponse
+	(*RecentOrdersResponse)(nil),   // 9: bananas.v1.RecentOrdersResponse
+	(*OrderWithDetails)(nil),       // 10: bananas.v1.OrderWithDetails
+}
+var file_bananas_v1_service_proto_depIdxs = []int32{
+	6,  // 0: bananas.v1.ListOrdersResponse.orders:type_name -> bananas.v1.SalesOrder
+	0,  // 1: bananas.v1.BananasService.SimpleRequest:input_type -> bananas.v1.SimpleRequestRequest
+	1,  // 2: bananas.v1.BananasService.JsonResponse:input_type -> bananas.v1.JsonResponseRequest
+	2,  // 3: bananas.v1.BananasService.GetRecentOrders:input_type -> bananas.v1.GetRecentOrdersRequest
+	3,  // 4: bananas.v1.BananasService.ListOrders:input_type -> bananas.v1.ListOrdersRequest
+	5,  // 5: bananas.v1.BananasService.StreamOrders:input_type -> bananas.v1.StreamOrdersRequest
+	7,  // 6: bananas.v1.BananasService.SimpleRequest:output_type -> bananas.v1.SimpleResponse
+	8,  // 7: bananas.v1.BananasService.JsonResponse:output_type -> bananas.v1.JsonResponse
+	9,  // 8: bananas.v1.BananasService.GetRecentOrders:output_type -> bananas.v1.RecentOrdersResponse
+	4,  // 9: bananas.v1.BananasService.ListOrders:output_type -> bananas.v1.ListOrdersResponse
+	10, // 10: bananas.v1.BananasService.StreamOrders:output_type -> bananas.v1.OrderWithDetails
+	6,  // [6:11] is the sub-list for method output_type
+	1,  // [1:6] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_bananas_v1_service_proto_init() }
+func file_bananas_v1_service_proto_init() {
+	if File_bananas_v1_service_proto != nil {
+		return
+	}
+	file_bananas_v1_api_proto_init()
+	file_bananas_v1_models_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bananas_v1_service_proto_rawDesc), len(file_bananas_v1_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bananas_v1_service_proto_goTypes,
+		DependencyIndexes: file_bananas_v1_service_proto_depIdxs,
+		MessageInfos:      file_bananas_v1_service_proto_msgTypes,
+	}.Build()
+	File_bananas_v1_service_proto = out.File
+	file_bananas_v1_service_proto_goTypes = nil
+	file_bananas_v1_service_proto_depIdxs = nil
+}
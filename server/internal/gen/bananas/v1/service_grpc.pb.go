@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: bananas/v1/service.proto
+
+package bananasv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BananasService_SimpleRequest_FullMethodName   = "/bananas.v1.BananasService/SimpleRequest"
+	BananasService_JsonResponse_FullMethodName    = "/bananas.v1.BananasService/JsonResponse"
+	BananasService_GetRecentOrders_FullMethodName = "/bananas.v1.BananasService/GetRecentOrders"
+	BananasService_ListOrders_FullMethodName      = "/bananas.v1.BananasService/ListOrders"
+	BananasService_StreamOrders_FullMethodName    = "/bananas.v1.BananasService/StreamOrders"
+)
+
+// BananasServiceClient is the client API for BananasService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BananasService exposes the shared order and test operations over gRPC and
+// Connect. Unary calls return the same envelopes as the HTTP endpoints so RPC
+// and REST results are comparable on identical data.
+type BananasServiceClient interface {
+	// Mirrors GET /api/test/simple.
+	SimpleRequest(ctx context.Context, in *SimpleRequestRequest, opts ...grpc.CallOption) (*SimpleResponse, error)
+	// Mirrors GET /api/test/json.
+	JsonResponse(ctx context.Context, in *JsonResponseRequest, opts ...grpc.CallOption) (*JsonResponse, error)
+	// Mirrors GET /api/orders/recent.
+	GetRecentOrders(ctx context.Context, in *GetRecentOrdersRequest, opts ...grpc.CallOption) (*RecentOrdersResponse, error)
+	// Pages through sales orders, newest first, without their details.
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	// Sends the most recent orders one message at a time as they are read
+	// from a database cursor, without their customer or items.
+	StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderWithDetails], error)
+}
+
+type bananasServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBananasServiceClient(cc grpc.ClientConnInterface) BananasServiceClient {
+	return &bananasServiceClient{cc}
+}
+
+func (c *bananasServiceClient) SimpleRequest(ctx context.Context, in *SimpleRequestRequest, opts ...grpc.CallOption) (*SimpleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimpleResponse)
+	err := c.cc.Invoke(ctx, BananasService_SimpleRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bananasServiceClient) JsonResponse(ctx context.Context, in *JsonResponseRequest, opts ...grpc.CallOption) (*JsonResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JsonResponse)
+	err := c.cc.Invoke(ctx, BananasService_JsonResponse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bananasServiceClient) GetRecentOrders(ctx context.Context, in *GetRecentOrdersRequest, opts ...grpc.CallOption) (*RecentOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecentOrdersResponse)
+	err := c.cc.Invoke(ctx, BananasService_GetRecentOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bananasServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, BananasService_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bananasServiceClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderWithDetails], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BananasService_ServiceDesc.Streams[0], BananasService_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrdersRequest, OrderWithDetails]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BananasService_StreamOrdersClient = grpc.ServerStreamingClient[OrderWithDetails]
+
+// BananasServiceServer is the server API for BananasService service.
+// All implementations must embed UnimplementedBananasServiceServer
+// for forward compatibility.
+//
+// BananasService exposes the shared order and test operations over gRPC and
+// Connect. Unary calls return the same envelopes as the HTTP endpoints so RPC
+// and REST results are comparable on identical data.
+type BananasServiceServer interface {
+	// Mirrors GET /api/test/simple.
+	SimpleRequest(context.Context, *SimpleRequestRequest) (*SimpleResponse, error)
+	// Mirrors GET /api/test/json.
+	JsonResponse(context.Context, *JsonResponseRequest) (*JsonResponse, error)
+	// Mirrors GET /api/orders/recent.
+	GetRecentOrders(context.Context, *GetRecentOrdersRequest) (*RecentOrdersResponse, error)
+	// Pages through sales orders, newest first, without their details.
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	// Sends the most recent orders one message at a time as they are read
+	// from a database cursor, without their customer or items.
+	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderWithDetails]) error
+	mustEmbedUnimplementedBananasServiceServer()
+}
+
+// UnimplementedBananasServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBananasServiceServer struct{}
+
+func (UnimplementedBananasServiceServer) SimpleRequest(context.Context, *SimpleRequestRequest) (*SimpleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SimpleRequest not implemented")
+}
+func (UnimplementedBananasServiceServer) JsonResponse(context.Context, *JsonResponseRequest) (*JsonResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method JsonResponse not implemented")
+}
+func (UnimplementedBananasServiceServer) GetRecentOrders(context.Context, *GetRecentOrdersRequest) (*RecentOrdersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRecentOrders not implemented")
+}
+func (UnimplementedBananasServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedBananasServiceServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderWithDetails]) error {
+	return status.Error(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedBananasServiceServer) mustEmbedUnimplementedBananasServiceServer() {}
+func (UnimplementedBananasServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeBananasServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BananasServiceServer will
+// result in compilation errors.
+type UnsafeBananasServiceServer interface {
+	mustEmbedUnimplementedBananasServiceServer()
+}
+
+func RegisterBananasServiceServer(s grpc.ServiceRegistrar, srv BananasServiceServer) {
+	// If the following call panics, it indicates UnimplementedBananasServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BananasService_ServiceDesc, srv)
+}
+
+func _BananasService_SimpleRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimpleRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BananasServiceServer).SimpleRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BananasService_SimpleRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BananasServiceServer).SimpleRequest(ctx, req.(*SimpleRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BananasService_JsonResponse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JsonResponseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BananasServiceServer).JsonResponse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BananasService_JsonResponse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BananasServiceServer).JsonResponse(ctx, req.(*JsonResponseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BananasService_GetRecentOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecentOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BananasServiceServer).GetRecentOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BananasService_GetRecentOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BananasServiceServer).GetRecentOrders(ctx, req.(*GetRecentOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BananasService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BananasServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BananasService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BananasServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BananasService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BananasServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, OrderWithDetails]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BananasService_StreamOrdersServer = grpc.ServerStreamingServer[OrderWithDetails]
+
+// BananasService_ServiceDesc is the grpc.ServiceDesc for BananasService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BananasService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bananas.v1.BananasService",
+	HandlerType: (*BananasServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SimpleRequest",
+			Handler:    _BananasService_SimpleRequest_Handler,
+		},
+		{
+			MethodName: "JsonResponse",
+			Handler:    _BananasService_JsonResponse_Handler,
+		},
+		{
+			MethodName: "GetRecentOrders",
+			Handler:    _BananasService_GetRecentOrders_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _BananasService_ListOrders_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamOrders",
+			Handler:       _BananasService_StreamOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bananas/v1/service.proto",
+}
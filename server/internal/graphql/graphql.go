@@ -0,0 +1,78 @@
+// Package graphql serves the inventory and sales domain over GraphQL. Nested
+// fields resolve through per-request dataloaders, so a query such as
+// orders { customer items { product } } costs one query per level rather
+// than one per order, which makes it comparable to /api/orders/recent.
+package graphql
+
+import (
+	"bananas/internal/services"
+	"context"
+	_ "embed"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxParallelism bounds the resolvers running at once. A resolver keeps its
+// slot while it waits on a loader, so this is also the largest batch a
+// loader can see; it matches maxOrders so one level of a full page of orders
+// fits in a single batch.
+const maxParallelism = maxOrders
+
+// maxDepth stops queries from nesting far past anything the schema needs,
+// such as long chains of category parents.
+const maxDepth = 12
+
+// Request is a GraphQL request, as posted in JSON or sent as GET parameters.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Executor runs GraphQL requests against the service.
+type Executor struct {
+	schema  *graphqlgo.Schema
+	service *services.Service
+}
+
+// New parses the schema, which is fixed, so a failure is a programming error
+// and panics.
+func New(service *services.Service) *Executor {
+	return &Executor{
+		schema: graphqlgo.MustParseSchema(schema, &resolver{service: service},
+			graphqlgo.MaxParallelism(maxParallelism),
+			graphqlgo.MaxDepth(maxDepth),
+		),
+		service: service,
+	}
+}
+
+// Execute runs req with every database read going through orm. With batched
+// false the loaders neither batch nor cache, so each nested field costs its
+// own query, giving the N+1 baseline to compare against. The response
+// carries the ORM and query stats in its extensions, and the stats are
+// returned too for timing headers.
+func (e *Executor) Execute(ctx context.Context, orm string, batched bool, req Request) (*graphqlgo.Response, Stats) {
+	l := newLoaders(e.service, orm, batched)
+	ctx = context.WithValue(ctx, loadersKey{}, l)
+
+	response := e.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	stats := l.stats.snapshot()
+	if response.Extensions == nil {
+		response.Extensions = make(map[string]any)
+	}
+	response.Extensions["orm"] = orm
+	response.Extensions["batched"] = batched
+	response.Extensions["stats"] = stats
+	return response, stats
+}
+
+type loadersKey struct{}
+
+// loadersFrom returns the loaders Execute put in ctx.
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,207 @@
+package graphql
+
+import (
+	"bananas/internal/models"
+	"bananas/internal/services"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// batchWait is how long a loader collects keys before querying. Each level
+// of a query waits it out once, so it trades a little latency for whole
+// levels landing in one batch; on a busy or single core host, resolvers for
+// a few hundred parents take several milliseconds just to start.
+const batchWait = 10 * time.Millisecond
+
+// loaders batch the nested lookups of one request, so resolving a field
+// across a list of parents costs one query rather than one per parent. They
+// are built per request, which keeps both the ORM choice and the cache from
+// outliving it.
+type loaders struct {
+	orm               string
+	customers         *dataloader.Loader[uuid.UUID, *models.Customer]
+	orderItems        *dataloader.Loader[uuid.UUID, []*models.SalesOrderItem]
+	products          *dataloader.Loader[uuid.UUID, *models.Product]
+	productCategories *dataloader.Loader[uuid.UUID, []*models.ProductCategory]
+	categories        *dataloader.Loader[uuid.UUID, *models.Category]
+	inventory         *dataloader.Loader[uuid.UUID, []*models.Inventory]
+	supplierProducts  *dataloader.Loader[uuid.UUID, []*models.SupplierProduct]
+	suppliers         *dataloader.Loader[uuid.UUID, *models.Supplier]
+	stats             *statsRecorder
+}
+
+// loaderOptions are shared by every loader of a request.
+type loaderOptions struct {
+	orm     string
+	batched bool
+	stats   *statsRecorder
+}
+
+func newLoaders(service *services.Service, orm string, batched bool) *loaders {
+	stats := &statsRecorder{stats: Stats{Loaders: make(map[string]LoaderStats)}}
+	opts := loaderOptions{orm: orm, batched: batched, stats: stats}
+	return &loaders{
+		orm: orm,
+		customers: byID(opts, "customers", service.GetCustomersByIDs,
+			func(c *models.Customer) uuid.UUID { return c.ID }),
+		orderItems: groupedBy(opts, "orderItems", service.GetOrderItemsByOrderIDs,
+			func(item *models.SalesOrderItem) uuid.UUID { return item.SalesOrderID }),
+		products: byID(opts, "products", service.GetProductsByIDs,
+			func(p *models.Product) uuid.UUID { return p.ID }),
+		productCategories: groupedBy(opts, "productCategories", service.GetProductCategoriesByProductIDs,
+			func(pc *models.ProductCategory) uuid.UUID { return pc.ProductID }),
+		categories: byID(opts, "categories", service.GetCategoriesByIDs,
+			func(c *models.Category) uuid.UUID { return c.ID }),
+		inventory: groupedBy(opts, "inventory", service.GetInventoryByProductIDs,
+			func(inv *models.Inventory) uuid.UUID { return inv.ProductID }),
+		supplierProducts: groupedBy(opts, "supplierProducts", service.GetSupplierProductsByProductIDs,
+			func(sp *models.SupplierProduct) uuid.UUID { return sp.ProductID }),
+		suppliers: byID(opts, "suppliers", service.GetSuppliersByIDs,
+			func(s *models.Supplier) uuid.UUID { return s.ID }),
+		stats: stats,
+	}
+}
+
+// batchLoad is a service batch load, such as Service.GetProductsByIDs.
+type batchLoad[V any] func(ctx context.Context, orm string, keys []uuid.UUID) ([]*V, error)
+
+// byID builds a loader for rows looked up by their own ID. A key with no row
+// loads as nil.
+func byID[V any](opts loaderOptions, name string, load batchLoad[V], id func(*V) uuid.UUID) *dataloader.Loader[uuid.UUID, *V] {
+	return newLoader(opts, name, func(ctx context.Context, keys []uuid.UUID) []*dataloader.Result[*V] {
+		rows, err := load(ctx, opts.orm, keys)
+		results := make([]*dataloader.Result[*V], len(keys))
+		if err != nil {
+			return failed(results, err)
+		}
+
+		found := make(map[uuid.UUID]*V, len(rows))
+		for _, row := range rows {
+			found[id(row)] = row
+		}
+		for i, key := range keys {
+			results[i] = &dataloader.Result[*V]{Data: found[key]}
+		}
+		return results
+	})
+}
+
+// groupedBy builds a loader for the rows that reference each key, such as
+// the items of an order. A key with no rows loads as an empty list, and rows
+// keep the order the query returned them in.
+func groupedBy[V any](opts loaderOptions, name string, load batchLoad[V], key func(*V) uuid.UUID) *dataloader.Loader[uuid.UUID, []*V] {
+	return newLoader(opts, name, func(ctx context.Context, keys []uuid.UUID) []*dataloader.Result[[]*V] {
+		rows, err := load(ctx, opts.orm, keys)
+		results := make([]*dataloader.Result[[]*V], len(keys))
+		if err != nil {
+			return failed(results, err)
+		}
+
+		groups := make(map[uuid.UUID][]*V, len(keys))
+		for _, row := range rows {
+			groups[key(row)] = append(groups[key(row)], row)
+		}
+		for i, k := range keys {
+			group := groups[k]
+			if group == nil {
+				group = []*V{}
+			}
+			results[i] = &dataloader.Result[[]*V]{Data: group}
+		}
+		return results
+	})
+}
+
+// newLoader wraps batch so every call is counted in the request's stats.
+// Unbatched loaders send each key off alone and uncached, as resolvers
+// querying for themselves would.
+func newLoader[V any](opts loaderOptions, name string, batch dataloader.BatchFunc[uuid.UUID, V]) *dataloader.Loader[uuid.UUID, V] {
+	counted := func(ctx context.Context, keys []uuid.UUID) []*dataloader.Result[V] {
+		start := time.Now()
+		results := batch(ctx, keys)
+		opts.stats.record(name, len(keys), time.Since(start))
+		return results
+	}
+	if !opts.batched {
+		return dataloader.NewBatchedLoader(counted,
+			dataloader.WithBatchCapacity[uuid.UUID, V](1),
+			dataloader.WithCache[uuid.UUID, V](&dataloader.NoCache[uuid.UUID, V]{}),
+		)
+	}
+	return dataloader.NewBatchedLoader(counted, dataloader.WithWait[uuid.UUID, V](batchWait))
+}
+
+// failed fails every result with err.
+func failed[V any](results []*dataloader.Result[V], err error) []*dataloader.Result[V] {
+	for i := range results {
+		results[i] = &dataloader.Result[V]{Error: err}
+	}
+	return results
+}
+
+// Stats counts the queries a request made. It goes out in the response's
+// extensions, where it shows how many round trips a nested query cost.
+type Stats struct {
+	Queries int                    `json:"queries"`
+	DBTime  float64                `json:"dbTimeMs"`
+	Loaders map[string]LoaderStats `json:"loaders"`
+}
+
+// LoaderStats counts the batches one loader ran and the keys they covered.
+type LoaderStats struct {
+	Batches int `json:"batches"`
+	Keys    int `json:"keys"`
+}
+
+// statsRecorder collects Stats from resolvers running concurrently.
+type statsRecorder struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// recordQuery counts a query made outside the loaders, such as a root
+// field's.
+func (s *statsRecorder) recordQuery(elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Queries++
+	s.stats.DBTime += milliseconds(elapsed)
+}
+
+// record counts one loader batch.
+func (s *statsRecorder) record(name string, keys int, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Queries++
+	s.stats.DBTime += milliseconds(elapsed)
+	loader := s.stats.Loaders[name]
+	loader.Batches++
+	loader.Keys += keys
+	s.stats.Loaders[name] = loader
+}
+
+// snapshot copies the counts, once resolvers are done.
+func (s *statsRecorder) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Loaders = make(map[string]LoaderStats, len(s.stats.Loaders))
+	for name, loader := range s.stats.Loaders {
+		stats.Loaders[name] = loader
+	}
+	return stats
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// notFound reports a non-null reference whose row is missing.
+func notFound(kind string, id uuid.UUID) error {
+	return fmt.Errorf("%s %s not found", kind, id)
+}
@@ -0,0 +1,323 @@
+package graphql
+
+import (
+	"bananas/internal/models"
+	"bananas/internal/services"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// maxOrders matches the cap /api/orders/recent puts on its limit.
+const maxOrders = 1000
+
+// resolver is the root of the schema. Everything below it reads through the
+// request's loaders.
+type resolver struct {
+	service *services.Service
+}
+
+type ordersArgs struct {
+	Limit  int32
+	Offset int32
+	Status *string
+}
+
+func (r *resolver) Orders(ctx context.Context, args ordersArgs) ([]*orderResolver, error) {
+	if args.Limit < 1 || args.Limit > maxOrders {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxOrders)
+	}
+	if args.Offset < 0 {
+		return nil, errors.New("offset must not be negative")
+	}
+	status := ""
+	if args.Status != nil {
+		status = *args.Status
+	}
+
+	l := loadersFrom(ctx)
+	start := time.Now()
+	orders, _, err := r.service.ListOrders(ctx, l.orm, status, int(args.Limit), int(args.Offset))
+	l.stats.recordQuery(time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*orderResolver, len(orders))
+	for i, order := range orders {
+		resolvers[i] = &orderResolver{order}
+	}
+	return resolvers, nil
+}
+
+func (r *resolver) Customer(ctx context.Context, args struct{ ID graphqlgo.ID }) (*customerResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := loadersFrom(ctx).customers.Load(ctx, id)()
+	if err != nil || customer == nil {
+		return nil, err
+	}
+	return &customerResolver{customer}, nil
+}
+
+func (r *resolver) Product(ctx context.Context, args struct{ ID graphqlgo.ID }) (*productResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := loadersFrom(ctx).products.Load(ctx, id)()
+	if err != nil || product == nil {
+		return nil, err
+	}
+	return &productResolver{product}, nil
+}
+
+// Products returns a product, or null, for each of ids, in the same order.
+func (r *resolver) Products(ctx context.Context, args struct{ IDs []graphqlgo.ID }) ([]*productResolver, error) {
+	ids := make([]uuid.UUID, len(args.IDs))
+	for i, raw := range args.IDs {
+		id, err := parseID(raw)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	products, errs := loadersFrom(ctx).products.LoadMany(ctx, ids)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	resolvers := make([]*productResolver, len(products))
+	for i, product := range products {
+		if product != nil {
+			resolvers[i] = &productResolver{product}
+		}
+	}
+	return resolvers, nil
+}
+
+type orderResolver struct {
+	order *models.SalesOrder
+}
+
+func (r *orderResolver) ID() graphqlgo.ID          { return graphqlgo.ID(r.order.ID.String()) }
+func (r *orderResolver) OrderNumber() string       { return r.order.OrderNumber }
+func (r *orderResolver) OrderDate() graphqlgo.Time { return graphqlgo.Time{Time: r.order.OrderDate} }
+func (r *orderResolver) Status() string            { return r.order.Status }
+func (r *orderResolver) Subtotal() float64         { return r.order.Subtotal }
+func (r *orderResolver) Tax() float64              { return r.order.Tax }
+func (r *orderResolver) Shipping() float64         { return r.order.Shipping }
+func (r *orderResolver) Total() float64            { return r.order.Total }
+func (r *orderResolver) Notes() *string            { return r.order.Notes }
+
+func (r *orderResolver) Customer(ctx context.Context) (*customerResolver, error) {
+	customer, err := loadersFrom(ctx).customers.Load(ctx, r.order.CustomerID)()
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, notFound("customer", r.order.CustomerID)
+	}
+	return &customerResolver{customer}, nil
+}
+
+func (r *orderResolver) Items(ctx context.Context) ([]*orderItemResolver, error) {
+	items, err := loadersFrom(ctx).orderItems.Load(ctx, r.order.ID)()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*orderItemResolver, len(items))
+	for i, item := range items {
+		resolvers[i] = &orderItemResolver{item}
+	}
+	return resolvers, nil
+}
+
+type orderItemResolver struct {
+	item *models.SalesOrderItem
+}
+
+func (r *orderItemResolver) ID() graphqlgo.ID   { return graphqlgo.ID(r.item.ID.String()) }
+func (r *orderItemResolver) Quantity() int32    { return int32(r.item.Quantity) }
+func (r *orderItemResolver) UnitPrice() float64 { return r.item.UnitPrice }
+func (r *orderItemResolver) Discount() float64  { return r.item.Discount }
+func (r *orderItemResolver) Tax() float64       { return r.item.Tax }
+func (r *orderItemResolver) Total() float64     { return r.item.Total }
+
+func (r *orderItemResolver) Product(ctx context.Context) (*productResolver, error) {
+	product, err := loadersFrom(ctx).products.Load(ctx, r.item.ProductID)()
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, notFound("product", r.item.ProductID)
+	}
+	return &productResolver{product}, nil
+}
+
+type customerResolver struct {
+	customer *models.Customer
+}
+
+func (r *customerResolver) ID() graphqlgo.ID  { return graphqlgo.ID(r.customer.ID.String()) }
+func (r *customerResolver) FirstName() string { return r.customer.FirstName }
+func (r *customerResolver) LastName() string  { return r.customer.LastName }
+func (r *customerResolver) Email() string     { return r.customer.Email }
+func (r *customerResolver) Phone() *string    { return r.customer.Phone }
+
+type productResolver struct {
+	product *models.Product
+}
+
+func (r *productResolver) ID() graphqlgo.ID     { return graphqlgo.ID(r.product.ID.String()) }
+func (r *productResolver) Sku() string          { return r.product.SKU }
+func (r *productResolver) Name() string         { return r.product.Name }
+func (r *productResolver) Description() *string { return r.product.Description }
+func (r *productResolver) Weight() *float64     { return r.product.Weight }
+func (r *productResolver) Dimensions() *string  { return r.product.Dimensions }
+func (r *productResolver) IsActive() bool       { return r.product.IsActive }
+
+// Categories goes through the product_categories link table, so it costs
+// two batches: the links, then the categories they name.
+func (r *productResolver) Categories(ctx context.Context) ([]*categoryResolver, error) {
+	l := loadersFrom(ctx)
+	links, err := l.productCategories.Load(ctx, r.product.ID)()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(links))
+	for i, link := range links {
+		ids[i] = link.CategoryID
+	}
+	categories, errs := l.categories.LoadMany(ctx, ids)()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*categoryResolver, 0, len(categories))
+	for _, category := range categories {
+		if category != nil {
+			resolvers = append(resolvers, &categoryResolver{category})
+		}
+	}
+	return resolvers, nil
+}
+
+func (r *productResolver) Inventory(ctx context.Context) ([]*inventoryResolver, error) {
+	inventory, err := loadersFrom(ctx).inventory.Load(ctx, r.product.ID)()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*inventoryResolver, len(inventory))
+	for i, inv := range inventory {
+		resolvers[i] = &inventoryResolver{inv}
+	}
+	return resolvers, nil
+}
+
+func (r *productResolver) Suppliers(ctx context.Context) ([]*productSupplierResolver, error) {
+	links, err := loadersFrom(ctx).supplierProducts.Load(ctx, r.product.ID)()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*productSupplierResolver, len(links))
+	for i, link := range links {
+		resolvers[i] = &productSupplierResolver{link}
+	}
+	return resolvers, nil
+}
+
+type categoryResolver struct {
+	category *models.Category
+}
+
+func (r *categoryResolver) ID() graphqlgo.ID     { return graphqlgo.ID(r.category.ID.String()) }
+func (r *categoryResolver) Name() string         { return r.category.Name }
+func (r *categoryResolver) Description() *string { return r.category.Description }
+
+func (r *categoryResolver) Parent(ctx context.Context) (*categoryResolver, error) {
+	if r.category.ParentID == nil {
+		return nil, nil
+	}
+	parent, err := loadersFrom(ctx).categories.Load(ctx, *r.category.ParentID)()
+	if err != nil || parent == nil {
+		return nil, err
+	}
+	return &categoryResolver{parent}, nil
+}
+
+type inventoryResolver struct {
+	inventory *models.Inventory
+}
+
+func (r *inventoryResolver) ID() graphqlgo.ID { return graphqlgo.ID(r.inventory.ID.String()) }
+func (r *inventoryResolver) WarehouseId() graphqlgo.ID {
+	return graphqlgo.ID(r.inventory.WarehouseID.String())
+}
+func (r *inventoryResolver) Quantity() int32         { return int32(r.inventory.Quantity) }
+func (r *inventoryResolver) ReservedQuantity() int32 { return int32(r.inventory.ReservedQuantity) }
+func (r *inventoryResolver) ReorderPoint() int32     { return int32(r.inventory.ReorderPoint) }
+func (r *inventoryResolver) ReorderQuantity() int32  { return int32(r.inventory.ReorderQuantity) }
+
+// Available is the stock not already reserved for open orders.
+func (r *inventoryResolver) Available() int32 {
+	return int32(r.inventory.Quantity - r.inventory.ReservedQuantity)
+}
+
+type productSupplierResolver struct {
+	link *models.SupplierProduct
+}
+
+func (r *productSupplierResolver) SupplierSku() *string { return r.link.SupplierSKU }
+func (r *productSupplierResolver) Cost() *float64       { return r.link.Cost }
+func (r *productSupplierResolver) Currency() string     { return r.link.Currency }
+func (r *productSupplierResolver) LeadTimeDays() *int32 { return optionalInt32(r.link.LeadTimeDays) }
+func (r *productSupplierResolver) MinimumOrderQuantity() *int32 {
+	return optionalInt32(r.link.MinimumOrderQuantity)
+}
+
+func (r *productSupplierResolver) Supplier(ctx context.Context) (*supplierResolver, error) {
+	supplier, err := loadersFrom(ctx).suppliers.Load(ctx, r.link.SupplierID)()
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, notFound("supplier", r.link.SupplierID)
+	}
+	return &supplierResolver{supplier}, nil
+}
+
+type supplierResolver struct {
+	supplier *models.Supplier
+}
+
+func (r *supplierResolver) ID() graphqlgo.ID     { return graphqlgo.ID(r.supplier.ID.String()) }
+func (r *supplierResolver) Name() string         { return r.supplier.Name }
+func (r *supplierResolver) ContactName() *string { return r.supplier.ContactName }
+func (r *supplierResolver) Email() *string       { return r.supplier.Email }
+func (r *supplierResolver) Phone() *string       { return r.supplier.Phone }
+func (r *supplierResolver) City() *string        { return r.supplier.City }
+func (r *supplierResolver) Country() *string     { return r.supplier.Country }
+
+func parseID(id graphqlgo.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid id %q", id)
+	}
+	return parsed, nil
+}
+
+func optionalInt32(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
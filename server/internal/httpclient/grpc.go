@@ -0,0 +1,35 @@
+package httpclient
+
+import (
+	"bananas/internal/certs"
+	"bananas/internal/config"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GRPC dials a gRPC server at addr, which only speaks HTTP/2: h2 over TLS or
+// h2c in the clear. opts.Socket dials the Unix socket instead of addr.
+func GRPC(addr string, opts Options) (*grpc.ClientConn, error) {
+	var creds credentials.TransportCredentials
+	switch opts.Protocol {
+	case config.ProtocolH2:
+		clientTLS, err := certs.ClientConfig(opts.TLS)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(clientTLS)
+	case config.ProtocolH2C:
+		creds = insecure.NewCredentials()
+	default:
+		return nil, fmt.Errorf("gRPC cannot be used over %s", opts.Protocol)
+	}
+
+	target := "passthrough:///" + addr
+	if opts.Socket != "" {
+		target = "unix://" + opts.Socket
+	}
+	return grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+}
@@ -0,0 +1,83 @@
+// Package httpclient builds HTTP clients that speak each listener protocol
+// the framework servers can be configured with.
+package httpclient
+
+import (
+	"bananas/internal/certs"
+	"bananas/internal/config"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// Options describes how a client reaches a framework server.
+type Options struct {
+	Protocol string
+	TLS      config.TLSConfig
+	// Socket, when set, dials this Unix domain socket instead of TCP.
+	Socket string
+	// MaxConns bounds idle connections per host for the TCP transports;
+	// HTTP/2 and HTTP/3 multiplex requests over as few connections as they can.
+	MaxConns int
+	Timeout  time.Duration
+}
+
+// New returns a client that talks to servers listening as opts describes.
+func New(opts Options) (*http.Client, error) {
+	var clientTLS *tls.Config
+	switch opts.Protocol {
+	case config.ProtocolHTTPS, config.ProtocolH2, config.ProtocolH3:
+		var err error
+		clientTLS, err = certs.ClientConfig(opts.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Protocol == config.ProtocolH3 {
+		if opts.Socket != "" {
+			return nil, fmt.Errorf("h3 cannot be used over a unix socket")
+		}
+		return &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: &http3.Transport{TLSClientConfig: clientTLS},
+		}, nil
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        opts.MaxConns,
+		MaxIdleConnsPerHost: opts.MaxConns,
+		TLSClientConfig:     clientTLS,
+		Protocols:           new(http.Protocols),
+	}
+
+	if opts.Socket != "" {
+		var dialer net.Dialer
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", opts.Socket)
+		}
+	}
+
+	switch opts.Protocol {
+	case config.ProtocolHTTP1, config.ProtocolHTTPS:
+		transport.Protocols.SetHTTP1(true)
+	case config.ProtocolH2:
+		transport.Protocols.SetHTTP2(true)
+	case config.ProtocolH2C:
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	default:
+		return nil, fmt.Errorf("unknown protocol: %s", opts.Protocol)
+	}
+
+	return &http.Client{Timeout: opts.Timeout, Transport: transport}, nil
+}
+
+// ForFramework returns a TCP client for the framework's configured protocol.
+func ForFramework(fw config.FrameworkConfig, timeout time.Duration) (*http.Client, error) {
+	return New(Options{Protocol: fw.Protocol, TLS: fw.TLS, Timeout: timeout})
+}
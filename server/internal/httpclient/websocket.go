@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"bananas/internal/certs"
+	"bananas/internal/config"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket returns a dialer for servers listening as opts describes. The
+// handshake is an HTTP/1.1 upgrade, so TLS listeners are dialed offering
+// only http/1.1 and h3 is not supported.
+func WebSocket(opts Options) (*websocket.Dialer, error) {
+	dialer := &websocket.Dialer{HandshakeTimeout: opts.Timeout}
+
+	switch opts.Protocol {
+	case config.ProtocolHTTP1, config.ProtocolH2C:
+	case config.ProtocolHTTPS, config.ProtocolH2:
+		clientTLS, err := certs.ClientConfig(opts.TLS)
+		if err != nil {
+			return nil, err
+		}
+		clientTLS.NextProtos = []string{"http/1.1"}
+		dialer.TLSClientConfig = clientTLS
+	default:
+		return nil, fmt.Errorf("WebSocket cannot be used over %s", opts.Protocol)
+	}
+
+	if opts.Socket != "" {
+		var netDialer net.Dialer
+		dialer.NetDialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return netDialer.DialContext(ctx, "unix", opts.Socket)
+		}
+	}
+	return dialer, nil
+}
+
+// WebSocketURL turns an http or https base URL into its ws or wss form.
+func WebSocketURL(baseURL string) string {
+	if rest, ok := strings.CutPrefix(baseURL, "https://"); ok {
+		return "wss://" + rest
+	}
+	return "ws://" + strings.TrimPrefix(baseURL, "http://")
+}
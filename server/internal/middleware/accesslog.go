@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// AccessEntry is a completed request as recorded in the access log.
+type AccessEntry struct {
+	Framework string
+	RequestID string
+	Method    string
+	Path      string
+	Status    int
+	Bytes     int64
+	Latency   time.Duration
+}
+
+// accessLine is the JSON shape of one access log line. Fiber renders the
+// same fields from a logger template, so keep the two in step.
+type accessLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Msg       string `json:"msg"`
+	Framework string `json:"framework"`
+	RequestID string `json:"request_id"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	Bytes     int64  `json:"bytes"`
+	LatencyUS int64  `json:"latency_us"`
+}
+
+// FormatAccess renders entry as a single JSON access log line, including the
+// trailing newline. Frameworks with a native request logger use it as their
+// formatter so every stack emits the same fields.
+func FormatAccess(entry AccessEntry) string {
+	data, _ := json.Marshal(accessLine{
+		Time:      time.Now().Format(time.RFC3339Nano),
+		Level:     "INFO",
+		Msg:       "request",
+		Framework: entry.Framework,
+		RequestID: entry.RequestID,
+		Method:    entry.Method,
+		Path:      entry.Path,
+		Status:    entry.Status,
+		Bytes:     entry.Bytes,
+		LatencyUS: entry.Latency.Microseconds(),
+	})
+	return string(data) + "\n"
+}
+
+// LogAccess writes entry to the access log on stdout.
+func LogAccess(entry AccessEntry) {
+	os.Stdout.WriteString(FormatAccess(entry))
+}
+
+// AccessLog records every request handled by next. It expects RequestID to
+// run before it.
+func AccessLog(framework string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			LogAccess(AccessEntry{
+				Framework: framework,
+				RequestID: w.Header().Get(RequestIDHeader),
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    rec.status,
+				Bytes:     rec.bytes,
+				Latency:   time.Since(start),
+			})
+		})
+	}
+}
+
+// statusRecorder captures the status and body size written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection over for a WebSocket upgrade, which is logged
+// with the 101 status the handler writes to the raw connection.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil {
+		r.status = http.StatusSwitchingProtocols
+		r.wroteHeader = true
+	}
+	return conn, rw, err
+}
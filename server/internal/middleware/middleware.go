@@ -0,0 +1,234 @@
+// Package middleware holds the net/http middleware used by frameworks that
+// have no native equivalent, so every framework can run the same stack.
+package middleware
+
+import (
+	"bananas/internal/codec"
+	"bananas/internal/config"
+	"bananas/internal/gen/bananas/v1/bananasv1connect"
+	"bananas/internal/logger"
+	"bananas/internal/problem"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Middleware is the standard net/http middleware shape.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middlewares so the first one listed is the outermost.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Stack returns the configured middleware stack for frameworks built on
+// plain net/http routers, outermost first.
+func Stack(framework string, cfg config.MiddlewareConfig) []Middleware {
+	var stack []Middleware
+	if cfg.Production() {
+		stack = append(stack, RequestID, AccessLog(framework))
+	}
+	if cfg.Minimal() {
+		stack = append(stack, Recover, CORS)
+	}
+	if cfg.Production() {
+		stack = append(stack,
+			Compress,
+			SkipLongLived(Timeout(cfg.RequestTimeout)),
+			SkipFileTransfers(MaxBytes(cfg.MaxBodyBytes)),
+		)
+	}
+	return stack
+}
+
+// IsWebSocketUpgrade reports whether r is a WebSocket handshake.
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		headerContainsToken(r.Header, "Connection", "upgrade")
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsLongLived reports whether r opens a WebSocket, a streamed response, a
+// server-streaming RPC or a file transfer, any of which lasts as long as the
+// client or the data does.
+func IsLongLived(r *http.Request) bool {
+	return IsWebSocketUpgrade(r) || IsStream(r.URL.Path, r.Header.Get("Accept")) ||
+		IsRPCStream(r.URL.Path) || IsFileTransfer(r.URL.Path)
+}
+
+// IsStream reports whether a request for path is answered with a stream:
+// routes ending in /stream always are, whatever Accept asks for, and any
+// route may be when Accept asks for SSE or NDJSON.
+func IsStream(path, accept string) bool {
+	return strings.HasSuffix(path, "/stream") || codec.AcceptsStream(accept)
+}
+
+// SkipLongLived bypasses m for long-lived requests. Request timeouts bound a
+// single response, not a connection or stream that outlasts it.
+func SkipLongLived(m Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		wrapped := m(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if IsLongLived(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsRPCStream reports whether path is a server-streaming BananasService
+// procedure. Connect, gRPC and gRPC-Web all serve it as one response that
+// stays open until the last message.
+func IsRPCStream(path string) bool {
+	return path == bananasv1connect.BananasServiceStreamOrdersProcedure
+}
+
+// FilesPrefix is the path prefix of the file upload and download routes.
+const FilesPrefix = "/api/files/"
+
+// IsFileTransfer reports whether path is a file upload or download route.
+func IsFileTransfer(path string) bool {
+	return strings.HasPrefix(path, FilesPrefix)
+}
+
+// SkipFileTransfers bypasses m for file transfer routes. It wraps body size
+// limits, since uploads are bounded by Config.MaxUploadBytes instead.
+func SkipFileTransfers(m Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		wrapped := m(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if IsFileTransfer(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORS allows any origin to call the API.
+func CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// RequestID reuses the caller's X-Request-ID or generates one, echoes it on
+// the response and stores it on the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// GetRequestID returns the ID stored by RequestID, or "" if there is none.
+func GetRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID stores id for GetRequestID, for servers such as gRPC that
+// carry it outside of net/http.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// NewRequestID returns a random 128-bit hex ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Recover turns a panic into a 500 problem, unless the response had already
+// started, and logs it with the stack.
+func Recover(next http.Handler) http.Handler {
+	log := logger.New("middleware").Function("Recover")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				log.Er("panic serving %s %s: %v\n%s", nil, r.Method, r.URL.Path, v, debug.Stack())
+				if !rec.wroteHeader {
+					problem.Write(w, r, problem.Internal())
+				}
+			}
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// Timeout puts a deadline on the request context and answers with a 504
+// problem if the handler returns after it has passed without having written
+// a response.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				cancel()
+				if ctx.Err() == context.DeadlineExceeded && !rec.wroteHeader {
+					problem.Write(w, r, problem.Timeout(timeout))
+				}
+			}()
+			next.ServeHTTP(rec, r.WithContext(ctx))
+		})
+	}
+}
+
+// MaxBytes caps the request body, rejecting oversized bodies up front when
+// Content-Length is known.
+func MaxBytes(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				problem.Write(w, r, problem.TooLarge(limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
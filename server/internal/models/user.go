@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User is an account for the authentication endpoints. Emails are stored
+// lower case, and the password hash is never sent back to clients.
+type User struct {
+	ID           uuid.UUID `json:"id" db:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Email        string    `json:"email" db:"email" gorm:"type:varchar(255);unique;not null"`
+	PasswordHash string    `json:"-" db:"password_hash" gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" gorm:"type:timestamptz;default:now()"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" gorm:"type:timestamptz;default:now()"`
+}
@@ -0,0 +1,135 @@
+// Package payload generates the bodies used by the file transfer endpoints
+// and benchmarks. Payloads are produced on the fly from a fixed block of
+// pseudo-random bytes, so any size up to MaxSize costs no memory or disk to
+// serve or send, and the same size always yields the same bytes.
+package payload
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Payload size bounds.
+const (
+	MinSize = 1 << 10
+	MaxSize = 1 << 30
+)
+
+// blockSize is the length of the repeating block. Pseudo-random content
+// keeps transfer compression from flattering the results.
+const blockSize = 64 << 10
+
+var block = func() []byte {
+	b := make([]byte, blockSize)
+	// xorshift64, seeded with a constant so payloads are reproducible.
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range b {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+		b[i] = byte(x)
+	}
+	return b
+}()
+
+// units maps size suffixes to multipliers. Sizes are binary, so 1KB is 1024
+// bytes.
+var units = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize parses sizes such as "1KB", "64MB" or "1GB", or a plain byte
+// count, and checks they lie between MinSize and MaxSize.
+func ParseSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, u := range units {
+		if number, ok := strings.CutSuffix(value, u.suffix); ok {
+			value, multiplier = strings.TrimSpace(number), u.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if n > MaxSize/multiplier || n*multiplier < MinSize {
+		return 0, fmt.Errorf("size %q is outside %s to %s", s, FormatSize(MinSize), FormatSize(MaxSize))
+	}
+	return n * multiplier, nil
+}
+
+// FormatSize renders n with the largest unit that divides it evenly.
+func FormatSize(n int64) string {
+	for _, u := range units {
+		if n >= u.multiplier && n%u.multiplier == 0 {
+			return strconv.FormatInt(n/u.multiplier, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10) + "B"
+}
+
+// ETag is the entity tag of the payload of size bytes. Payloads never
+// change, so it depends on the size alone.
+func ETag(size int64) string {
+	return `"payload-` + strconv.FormatInt(size, 10) + `"`
+}
+
+// Reader reads a generated payload. Every byte depends only on its offset,
+// so a range reads the same bytes as the whole payload does there.
+type Reader struct {
+	size   int64
+	offset int64
+}
+
+// NewReader returns a reader over the payload of size bytes.
+func NewReader(size int64) *Reader {
+	return &Reader{size: size}
+}
+
+// NewRangeReader returns a reader over length bytes of the payload starting
+// at offset, for serving a byte range.
+func NewRangeReader(offset, length int64) *Reader {
+	return &Reader{size: offset + length, offset: offset}
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if remaining := r.size - r.offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n := 0
+	for n < len(p) {
+		n += copy(p[n:], block[(r.offset+int64(n))%blockSize:])
+	}
+	r.offset += int64(n)
+	return n, nil
+}
+
+// WriteTo writes the rest of the payload a block at a time, so io.Copy
+// needs no buffer of its own.
+func (r *Reader) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for r.offset < r.size {
+		start := r.offset % blockSize
+		end := min(int64(blockSize), start+r.size-r.offset)
+		n, err := w.Write(block[start:end])
+		written += int64(n)
+		r.offset += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,71 @@
+package payload
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "1KB", want: 1 << 10},
+		{in: "64MB", want: 64 << 20},
+		{in: "1GB", want: 1 << 30},
+		{in: "1024", want: 1024},
+		{in: "2048B", want: 2048},
+		{in: " 10 kb ", want: 10 << 10},
+		{in: "1mb", want: 1 << 20},
+		{in: "1023", wantErr: true},
+		{in: "1023B", wantErr: true},
+		{in: "2GB", wantErr: true},
+		{in: "1073741825", wantErr: true},
+		{in: "1048577KB", wantErr: true},
+		{in: "9223372036854775807GB", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "0KB", wantErr: true},
+		{in: "-1KB", wantErr: true},
+		{in: "1.5MB", wantErr: true},
+		{in: "KB", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "1TB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSize(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseSize(%q) = %d, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSize(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{in: 1 << 10, want: "1KB"},
+		{in: 1536, want: "1536B"},
+		{in: 3 << 20, want: "3MB"},
+		{in: 1 << 30, want: "1GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSize(tt.in); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+		if n, err := ParseSize(FormatSize(tt.in)); err != nil || n != tt.in {
+			t.Errorf("ParseSize(FormatSize(%d)) = %d, %v", tt.in, n, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package problem holds the error body every framework answers with: an RFC
+// 9457 problem details object, sent as application/problem+json whether the
+// error came from a controller, shared middleware or the framework itself,
+// such as an unknown route.
+package problem
+
+import (
+	"bananas/internal/payload"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// MediaType is the content type of a problem details response.
+const MediaType = "application/problem+json"
+
+// requestIDHeader is where the request ID middleware of every framework
+// leaves the ID, on the response and, for Fiber's bridged controllers, on
+// the request.
+const requestIDHeader = "X-Request-ID"
+
+// Problem is a problem details object. Type is always about:blank, so Title
+// is the status text and Detail says what went wrong this time. RequestID
+// and Errors are extension members.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists the broken rules of a request body that failed
+	// validation.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is one broken rule. Field is the JSON path, such as
+// items[2].quantity, and Rule and Param the rule as written in the schema.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// New returns the problem for status, with detail explaining it.
+func New(status int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// At returns p as it occurred on the request to path with the given ID,
+// which is left out when empty.
+func (p Problem) At(path, requestID string) Problem {
+	p.Instance = path
+	p.RequestID = requestID
+	return p
+}
+
+// For returns p as it occurred on r, taking the request ID from the response
+// header the request ID middleware set, or else from the request.
+func (p Problem) For(w http.ResponseWriter, r *http.Request) Problem {
+	id := w.Header().Get(requestIDHeader)
+	if id == "" {
+		id = r.Header.Get(requestIDHeader)
+	}
+	return p.At(r.URL.Path, id)
+}
+
+// Write answers r with p, encoded with encoding/json. Controllers encode
+// problems with the request's JSON codec instead.
+func Write(w http.ResponseWriter, r *http.Request, p Problem) {
+	body, _ := json.Marshal(p.For(w, r))
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(p.Status)
+	w.Write(body)
+}
+
+// NotFound is the problem for a request no route matches.
+func NotFound() Problem {
+	return New(http.StatusNotFound, "No route matches the request path")
+}
+
+// MethodNotAllowed is the problem for a route that does not take method.
+func MethodNotAllowed(method string) Problem {
+	return New(http.StatusMethodNotAllowed, "The route does not allow "+method)
+}
+
+// Timeout is the problem for a request that ran past the request timeout.
+func Timeout(after time.Duration) Problem {
+	return New(http.StatusGatewayTimeout, "The request timed out after "+after.String())
+}
+
+// TooLarge is the problem for a request body over limit bytes.
+func TooLarge(limit int64) Problem {
+	return New(http.StatusRequestEntityTooLarge, "Request body exceeds "+payload.FormatSize(limit))
+}
+
+// Internal is the problem for a request the server failed to serve, such as
+// one whose handler panicked. What went wrong is logged, not sent.
+func Internal() Problem {
+	return New(http.StatusInternalServerError, "The server failed to serve the request")
+}
+
+// NotFoundHandler answers with NotFound, for the not found hook of net/http
+// routers.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, NotFound())
+}
+
+// MethodNotAllowedHandler answers with MethodNotAllowed, for the method not
+// allowed hook of net/http routers.
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, MethodNotAllowed(r.Method))
+}
@@ -0,0 +1,128 @@
+// Package protoconv converts models into their generated Protobuf messages.
+package protoconv
+
+import (
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/models"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+func SalesOrder(o models.SalesOrder) *bananasv1.SalesOrder {
+	return &bananasv1.SalesOrder{
+		Id:          o.ID.String(),
+		OrderNumber: o.OrderNumber,
+		CustomerId:  o.CustomerID.String(),
+		OrderDate:   timestamp(o.OrderDate),
+		Status:      o.Status,
+		Subtotal:    o.Subtotal,
+		Tax:         o.Tax,
+		Shipping:    o.Shipping,
+		Total:       o.Total,
+		Notes:       o.Notes,
+		CreatedAt:   timestamp(o.CreatedAt),
+		UpdatedAt:   timestamp(o.UpdatedAt),
+		DeletedAt:   optionalTimestamp(o.DeletedAt),
+	}
+}
+
+func SalesOrderItem(i models.SalesOrderItem) *bananasv1.SalesOrderItem {
+	return &bananasv1.SalesOrderItem{
+		Id:           i.ID.String(),
+		SalesOrderId: i.SalesOrderID.String(),
+		ProductId:    i.ProductID.String(),
+		Quantity:     int32(i.Quantity),
+		UnitPrice:    i.UnitPrice,
+		Discount:     i.Discount,
+		Tax:          i.Tax,
+		Total:        i.Total,
+		CreatedAt:    timestamp(i.CreatedAt),
+		UpdatedAt:    timestamp(i.UpdatedAt),
+	}
+}
+
+func Customer(c models.Customer) *bananasv1.Customer {
+	return &bananasv1.Customer{
+		Id:        c.ID.String(),
+		FirstName: c.FirstName,
+		LastName:  c.LastName,
+		Email:     c.Email,
+		Phone:     c.Phone,
+		CreatedAt: timestamp(c.CreatedAt),
+		UpdatedAt: timestamp(c.UpdatedAt),
+		DeletedAt: optionalTimestamp(c.DeletedAt),
+	}
+}
+
+func Product(p models.Product) *bananasv1.Product {
+	return &bananasv1.Product{
+		Id:          p.ID.String(),
+		Sku:         p.SKU,
+		Name:        p.Name,
+		Description: p.Description,
+		Weight:      p.Weight,
+		Dimensions:  p.Dimensions,
+		IsActive:    p.IsActive,
+		CreatedAt:   timestamp(p.CreatedAt),
+		UpdatedAt:   timestamp(p.UpdatedAt),
+		DeletedAt:   optionalTimestamp(p.DeletedAt),
+	}
+}
+
+func OrderWithDetails(o *models.OrderWithDetails) *bananasv1.OrderWithDetails {
+	items := make([]*bananasv1.OrderItemWithProduct, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = &bananasv1.OrderItemWithProduct{
+			Item:    SalesOrderItem(item.Item),
+			Product: Product(item.Product),
+		}
+	}
+	return &bananasv1.OrderWithDetails{
+		Order:    SalesOrder(o.Order),
+		Customer: Customer(o.Customer),
+		Items:    items,
+	}
+}
+
+func OrdersWithDetails(orders []*models.OrderWithDetails) []*bananasv1.OrderWithDetails {
+	result := make([]*bananasv1.OrderWithDetails, len(orders))
+	for i, o := range orders {
+		result[i] = OrderWithDetails(o)
+	}
+	return result
+}
+
+func TestResult(t *models.TestResult) *bananasv1.TestResult {
+	return &bananasv1.TestResult{
+		Id:          int64(t.ID),
+		Framework:   t.Framework,
+		TestType:    t.TestType,
+		ExecutionMs: int64(t.ExecutionMs),
+		Success:     t.Success,
+		CreatedAt:   timestamp(t.CreatedAt),
+		UpdatedAt:   timestamp(t.UpdatedAt),
+	}
+}
+
+func TestResults(results []*models.TestResult) []*bananasv1.TestResult {
+	converted := make([]*bananasv1.TestResult, len(results))
+	for i, t := range results {
+		converted[i] = TestResult(t)
+	}
+	return converted
+}
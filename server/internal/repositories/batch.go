@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"bananas/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Batch loads fetch every row for a set of keys in one query. They back the
+// GraphQL dataloaders, so rows come back in no particular order and keys
+// without rows are simply absent. The sql, sqlx and pgx repositories share
+// these queries; GORM builds its own.
+const (
+	customersByIDsQuery = `
+		SELECT id, first_name, last_name, email, phone, created_at, updated_at, deleted_at
+		FROM customers
+		WHERE id = ANY($1)
+	`
+
+	orderItemsByOrderIDsQuery = `
+		SELECT
+			id, sales_order_id, product_id, quantity,
+			unit_price, discount, tax, total,
+			created_at, updated_at
+		FROM sales_order_items
+		WHERE sales_order_id = ANY($1)
+		ORDER BY created_at, id
+	`
+
+	productsByIDsQuery = `
+		SELECT
+			id, sku, name, description, weight, dimensions,
+			is_active, created_at, updated_at, deleted_at
+		FROM products
+		WHERE id = ANY($1)
+	`
+
+	productCategoriesByProductIDsQuery = `
+		SELECT id, product_id, category_id, created_at
+		FROM product_categories
+		WHERE product_id = ANY($1)
+		ORDER BY created_at, id
+	`
+
+	categoriesByIDsQuery = `
+		SELECT id, name, description, parent_id, created_at, updated_at, deleted_at
+		FROM categories
+		WHERE id = ANY($1)
+	`
+
+	inventoryByProductIDsQuery = `
+		SELECT
+			id, product_id, warehouse_id, quantity, reserved_quantity,
+			reorder_point, reorder_quantity, created_at, updated_at
+		FROM inventory
+		WHERE product_id = ANY($1)
+		ORDER BY warehouse_id
+	`
+
+	supplierProductsByProductIDsQuery = `
+		SELECT
+			id, supplier_id, product_id, supplier_sku, cost, currency,
+			lead_time_days, minimum_order_quantity, created_at, updated_at
+		FROM supplier_products
+		WHERE product_id = ANY($1)
+		ORDER BY cost NULLS LAST, id
+	`
+
+	suppliersByIDsQuery = `
+		SELECT
+			id, name, contact_name, email, phone, address, city,
+			state, postal_code, country, created_at, updated_at, deleted_at
+		FROM suppliers
+		WHERE id = ANY($1)
+	`
+)
+
+// rowScanner is the part of *sql.Rows and pgx.Rows the batch loads use.
+type rowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// scanAll scans every remaining row with scan.
+func scanAll[T any](rows rowScanner, scan func(rowScanner, *T) error) ([]*T, error) {
+	var results []*T
+	for rows.Next() {
+		result := new(T)
+		if err := scan(rows, result); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func scanCustomer(row rowScanner, c *models.Customer) error {
+	return row.Scan(
+		&c.ID, &c.FirstName, &c.LastName, &c.Email, &c.Phone,
+		&c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
+	)
+}
+
+func scanOrderItem(row rowScanner, item *models.SalesOrderItem) error {
+	return row.Scan(
+		&item.ID, &item.SalesOrderID, &item.ProductID, &item.Quantity,
+		&item.UnitPrice, &item.Discount, &item.Tax, &item.Total,
+		&item.CreatedAt, &item.UpdatedAt,
+	)
+}
+
+func scanProduct(row rowScanner, p *models.Product) error {
+	return row.Scan(
+		&p.ID, &p.SKU, &p.Name, &p.Description, &p.Weight, &p.Dimensions,
+		&p.IsActive, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt,
+	)
+}
+
+func scanProductCategory(row rowScanner, pc *models.ProductCategory) error {
+	return row.Scan(&pc.ID, &pc.ProductID, &pc.CategoryID, &pc.CreatedAt)
+}
+
+func scanCategory(row rowScanner, c *models.Category) error {
+	return row.Scan(
+		&c.ID, &c.Name, &c.Description, &c.ParentID,
+		&c.CreatedAt, &c.UpdatedAt, &c.DeletedAt,
+	)
+}
+
+func scanInventory(row rowScanner, inv *models.Inventory) error {
+	return row.Scan(
+		&inv.ID, &inv.ProductID, &inv.WarehouseID, &inv.Quantity, &inv.ReservedQuantity,
+		&inv.ReorderPoint, &inv.ReorderQuantity, &inv.CreatedAt, &inv.UpdatedAt,
+	)
+}
+
+func scanSupplierProduct(row rowScanner, sp *models.SupplierProduct) error {
+	return row.Scan(
+		&sp.ID, &sp.SupplierID, &sp.ProductID, &sp.SupplierSKU, &sp.Cost, &sp.Currency,
+		&sp.LeadTimeDays, &sp.MinimumOrderQuantity, &sp.CreatedAt, &sp.UpdatedAt,
+	)
+}
+
+func scanSupplier(row rowScanner, s *models.Supplier) error {
+	return row.Scan(
+		&s.ID, &s.Name, &s.ContactName, &s.Email, &s.Phone, &s.Address, &s.City,
+		&s.State, &s.PostalCode, &s.Country, &s.CreatedAt, &s.UpdatedAt, &s.DeletedAt,
+	)
+}
+
+// uuidStrings converts ids for drivers that bind a []string as uuid[].
+func uuidStrings(ids []uuid.UUID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strs
+}
+
+// uuidArray renders ids as a Postgres array literal for database/sql, which
+// cannot bind slices.
+func uuidArray(ids []uuid.UUID) string {
+	return "{" + joinStrings(uuidStrings(ids), ",") + "}"
+}
@@ -5,8 +5,10 @@ import (
 	"bananas/internal/logger"
 	"bananas/internal/models"
 	"context"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -154,3 +156,228 @@ func (r *GORMRepository) GetRecentOrders(ctx context.Context, limit int) ([]*mod
 
 	return results, nil
 }
+
+func (r *GORMRepository) ListOrders(ctx context.Context, status string, limit, offset int) ([]*models.SalesOrder, error) {
+	var orders []*models.SalesOrder
+
+	query := r.DB.WithContext(ctx).
+		Table("sales_orders").
+		Where("deleted_at IS NULL")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.
+		Order("order_date DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+
+	if err != nil {
+		r.Logger.Er("failed to list orders", err)
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *GORMRepository) StreamOrders(ctx context.Context, status string, limit int, fn func(*models.SalesOrder) error) error {
+	query := r.DB.WithContext(ctx).
+		Table("sales_orders").
+		Where("deleted_at IS NULL")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Order("order_date DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		r.Logger.Er("failed to stream orders", err)
+		return err
+	}
+	defer rows.Close()
+
+	var order models.SalesOrder
+	for rows.Next() {
+		if err := r.DB.ScanRows(rows, &order); err != nil {
+			r.Logger.Er("failed to scan order", err)
+			return err
+		}
+		if err := fn(&order); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil && ctx.Err() == nil {
+		r.Logger.Er("error iterating orders", err)
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (r *GORMRepository) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Customer, error) {
+	var customers []*models.Customer
+
+	err := r.DB.WithContext(ctx).
+		Table("customers").
+		Where("id IN ?", uuidStrings(ids)).
+		Find(&customers).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query customers", err)
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (r *GORMRepository) GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*models.SalesOrderItem, error) {
+	var items []*models.SalesOrderItem
+
+	err := r.DB.WithContext(ctx).
+		Table("sales_order_items").
+		Where("sales_order_id IN ?", uuidStrings(orderIDs)).
+		Order("created_at, id").
+		Find(&items).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query order items", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *GORMRepository) GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	var products []*models.Product
+
+	err := r.DB.WithContext(ctx).
+		Table("products").
+		Where("id IN ?", uuidStrings(ids)).
+		Find(&products).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query products", err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *GORMRepository) GetProductCategoriesByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.ProductCategory, error) {
+	var productCategories []*models.ProductCategory
+
+	err := r.DB.WithContext(ctx).
+		Table("product_categories").
+		Where("product_id IN ?", uuidStrings(productIDs)).
+		Order("created_at, id").
+		Find(&productCategories).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query product categories", err)
+		return nil, err
+	}
+
+	return productCategories, nil
+}
+
+func (r *GORMRepository) GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Category, error) {
+	var categories []*models.Category
+
+	err := r.DB.WithContext(ctx).
+		Table("categories").
+		Where("id IN ?", uuidStrings(ids)).
+		Find(&categories).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query categories", err)
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *GORMRepository) GetInventoryByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.Inventory, error) {
+	var inventory []*models.Inventory
+
+	err := r.DB.WithContext(ctx).
+		Table("inventory").
+		Where("product_id IN ?", uuidStrings(productIDs)).
+		Order("warehouse_id").
+		Find(&inventory).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query inventory", err)
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+func (r *GORMRepository) GetSupplierProductsByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.SupplierProduct, error) {
+	var supplierProducts []*models.SupplierProduct
+
+	err := r.DB.WithContext(ctx).
+		Table("supplier_products").
+		Where("product_id IN ?", uuidStrings(productIDs)).
+		Order("cost NULLS LAST, id").
+		Find(&supplierProducts).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query supplier products", err)
+		return nil, err
+	}
+
+	return supplierProducts, nil
+}
+
+func (r *GORMRepository) GetSuppliersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Supplier, error) {
+	var suppliers []*models.Supplier
+
+	err := r.DB.WithContext(ctx).
+		Table("suppliers").
+		Where("id IN ?", uuidStrings(ids)).
+		Find(&suppliers).Error
+
+	if err != nil {
+		r.Logger.Er("failed to query suppliers", err)
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+func (r *GORMRepository) CreateUser(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	err := r.DB.WithContext(ctx).Table("users").Create(user).Error
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		r.Logger.Er("failed to create user", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *GORMRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	user := &models.User{}
+	err := r.DB.WithContext(ctx).Table("users").Where("email = ?", email).Take(user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.Logger.Er("failed to query user", err)
+		return nil, err
+	}
+
+	return user, nil
+}
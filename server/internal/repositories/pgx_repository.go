@@ -5,8 +5,11 @@ import (
 	"bananas/internal/logger"
 	"bananas/internal/models"
 	"context"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -274,3 +277,171 @@ func (r *PGXRepository) GetRecentOrders(ctx context.Context, limit int) ([]*mode
 
 	return results, nil
 }
+
+func (r *PGXRepository) ListOrders(ctx context.Context, status string, limit, offset int) ([]*models.SalesOrder, error) {
+	query := `
+		SELECT
+			id, order_number, customer_id, order_date, status,
+			subtotal, tax, shipping, total, notes,
+			created_at, updated_at, deleted_at
+		FROM sales_orders
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
+		ORDER BY order_date DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.Pool.Query(ctx, query, status, limit, offset)
+	if err != nil {
+		r.Logger.Er("failed to list orders", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*models.SalesOrder
+	for rows.Next() {
+		order := &models.SalesOrder{}
+		err := rows.Scan(
+			&order.ID, &order.OrderNumber, &order.CustomerID, &order.OrderDate, &order.Status,
+			&order.Subtotal, &order.Tax, &order.Shipping, &order.Total, &order.Notes,
+			&order.CreatedAt, &order.UpdatedAt, &order.DeletedAt,
+		)
+		if err != nil {
+			r.Logger.Er("failed to scan order", err)
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.Logger.Er("error iterating orders", err)
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *PGXRepository) StreamOrders(ctx context.Context, status string, limit int, fn func(*models.SalesOrder) error) error {
+	query := `
+		SELECT
+			id, order_number, customer_id, order_date, status,
+			subtotal, tax, shipping, total, notes,
+			created_at, updated_at, deleted_at
+		FROM sales_orders
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
+		ORDER BY order_date DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := r.Pool.Query(ctx, query, status, limit)
+	if err != nil {
+		r.Logger.Er("failed to stream orders", err)
+		return err
+	}
+	defer rows.Close()
+
+	var order models.SalesOrder
+	for rows.Next() {
+		err := rows.Scan(
+			&order.ID, &order.OrderNumber, &order.CustomerID, &order.OrderDate, &order.Status,
+			&order.Subtotal, &order.Tax, &order.Shipping, &order.Total, &order.Notes,
+			&order.CreatedAt, &order.UpdatedAt, &order.DeletedAt,
+		)
+		if err != nil {
+			r.Logger.Er("failed to scan order", err)
+			return err
+		}
+		if err := fn(&order); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil && ctx.Err() == nil {
+		r.Logger.Er("error iterating orders", err)
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (r *PGXRepository) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Customer, error) {
+	return pgxBatchLoad(ctx, r, "customers", customersByIDsQuery, ids, scanCustomer)
+}
+
+func (r *PGXRepository) GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*models.SalesOrderItem, error) {
+	return pgxBatchLoad(ctx, r, "order items", orderItemsByOrderIDsQuery, orderIDs, scanOrderItem)
+}
+
+func (r *PGXRepository) GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	return pgxBatchLoad(ctx, r, "products", productsByIDsQuery, ids, scanProduct)
+}
+
+func (r *PGXRepository) GetProductCategoriesByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.ProductCategory, error) {
+	return pgxBatchLoad(ctx, r, "product categories", productCategoriesByProductIDsQuery, productIDs, scanProductCategory)
+}
+
+func (r *PGXRepository) GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Category, error) {
+	return pgxBatchLoad(ctx, r, "categories", categoriesByIDsQuery, ids, scanCategory)
+}
+
+func (r *PGXRepository) GetInventoryByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.Inventory, error) {
+	return pgxBatchLoad(ctx, r, "inventory", inventoryByProductIDsQuery, productIDs, scanInventory)
+}
+
+func (r *PGXRepository) GetSupplierProductsByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.SupplierProduct, error) {
+	return pgxBatchLoad(ctx, r, "supplier products", supplierProductsByProductIDsQuery, productIDs, scanSupplierProduct)
+}
+
+func (r *PGXRepository) GetSuppliersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Supplier, error) {
+	return pgxBatchLoad(ctx, r, "suppliers", suppliersByIDsQuery, ids, scanSupplier)
+}
+
+// pgxBatchLoad runs one of the shared batch queries, which pgx binds a
+// []string to as a uuid array.
+func pgxBatchLoad[T any](ctx context.Context, r *PGXRepository, name, query string, ids []uuid.UUID, scan func(rowScanner, *T) error) ([]*T, error) {
+	rows, err := r.Pool.Query(ctx, query, uuidStrings(ids))
+	if err != nil {
+		r.Logger.Er("failed to query "+name, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results, err := scanAll(rows, scan)
+	if err != nil {
+		r.Logger.Er("failed to scan "+name, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *PGXRepository) CreateUser(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	err := r.Pool.QueryRow(ctx, createUserQuery, user.Email, user.PasswordHash, now, now).Scan(&user.ID)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		r.Logger.Er("failed to create user", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PGXRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	user := &models.User{}
+	err := r.Pool.QueryRow(ctx, userByEmailQuery, email).
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.Logger.Er("failed to query user", err)
+		return nil, err
+	}
+
+	return user, nil
+}
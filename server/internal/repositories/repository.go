@@ -5,7 +5,11 @@ import (
 	"bananas/internal/logger"
 	"bananas/internal/models"
 	"context"
+	"database/sql"
+	"errors"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type RepositoryInterface interface {
@@ -14,6 +18,29 @@ type RepositoryInterface interface {
 	CreateFramework(ctx context.Context, framework *models.Framework) error
 	GetFrameworks(ctx context.Context, frameworkType string) ([]*models.Framework, error)
 	GetRecentOrders(ctx context.Context, limit int) ([]*models.OrderWithDetails, error)
+	ListOrders(ctx context.Context, status string, limit, offset int) ([]*models.SalesOrder, error)
+	// StreamOrders reads orders newest first from a database cursor and
+	// passes each to fn, stopping at the first error fn returns. The order
+	// is reused between calls, so fn must not keep it. A limit of 0 streams
+	// every order.
+	StreamOrders(ctx context.Context, status string, limit int, fn func(*models.SalesOrder) error) error
+
+	// Batch loads for the GraphQL dataloaders. Each fetches the rows for
+	// all of its keys in one query, in no particular order.
+	GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Customer, error)
+	GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*models.SalesOrderItem, error)
+	GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error)
+	GetProductCategoriesByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.ProductCategory, error)
+	GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Category, error)
+	GetInventoryByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.Inventory, error)
+	GetSupplierProductsByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.SupplierProduct, error)
+	GetSuppliersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Supplier, error)
+
+	// CreateUser inserts user, filling in its ID, and returns ErrDuplicate
+	// when the email is taken. GetUserByEmail returns nil when no user has
+	// the email.
+	CreateUser(ctx context.Context, user *models.User) error
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 }
 
 type SQLRepository struct {
@@ -281,6 +308,174 @@ func (r *SQLRepository) GetRecentOrders(ctx context.Context, limit int) ([]*mode
 	return results, nil
 }
 
+func (r *SQLRepository) ListOrders(ctx context.Context, status string, limit, offset int) ([]*models.SalesOrder, error) {
+	query := `
+		SELECT
+			id, order_number, customer_id, order_date, status,
+			subtotal, tax, shipping, total, notes,
+			created_at, updated_at, deleted_at
+		FROM sales_orders
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
+		ORDER BY order_date DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.DB.SQL.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		r.Logger.Er("failed to list orders", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*models.SalesOrder
+	for rows.Next() {
+		order := &models.SalesOrder{}
+		err := rows.Scan(
+			&order.ID, &order.OrderNumber, &order.CustomerID, &order.OrderDate, &order.Status,
+			&order.Subtotal, &order.Tax, &order.Shipping, &order.Total, &order.Notes,
+			&order.CreatedAt, &order.UpdatedAt, &order.DeletedAt,
+		)
+		if err != nil {
+			r.Logger.Er("failed to scan order", err)
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.Logger.Er("error iterating orders", err)
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *SQLRepository) StreamOrders(ctx context.Context, status string, limit int, fn func(*models.SalesOrder) error) error {
+	query := `
+		SELECT
+			id, order_number, customer_id, order_date, status,
+			subtotal, tax, shipping, total, notes,
+			created_at, updated_at, deleted_at
+		FROM sales_orders
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
+		ORDER BY order_date DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := r.DB.SQL.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		r.Logger.Er("failed to stream orders", err)
+		return err
+	}
+	defer rows.Close()
+
+	var order models.SalesOrder
+	for rows.Next() {
+		err := rows.Scan(
+			&order.ID, &order.OrderNumber, &order.CustomerID, &order.OrderDate, &order.Status,
+			&order.Subtotal, &order.Tax, &order.Shipping, &order.Total, &order.Notes,
+			&order.CreatedAt, &order.UpdatedAt, &order.DeletedAt,
+		)
+		if err != nil {
+			r.Logger.Er("failed to scan order", err)
+			return err
+		}
+		if err := fn(&order); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil && ctx.Err() == nil {
+		r.Logger.Er("error iterating orders", err)
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (r *SQLRepository) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Customer, error) {
+	return sqlBatchLoad(ctx, r, "customers", customersByIDsQuery, ids, scanCustomer)
+}
+
+func (r *SQLRepository) GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*models.SalesOrderItem, error) {
+	return sqlBatchLoad(ctx, r, "order items", orderItemsByOrderIDsQuery, orderIDs, scanOrderItem)
+}
+
+func (r *SQLRepository) GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	return sqlBatchLoad(ctx, r, "products", productsByIDsQuery, ids, scanProduct)
+}
+
+func (r *SQLRepository) GetProductCategoriesByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.ProductCategory, error) {
+	return sqlBatchLoad(ctx, r, "product categories", productCategoriesByProductIDsQuery, productIDs, scanProductCategory)
+}
+
+func (r *SQLRepository) GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Category, error) {
+	return sqlBatchLoad(ctx, r, "categories", categoriesByIDsQuery, ids, scanCategory)
+}
+
+func (r *SQLRepository) GetInventoryByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.Inventory, error) {
+	return sqlBatchLoad(ctx, r, "inventory", inventoryByProductIDsQuery, productIDs, scanInventory)
+}
+
+func (r *SQLRepository) GetSupplierProductsByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.SupplierProduct, error) {
+	return sqlBatchLoad(ctx, r, "supplier products", supplierProductsByProductIDsQuery, productIDs, scanSupplierProduct)
+}
+
+func (r *SQLRepository) GetSuppliersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Supplier, error) {
+	return sqlBatchLoad(ctx, r, "suppliers", suppliersByIDsQuery, ids, scanSupplier)
+}
+
+func (r *SQLRepository) CreateUser(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	err := r.DB.SQL.QueryRowContext(ctx, createUserQuery, user.Email, user.PasswordHash, now, now).Scan(&user.ID)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		r.Logger.Er("failed to create user", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	user := &models.User{}
+	err := r.DB.SQL.QueryRowContext(ctx, userByEmailQuery, email).
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.Logger.Er("failed to query user", err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// sqlBatchLoad runs one of the shared batch queries, binding ids as an array
+// literal.
+func sqlBatchLoad[T any](ctx context.Context, r *SQLRepository, name, query string, ids []uuid.UUID, scan func(rowScanner, *T) error) ([]*T, error) {
+	rows, err := r.DB.SQL.QueryContext(ctx, query, uuidArray(ids))
+	if err != nil {
+		r.Logger.Er("failed to query "+name, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results, err := scanAll(rows, scan)
+	if err != nil {
+		r.Logger.Er("failed to scan "+name, err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func joinStrings(strs []string, sep string) string {
 	result := ""
 	for i, s := range strs {
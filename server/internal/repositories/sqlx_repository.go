@@ -5,8 +5,11 @@ import (
 	"bananas/internal/logger"
 	"bananas/internal/models"
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -226,3 +229,182 @@ func (r *SQLxRepository) GetRecentOrders(ctx context.Context, limit int) ([]*mod
 
 	return results, nil
 }
+
+func (r *SQLxRepository) ListOrders(ctx context.Context, status string, limit, offset int) ([]*models.SalesOrder, error) {
+	query := `
+		SELECT
+			id, order_number, customer_id, order_date, status,
+			subtotal, tax, shipping, total, notes,
+			created_at, updated_at, deleted_at
+		FROM sales_orders
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
+		ORDER BY order_date DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var orders []*models.SalesOrder
+	err := r.DB.SelectContext(ctx, &orders, query, status, limit, offset)
+	if err != nil {
+		r.Logger.Er("failed to list orders", err)
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *SQLxRepository) StreamOrders(ctx context.Context, status string, limit int, fn func(*models.SalesOrder) error) error {
+	query := `
+		SELECT
+			id, order_number, customer_id, order_date, status,
+			subtotal, tax, shipping, total, notes,
+			created_at, updated_at, deleted_at
+		FROM sales_orders
+		WHERE deleted_at IS NULL AND ($1 = '' OR status = $1)
+		ORDER BY order_date DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := r.DB.QueryxContext(ctx, query, status, limit)
+	if err != nil {
+		r.Logger.Er("failed to stream orders", err)
+		return err
+	}
+	defer rows.Close()
+
+	var order models.SalesOrder
+	for rows.Next() {
+		if err := rows.StructScan(&order); err != nil {
+			r.Logger.Er("failed to scan order", err)
+			return err
+		}
+		if err := fn(&order); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil && ctx.Err() == nil {
+		r.Logger.Er("error iterating orders", err)
+		return err
+	}
+
+	return ctx.Err()
+}
+
+func (r *SQLxRepository) GetCustomersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Customer, error) {
+	var customers []*models.Customer
+	err := r.DB.SelectContext(ctx, &customers, customersByIDsQuery, uuidArray(ids))
+	if err != nil {
+		r.Logger.Er("failed to query customers", err)
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+func (r *SQLxRepository) GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*models.SalesOrderItem, error) {
+	var items []*models.SalesOrderItem
+	err := r.DB.SelectContext(ctx, &items, orderItemsByOrderIDsQuery, uuidArray(orderIDs))
+	if err != nil {
+		r.Logger.Er("failed to query order items", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *SQLxRepository) GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	var products []*models.Product
+	err := r.DB.SelectContext(ctx, &products, productsByIDsQuery, uuidArray(ids))
+	if err != nil {
+		r.Logger.Er("failed to query products", err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *SQLxRepository) GetProductCategoriesByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.ProductCategory, error) {
+	var productCategories []*models.ProductCategory
+	err := r.DB.SelectContext(ctx, &productCategories, productCategoriesByProductIDsQuery, uuidArray(productIDs))
+	if err != nil {
+		r.Logger.Er("failed to query product categories", err)
+		return nil, err
+	}
+
+	return productCategories, nil
+}
+
+func (r *SQLxRepository) GetCategoriesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Category, error) {
+	var categories []*models.Category
+	err := r.DB.SelectContext(ctx, &categories, categoriesByIDsQuery, uuidArray(ids))
+	if err != nil {
+		r.Logger.Er("failed to query categories", err)
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *SQLxRepository) GetInventoryByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.Inventory, error) {
+	var inventory []*models.Inventory
+	err := r.DB.SelectContext(ctx, &inventory, inventoryByProductIDsQuery, uuidArray(productIDs))
+	if err != nil {
+		r.Logger.Er("failed to query inventory", err)
+		return nil, err
+	}
+
+	return inventory, nil
+}
+
+func (r *SQLxRepository) GetSupplierProductsByProductIDs(ctx context.Context, productIDs []uuid.UUID) ([]*models.SupplierProduct, error) {
+	var supplierProducts []*models.SupplierProduct
+	err := r.DB.SelectContext(ctx, &supplierProducts, supplierProductsByProductIDsQuery, uuidArray(productIDs))
+	if err != nil {
+		r.Logger.Er("failed to query supplier products", err)
+		return nil, err
+	}
+
+	return supplierProducts, nil
+}
+
+func (r *SQLxRepository) GetSuppliersByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Supplier, error) {
+	var suppliers []*models.Supplier
+	err := r.DB.SelectContext(ctx, &suppliers, suppliersByIDsQuery, uuidArray(ids))
+	if err != nil {
+		r.Logger.Er("failed to query suppliers", err)
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+func (r *SQLxRepository) CreateUser(ctx context.Context, user *models.User) error {
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	err := r.DB.QueryRowxContext(ctx, createUserQuery, user.Email, user.PasswordHash, now, now).Scan(&user.ID)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		r.Logger.Er("failed to create user", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLxRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	user := &models.User{}
+	err := r.DB.GetContext(ctx, user, userByEmailQuery, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		r.Logger.Er("failed to query user", err)
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicate is returned when an insert would break a unique constraint,
+// such as registering an email that already has a user.
+var ErrDuplicate = errors.New("duplicate key")
+
+const (
+	createUserQuery = `
+		INSERT INTO users (email, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	userByEmailQuery = `
+		SELECT id, email, password_hash, created_at, updated_at
+		FROM users
+		WHERE email = $1
+	`
+)
+
+// uniqueViolation is Postgres's SQLSTATE for a broken unique constraint.
+const uniqueViolation = "23505"
+
+// isUniqueViolation recognizes a unique constraint error from lib/pq, which
+// database/sql and sqlx use, and from pgx, which GORM's driver uses too.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == uniqueViolation
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == uniqueViolation
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/gen/bananas/v1/bananasv1connect"
+	"bananas/internal/services"
+	"context"
+	"errors"
+	"net/http"
+
+	"connectrpc.com/connect"
+)
+
+// connectService adapts server to the generated Connect interface.
+type connectService struct {
+	bananasv1connect.UnimplementedBananasServiceHandler
+	*server
+}
+
+// NewConnectHandler returns the mount path and handler for BananasService
+// over Connect. The handler also speaks gRPC and gRPC-Web, so the one route
+// serves all three protocols.
+func NewConnectHandler(service *services.Service) (string, http.Handler) {
+	return bananasv1connect.NewBananasServiceHandler(&connectService{server: newServer(service, "connect")})
+}
+
+func (c *connectService) SimpleRequest(context.Context, *connect.Request[bananasv1.SimpleRequestRequest]) (*connect.Response[bananasv1.SimpleResponse], error) {
+	return connect.NewResponse(c.simpleRequest()), nil
+}
+
+func (c *connectService) JsonResponse(context.Context, *connect.Request[bananasv1.JsonResponseRequest]) (*connect.Response[bananasv1.JsonResponse], error) {
+	return connect.NewResponse(c.jsonResponse()), nil
+}
+
+func (c *connectService) GetRecentOrders(ctx context.Context, req *connect.Request[bananasv1.GetRecentOrdersRequest]) (*connect.Response[bananasv1.RecentOrdersResponse], error) {
+	msg, dbTime, err := c.getRecentOrders(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	resp := connect.NewResponse(msg)
+	resp.Header().Set("Server-Timing", dbTiming(msg.Orm, dbTime))
+	return resp, nil
+}
+
+func (c *connectService) ListOrders(ctx context.Context, req *connect.Request[bananasv1.ListOrdersRequest]) (*connect.Response[bananasv1.ListOrdersResponse], error) {
+	msg, dbTime, err := c.listOrders(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	resp := connect.NewResponse(msg)
+	resp.Header().Set("Server-Timing", dbTiming(msg.Orm, dbTime))
+	return resp, nil
+}
+
+func (c *connectService) StreamOrders(ctx context.Context, req *connect.Request[bananasv1.StreamOrdersRequest], stream *connect.ServerStream[bananasv1.OrderWithDetails]) error {
+	return connectError(c.streamOrders(ctx, req.Msg, stream.Send))
+}
+
+// connectError maps implementation errors onto Connect codes.
+func connectError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return err
+	}
+	switch {
+	case errors.Is(err, errInvalidArgument):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return connect.NewError(connect.CodeDeadlineExceeded, err)
+	case errors.Is(err, context.Canceled):
+		return connect.NewError(connect.CodeCanceled, err)
+	}
+	return connect.NewError(connect.CodeInternal, errors.New("failed to query orders"))
+}
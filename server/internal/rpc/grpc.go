@@ -0,0 +1,208 @@
+package rpc
+
+import (
+	"bananas/internal/config"
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/logger"
+	"bananas/internal/middleware"
+	"bananas/internal/services"
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/encoding/gzip" // lets clients ask for gzip responses
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// grpcService adapts server to the generated gRPC interface.
+type grpcService struct {
+	bananasv1.UnimplementedBananasServiceServer
+	*server
+}
+
+// NewGRPCServer builds a gRPC server exposing BananasService and the standard
+// health service, with interceptors matching the configured middleware stack.
+func NewGRPCServer(service *services.Service, stack config.MiddlewareConfig) *grpc.Server {
+	var unary []grpc.UnaryServerInterceptor
+	var streams []grpc.StreamServerInterceptor
+	opts := []grpc.ServerOption{}
+
+	if stack.Production() {
+		unary = append(unary, requestIDUnary, accessLogUnary)
+		streams = append(streams, requestIDStream, accessLogStream)
+	}
+	if stack.Minimal() {
+		unary = append(unary, recoverUnary)
+		streams = append(streams, recoverStream)
+	}
+	if stack.Production() {
+		unary = append(unary, timeoutUnary(stack.RequestTimeout))
+		opts = append(opts, grpc.MaxRecvMsgSize(int(stack.MaxBodyBytes)))
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(streams...))
+
+	s := grpc.NewServer(opts...)
+	bananasv1.RegisterBananasServiceServer(s, &grpcService{server: newServer(service, "grpc")})
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	return s
+}
+
+func (g *grpcService) SimpleRequest(context.Context, *bananasv1.SimpleRequestRequest) (*bananasv1.SimpleResponse, error) {
+	return g.simpleRequest(), nil
+}
+
+func (g *grpcService) JsonResponse(context.Context, *bananasv1.JsonResponseRequest) (*bananasv1.JsonResponse, error) {
+	return g.jsonResponse(), nil
+}
+
+func (g *grpcService) GetRecentOrders(ctx context.Context, req *bananasv1.GetRecentOrdersRequest) (*bananasv1.RecentOrdersResponse, error) {
+	resp, dbTime, err := g.getRecentOrders(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("server-timing", dbTiming(resp.Orm, dbTime)))
+	return resp, nil
+}
+
+func (g *grpcService) ListOrders(ctx context.Context, req *bananasv1.ListOrdersRequest) (*bananasv1.ListOrdersResponse, error) {
+	resp, dbTime, err := g.listOrders(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("server-timing", dbTiming(resp.Orm, dbTime)))
+	return resp, nil
+}
+
+func (g *grpcService) StreamOrders(req *bananasv1.StreamOrdersRequest, stream grpc.ServerStreamingServer[bananasv1.OrderWithDetails]) error {
+	return grpcError(g.streamOrders(stream.Context(), req, stream.Send))
+}
+
+// grpcError maps implementation errors onto gRPC status codes. Errors that
+// already carry a status, such as a failed Send, pass through.
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, errInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to query orders")
+}
+
+// requestIDFromMetadata reuses the caller's x-request-id or generates one and
+// sends it back in the response headers.
+func requestIDFromMetadata(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(middleware.RequestIDHeader); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = middleware.NewRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(middleware.RequestIDHeader, id))
+	return middleware.WithRequestID(ctx, id)
+}
+
+func requestIDUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(requestIDFromMetadata(ctx), req)
+}
+
+func requestIDStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: ss, ctx: requestIDFromMetadata(ss.Context())})
+}
+
+// accessLogUnary writes the shared JSON access line, with the gRPC status
+// code as the status and the response message size as the bytes.
+func accessLogUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	var size int64
+	if m, ok := resp.(proto.Message); ok && err == nil {
+		size = int64(proto.Size(m))
+	}
+	middleware.LogAccess(middleware.AccessEntry{
+		Framework: "grpc",
+		RequestID: middleware.GetRequestID(ctx),
+		Method:    "POST",
+		Path:      info.FullMethod,
+		Status:    int(status.Code(err)),
+		Bytes:     size,
+		Latency:   time.Since(start),
+	})
+	return resp, err
+}
+
+func accessLogStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	middleware.LogAccess(middleware.AccessEntry{
+		Framework: "grpc",
+		RequestID: middleware.GetRequestID(ss.Context()),
+		Method:    "POST",
+		Path:      info.FullMethod,
+		Status:    int(status.Code(err)),
+		Latency:   time.Since(start),
+	})
+	return err
+}
+
+// recoverUnary turns a panic into codes.Internal and logs it with the stack.
+func recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logPanic(info.FullMethod, rec)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logPanic(info.FullMethod, rec)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func logPanic(method string, rec any) {
+	logger.New("grpc").Function("recover").Er("panic serving %s: %v\n%s", nil, method, rec, debug.Stack())
+}
+
+// timeoutUnary puts a deadline on unary calls. Streams are left unbounded
+// since their length depends on the request.
+func timeoutUnary(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// contextStream overrides a ServerStream's context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,157 @@
+// Package rpc implements BananasService once, on top of services.Service, and
+// adapts it to gRPC and Connect so both RPC stacks run identical code.
+package rpc
+
+import (
+	bananasv1 "bananas/internal/gen/bananas/v1"
+	"bananas/internal/logger"
+	"bananas/internal/models"
+	"bananas/internal/protoconv"
+	"bananas/internal/services"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Limits match the HTTP endpoints so both APIs load the same amount of data.
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// errInvalidArgument marks request errors so each adapter can map them onto
+// its own status code.
+var errInvalidArgument = errors.New("invalid argument")
+
+// server holds the transport independent implementation. framework names the
+// stack serving the call and is echoed in responses like the HTTP API does.
+type server struct {
+	service   *services.Service
+	framework string
+	log       logger.Logger
+}
+
+func newServer(service *services.Service, framework string) *server {
+	return &server{
+		service:   service,
+		framework: framework,
+		log:       logger.New(framework),
+	}
+}
+
+func (s *server) simpleRequest() *bananasv1.SimpleResponse {
+	return &bananasv1.SimpleResponse{
+		Message:   "Simple request successful",
+		Framework: s.framework,
+	}
+}
+
+func (s *server) jsonResponse() *bananasv1.JsonResponse {
+	return &bananasv1.JsonResponse{
+		Message:   "JSON response successful",
+		Framework: s.framework,
+		Timestamp: time.Now().Unix(),
+		Data: []*bananasv1.JsonResponse_Item{
+			{Id: 1, Name: "Item 1", Value: 100.5},
+			{Id: 2, Name: "Item 2", Value: 200.3},
+			{Id: 3, Name: "Item 3", Value: 150.7},
+		},
+	}
+}
+
+// getRecentOrders also returns the database time, which callers report in
+// the Server-Timing header at full precision.
+func (s *server) getRecentOrders(ctx context.Context, req *bananasv1.GetRecentOrdersRequest) (*bananasv1.RecentOrdersResponse, time.Duration, error) {
+	totalStart := time.Now()
+	orm := ormOrDefault(req.GetOrm())
+
+	orders, dbTime, err := s.service.GetRecentOrders(ctx, orm, limitOrDefault(req.GetLimit()))
+	if err != nil {
+		s.log.Er("failed to get recent orders", err)
+		return nil, 0, err
+	}
+
+	totalTime := time.Since(totalStart)
+	return &bananasv1.RecentOrdersResponse{
+		Orders:        protoconv.OrdersWithDetails(orders),
+		Count:         int32(len(orders)),
+		Orm:           orm,
+		Framework:     s.framework,
+		DbTime:        dbTime.Milliseconds(),
+		TotalTime:     totalTime.Milliseconds(),
+		FrameworkTime: (totalTime - dbTime).Milliseconds(),
+	}, dbTime, nil
+}
+
+// listOrders pages with an offset carried in an opaque page token. Like
+// getRecentOrders it also returns the database time.
+func (s *server) listOrders(ctx context.Context, req *bananasv1.ListOrdersRequest) (*bananasv1.ListOrdersResponse, time.Duration, error) {
+	orm := ormOrDefault(req.GetOrm())
+	limit := limitOrDefault(req.GetPageSize())
+
+	offset := 0
+	if token := req.GetPageToken(); token != "" {
+		parsed, err := strconv.Atoi(token)
+		if err != nil || parsed < 0 {
+			return nil, 0, fmt.Errorf("%w: bad page token %q", errInvalidArgument, token)
+		}
+		offset = parsed
+	}
+
+	orders, dbTime, err := s.service.ListOrders(ctx, orm, req.GetStatus(), limit, offset)
+	if err != nil {
+		s.log.Er("failed to list orders", err)
+		return nil, 0, err
+	}
+
+	resp := &bananasv1.ListOrdersResponse{
+		Orders:    make([]*bananasv1.SalesOrder, len(orders)),
+		Orm:       orm,
+		Framework: s.framework,
+		DbTime:    dbTime.Milliseconds(),
+	}
+	for i, order := range orders {
+		resp.Orders[i] = protoconv.SalesOrder(*order)
+	}
+	if len(orders) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, dbTime, nil
+}
+
+// streamOrders hands orders to send as they come off the database cursor,
+// like the HTTP stream endpoints, so the whole result is never held in
+// memory. The cursor reads sales orders alone, so each message carries the
+// order without its customer or items.
+func (s *server) streamOrders(ctx context.Context, req *bananasv1.StreamOrdersRequest, send func(*bananasv1.OrderWithDetails) error) error {
+	err := s.service.StreamOrders(ctx, ormOrDefault(req.GetOrm()), "", limitOrDefault(req.GetLimit()), func(order *models.SalesOrder) error {
+		return send(&bananasv1.OrderWithDetails{Order: protoconv.SalesOrder(*order)})
+	})
+	if err != nil && ctx.Err() == nil {
+		s.log.Er("failed to stream orders", err)
+	}
+	return err
+}
+
+// dbTiming formats the Server-Timing value the HTTP API reports for the
+// database, in the same fractional milliseconds, so RPC responses carry it as
+// a header too.
+func dbTiming(orm string, dbTime time.Duration) string {
+	return "db;desc=" + strconv.Quote(orm) + ";dur=" + strconv.FormatFloat(float64(dbTime.Microseconds())/1000, 'f', 3, 64)
+}
+
+func ormOrDefault(orm string) string {
+	if orm == "" {
+		return "sql"
+	}
+	return orm
+}
+
+func limitOrDefault(limit int32) int {
+	if limit <= 0 || limit > maxLimit {
+		return defaultLimit
+	}
+	return int(limit)
+}
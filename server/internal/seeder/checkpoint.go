@@ -0,0 +1,299 @@
+package seeder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/yaml.v3"
+)
+
+// createSeedProgress creates the table seeding records its progress in, so
+// a failed run can be resumed: a row per phase, with an empty table_name,
+// and a row per table seeded in it. Every row carries the run's seed,
+// reference time and config fingerprint. position counts the orders of the
+// sales and purchase order phases whose batches were fully written, or the
+// rows of a completed table. indexes holds the definitions of a table's
+// secondary indexes dropped for loading, until they are rebuilt.
+const createSeedProgress = `
+	CREATE TABLE IF NOT EXISTS seed_progress (
+		phase SMALLINT NOT NULL,
+		table_name VARCHAR(100) NOT NULL DEFAULT '',
+		position BIGINT NOT NULL DEFAULT 0,
+		completed BOOLEAN NOT NULL DEFAULT false,
+		indexes TEXT[],
+		random_seed BIGINT NOT NULL,
+		as_of TIMESTAMP WITH TIME ZONE NOT NULL,
+		fingerprint VARCHAR(64) NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		PRIMARY KEY (phase, table_name)
+	)`
+
+// Seeding phases
+const (
+	phaseMasterData = iota + 1
+	phaseProducts
+	phaseCustomers
+	phaseSalesOrders
+	phasePurchaseOrders
+	phaseInventory
+	phases = phaseInventory
+)
+
+// tablePhases is the seeding phase each table is written in.
+// inventory_transactions is also written by the order phases, with a
+// ledger, and counts as written in the last.
+var tablePhases = map[string]int{
+	"categories": phaseMasterData, "suppliers": phaseMasterData, "warehouses": phaseMasterData,
+	"products": phaseProducts, "product_categories": phaseProducts, "product_prices": phaseProducts,
+	"product_costs": phaseProducts, "supplier_products": phaseProducts,
+	"customers": phaseCustomers, "customer_addresses": phaseCustomers,
+	"sales_orders": phaseSalesOrders, "sales_order_items": phaseSalesOrders, "sales_order_payments": phaseSalesOrders,
+	"purchase_orders": phasePurchaseOrders, "purchase_order_items": phasePurchaseOrders, "purchase_order_receipts": phasePurchaseOrders,
+	"inventory": phaseInventory, "inventory_transactions": phaseInventory,
+}
+
+// step is a phase, or a table seeded in it
+type step struct {
+	phase int
+	table string // empty for the phase itself
+}
+
+// checkpoint is the recorded progress of one step
+type checkpoint struct {
+	position  int
+	completed bool
+	indexes   []string
+}
+
+// checkpoints records and reads back seeding progress in seed_progress
+type checkpoints struct {
+	pool  *pgxpool.Pool
+	steps map[step]*checkpoint
+}
+
+func newCheckpoints(pool *pgxpool.Pool) *checkpoints {
+	return &checkpoints{pool: pool, steps: make(map[step]*checkpoint)}
+}
+
+// run identifies a seeding run: resuming needs the same generated data
+type run struct {
+	seed        uint64
+	asOf        time.Time
+	fingerprint string
+}
+
+// errNoRun is returned by load when seed_progress records no run
+var errNoRun = errors.New("no seeding run recorded in seed_progress")
+
+// fingerprint hashes the config fields that decide what is generated and
+// written, leaving out how it is loaded, so a run can be resumed with
+// other batch sizes or workers but not other data
+func fingerprint(c *Config) string {
+	data := *c
+	data.RandomSeed, data.AsOf = 0, time.Time{}
+	data.BatchSize, data.CopyWorkers = 0, 0
+	data.DropIndexes, data.UnloggedStaging, data.Analyze = false, false, false
+
+	out, err := yaml.Marshal(&data)
+	if err != nil {
+		// Config has only plain fields, which always marshal
+		panic(err)
+	}
+	sum := sha256.Sum256(out)
+	return hex.EncodeToString(sum[:])
+}
+
+// begin starts recording a new run seeding tables, replacing any earlier
+// run's progress
+func (cp *checkpoints) begin(ctx context.Context, r run, tables []string) error {
+	if _, err := cp.pool.Exec(ctx, createSeedProgress); err != nil {
+		return fmt.Errorf("failed to create seed_progress: %w", err)
+	}
+
+	steps := make([]step, 0, phases+len(tables))
+	for phase := 1; phase <= phases; phase++ {
+		steps = append(steps, step{phase: phase})
+	}
+	for _, table := range tables {
+		steps = append(steps, step{tablePhases[table], table})
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("DELETE FROM seed_progress")
+	clear(cp.steps)
+	for _, st := range steps {
+		cp.steps[st] = &checkpoint{}
+		batch.Queue(`INSERT INTO seed_progress (phase, table_name, random_seed, as_of, fingerprint)
+			VALUES ($1, $2, $3, $4, $5)`, st.phase, st.table, int64(r.seed), r.asOf, r.fingerprint)
+	}
+	if err := cp.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to record seeding run: %w", err)
+	}
+	return nil
+}
+
+// load reads back the progress of the last run, returning its seed,
+// reference time and fingerprint, or errNoRun. It creates seed_progress if
+// it is missing.
+func (cp *checkpoints) load(ctx context.Context) (run, error) {
+	if _, err := cp.pool.Exec(ctx, createSeedProgress); err != nil {
+		return run{}, fmt.Errorf("failed to create seed_progress: %w", err)
+	}
+	return cp.read(ctx)
+}
+
+// lookup is load without any DDL, for reading a database that must not be
+// changed. A missing seed_progress means errNoRun.
+func (cp *checkpoints) lookup(ctx context.Context) (run, error) {
+	var exists bool
+	if err := cp.pool.QueryRow(ctx, "SELECT to_regclass('seed_progress') IS NOT NULL").Scan(&exists); err != nil {
+		return run{}, fmt.Errorf("failed to look up seed_progress: %w", err)
+	}
+	if !exists {
+		return run{}, errNoRun
+	}
+	return cp.read(ctx)
+}
+
+// read loads the recorded steps. The seed is stored in a BIGINT by its bit
+// pattern.
+func (cp *checkpoints) read(ctx context.Context) (run, error) {
+	rows, err := cp.pool.Query(ctx, `
+		SELECT phase, table_name, position, completed, indexes, random_seed, as_of, fingerprint
+		FROM seed_progress`)
+	if err != nil {
+		return run{}, fmt.Errorf("failed to read seed_progress: %w", err)
+	}
+	defer rows.Close()
+
+	var r run
+	clear(cp.steps)
+	for rows.Next() {
+		var st step
+		var position, seed int64
+		var rowRun run
+		c := &checkpoint{}
+		if err := rows.Scan(&st.phase, &st.table, &position, &c.completed, &c.indexes, &seed, &rowRun.asOf, &rowRun.fingerprint); err != nil {
+			return run{}, fmt.Errorf("failed to read seed_progress: %w", err)
+		}
+		c.position = int(position)
+		rowRun.seed = uint64(seed)
+		rowRun.asOf = rowRun.asOf.UTC()
+
+		if r.fingerprint != "" && rowRun != r {
+			return run{}, errors.New("seed_progress mixes runs, seed without resuming to start over")
+		}
+		r = rowRun
+		cp.steps[st] = c
+	}
+	if err := rows.Err(); err != nil {
+		return run{}, fmt.Errorf("failed to read seed_progress: %w", err)
+	}
+	if len(cp.steps) == 0 {
+		return run{}, errNoRun
+	}
+	return r, nil
+}
+
+// tableDone reports whether table was fully written
+func (cp *checkpoints) tableDone(table string) bool {
+	c := cp.steps[step{tablePhases[table], table}]
+	return c != nil && c.completed
+}
+
+// phaseDone reports whether phase was fully written
+func (cp *checkpoints) phaseDone(phase int) bool {
+	c := cp.steps[step{phase: phase}]
+	return c != nil && c.completed
+}
+
+// position returns the orders of phase already written
+func (cp *checkpoints) position(phase int) int {
+	if c := cp.steps[step{phase: phase}]; c != nil {
+		return c.position
+	}
+	return 0
+}
+
+// resumePhase returns the first phase not fully written, or 0 if every
+// phase was
+func (cp *checkpoints) resumePhase() int {
+	for phase := 1; phase <= phases; phase++ {
+		if !cp.phaseDone(phase) {
+			return phase
+		}
+	}
+	return 0
+}
+
+// droppedIndexes returns the index definitions dropped for loading and not
+// yet rebuilt, per table
+func (cp *checkpoints) droppedIndexes() map[string][]string {
+	indexes := make(map[string][]string)
+	for st, c := range cp.steps {
+		if len(c.indexes) > 0 {
+			indexes[st.table] = c.indexes
+		}
+	}
+	return indexes
+}
+
+// saveIndexes records the index definitions dropped for loading, so a
+// resumed run rebuilds them too. nil records that they were rebuilt.
+func (cp *checkpoints) saveIndexes(ctx context.Context, indexes map[string][]string) error {
+	batch := &pgx.Batch{}
+	for st, c := range cp.steps {
+		if st.table == "" {
+			continue
+		}
+		c.indexes = indexes[st.table]
+		batch.Queue(`UPDATE seed_progress SET indexes = $3, updated_at = NOW()
+			WHERE phase = $1 AND table_name = $2`, st.phase, st.table, c.indexes)
+	}
+	if err := cp.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to record dropped indexes: %w", err)
+	}
+	return nil
+}
+
+// advance records that the orders of phase up to position were written
+func (cp *checkpoints) advance(ctx context.Context, phase, position int) error {
+	return cp.update(ctx, step{phase: phase}, position, false)
+}
+
+// completeTable records that table was fully written, with rows rows
+func (cp *checkpoints) completeTable(ctx context.Context, table string, rows int) error {
+	return cp.update(ctx, step{tablePhases[table], table}, rows, true)
+}
+
+// completePhase records that phase was fully written, along with the tables
+// it wrote that are not recorded complete yet
+func (cp *checkpoints) completePhase(ctx context.Context, phase int) error {
+	for st, c := range cp.steps {
+		if st.phase == phase && st.table != "" && !c.completed {
+			if err := cp.update(ctx, st, c.position, true); err != nil {
+				return err
+			}
+		}
+	}
+	return cp.update(ctx, step{phase: phase}, cp.position(phase), true)
+}
+
+func (cp *checkpoints) update(ctx context.Context, st step, position int, completed bool) error {
+	c := cp.steps[st]
+	if c == nil {
+		return nil
+	}
+	c.position, c.completed = position, completed
+	if _, err := cp.pool.Exec(ctx, `UPDATE seed_progress SET position = $3, completed = $4, updated_at = NOW()
+		WHERE phase = $1 AND table_name = $2`, st.phase, st.table, position, completed); err != nil {
+		return fmt.Errorf("failed to record seeding progress: %w", err)
+	}
+	return nil
+}
@@ -1,39 +1,86 @@
 package seeder
 
-// Config holds the target counts for seeding
+import (
+	"bananas/internal/seeder/generators"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultAsOf is when seeded runs generate data as of unless Config.AsOf
+// says otherwise, fixed so a seed alone reproduces a dataset
+var DefaultAsOf = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Config holds the target counts for seeding. Profiles set every field, and
+// YAML profile files use the yaml names.
 type Config struct {
+	// Reproducibility: the same RandomSeed, AsOf and counts give the same
+	// rows. A zero RandomSeed picks one at random, which the seeder logs,
+	// or with Tables is the seed of the last run in seed_progress. A zero
+	// AsOf is DefaultAsOf for seeded runs, the last run's with Tables and
+	// no seed, and the current time otherwise.
+	RandomSeed uint64    `yaml:"random_seed"`
+	AsOf       time.Time `yaml:"as_of"`
+
+	// Tables limits seeding to the named tables, all of them when empty.
+	// The tables they reference are still generated, for their IDs, so the
+	// database must already hold them from the same seed and profile
+	// unless they are listed too. Without a RandomSeed, the seed and AsOf
+	// recorded in seed_progress by the last run are used, and seeding
+	// fails if there is none.
+	Tables []string `yaml:"tables"`
+
 	// Master data
-	Categories int
-	Suppliers  int
-	Warehouses int
+	Categories int `yaml:"categories"`
+	Suppliers  int `yaml:"suppliers"`
+	Warehouses int `yaml:"warehouses"`
 
 	// Products and relationships
-	Products                int
-	ProductCategoriesPerProduct int // avg categories per product
-	SupplierProductsPerProduct  int // avg suppliers per product
-	ProductPricesPerProduct     int // price history records
-	ProductCostsPerProduct      int // cost history records
+	Products                    int `yaml:"products"`
+	ProductCategoriesPerProduct int `yaml:"product_categories_per_product"` // avg categories per product
+	SupplierProductsPerProduct  int `yaml:"supplier_products_per_product"`  // avg suppliers per product
+	ProductPricesPerProduct     int `yaml:"product_prices_per_product"`     // price history records
+	ProductCostsPerProduct      int `yaml:"product_costs_per_product"`      // cost history records
 
 	// Customers
-	Customers              int
-	CustomerAddressesPerCustomer int // avg addresses per customer
+	Customers                    int `yaml:"customers"`
+	CustomerAddressesPerCustomer int `yaml:"customer_addresses_per_customer"` // avg addresses per customer
 
 	// Sales
-	SalesOrders           int
-	SalesOrderItemsPerOrder int // avg items per order
-	SalesOrderPaymentsPerOrder int // avg payments per order (some split payments)
+	SalesOrders                int `yaml:"sales_orders"`
+	SalesOrderItemsPerOrder    int `yaml:"sales_order_items_per_order"`    // avg items per order
+	SalesOrderPaymentsPerOrder int `yaml:"sales_order_payments_per_order"` // avg payments per order (some split payments)
 
 	// Purchasing
-	PurchaseOrders            int
-	PurchaseOrderItemsPerOrder int // avg items per order
-	PurchaseOrderReceiptsPerItem int // avg receipts per item (partial deliveries)
+	PurchaseOrders               int `yaml:"purchase_orders"`
+	PurchaseOrderItemsPerOrder   int `yaml:"purchase_order_items_per_order"`   // avg items per order
+	PurchaseOrderReceiptsPerItem int `yaml:"purchase_order_receipts_per_item"` // avg receipts per item (partial deliveries)
+
+	// Distributions: how sales orders pick products, customers, dates and
+	// item counts. Zero settings pick uniformly.
+	ProductZipf         float64                 `yaml:"product_zipf"`           // Zipf exponent of product popularity
+	CustomerZipf        float64                 `yaml:"customer_zipf"`          // Zipf exponent of orders per customer
+	OrderSeasonality    *generators.Seasonality `yaml:"order_seasonality"`      // order_date volume by weekday and holiday, nil for flat
+	SalesOrderItemsTail float64                 `yaml:"sales_order_items_tail"` // Pareto shape of items per sales order, above 1, lower for a longer tail
 
 	// Inventory
-	InventoryRecordsPerProduct   int // avg warehouses per product
-	InventoryTransactionsPerProduct int // transaction history
+	InventoryRecordsPerProduct      int `yaml:"inventory_records_per_product"`      // avg warehouses per product
+	InventoryTransactionsPerProduct int `yaml:"inventory_transactions_per_product"` // transaction history
+	// InventoryFromLedger derives inventory_transactions from shipped sales
+	// order items and purchase order receipts, and inventory quantities
+	// from their balance, instead of generating both at random.
+	// InventoryTransactionsPerProduct is unused then.
+	InventoryFromLedger bool `yaml:"inventory_from_ledger"`
 
 	// Batch sizes for insertion
-	BatchSize int
+	BatchSize int `yaml:"batch_size"`
+
+	// Loading
+	CopyWorkers     int  `yaml:"copy_workers"`     // pool connections COPY batches fan out to, 1 for serial
+	DropIndexes     bool `yaml:"drop_indexes"`     // drop secondary indexes before loading, rebuild after
+	UnloggedStaging bool `yaml:"unlogged_staging"` // load into UNLOGGED tables, set LOGGED once loaded
+	Analyze         bool `yaml:"analyze"`          // ANALYZE every table once loaded
 }
 
 // DefaultConfig returns the default seeding configuration
@@ -47,10 +94,10 @@ func DefaultConfig() *Config {
 
 		// Products: 500,000 products
 		Products:                    500_000,
-		ProductCategoriesPerProduct: 2,      // 1-3 categories
-		SupplierProductsPerProduct:  2,      // 1-5 suppliers
-		ProductPricesPerProduct:     3,      // 3 price history records
-		ProductCostsPerProduct:      3,      // 3 cost history records
+		ProductCategoriesPerProduct: 2, // 1-3 categories
+		SupplierProductsPerProduct:  2, // 1-5 suppliers
+		ProductPricesPerProduct:     3, // 3 price history records
+		ProductCostsPerProduct:      3, // 3 cost history records
 
 		// Customers: 2,000,000 customers
 		Customers:                    2_000_000,
@@ -58,8 +105,15 @@ func DefaultConfig() *Config {
 
 		// Sales: 20,000,000 orders with ~60M line items
 		SalesOrders:                20_000_000,
-		SalesOrderItemsPerOrder:    3,  // 1-10 items
-		SalesOrderPaymentsPerOrder: 1,  // 1-2 payments
+		SalesOrderItemsPerOrder:    3, // avg, long tail up to 500
+		SalesOrderPaymentsPerOrder: 1, // 1-2 payments
+
+		// Skew: hot products, repeat customers, holiday peaks and the odd
+		// huge basket
+		ProductZipf:         1.0,
+		CustomerZipf:        0.8,
+		OrderSeasonality:    generators.RetailSeasonality(),
+		SalesOrderItemsTail: 2.5,
 
 		// Purchasing: 100,000 orders with ~5M line items
 		PurchaseOrders:               100_000,
@@ -69,9 +123,14 @@ func DefaultConfig() *Config {
 		// Inventory: full coverage
 		InventoryRecordsPerProduct:      2,  // 1-3 warehouses
 		InventoryTransactionsPerProduct: 10, // transaction history
+		InventoryFromLedger:             true,
 
 		// Performance tuning
-		BatchSize: 5000,
+		BatchSize:       5000,
+		CopyWorkers:     4,
+		DropIndexes:     true,
+		UnloggedStaging: false,
+		Analyze:         true,
 	}
 }
 
@@ -95,37 +154,250 @@ func SmallConfig() *Config {
 		SalesOrderItemsPerOrder:    3,
 		SalesOrderPaymentsPerOrder: 1,
 
+		ProductZipf:         1.0,
+		CustomerZipf:        0.8,
+		OrderSeasonality:    generators.RetailSeasonality(),
+		SalesOrderItemsTail: 2.5,
+
 		PurchaseOrders:               1_000,
 		PurchaseOrderItemsPerOrder:   50,
 		PurchaseOrderReceiptsPerItem: 1,
 
 		InventoryRecordsPerProduct:      2,
 		InventoryTransactionsPerProduct: 5,
+		InventoryFromLedger:             true,
+
+		BatchSize:   1000,
+		CopyWorkers: 2,
+		Analyze:     true,
+	}
+}
+
+// TinyConfig returns a config that seeds in seconds, for smoke tests
+func TinyConfig() *Config {
+	c := SmallConfig()
+	c.Categories = 20
+	c.Suppliers = 20
+	c.Warehouses = 5
+	c.Products = 200
+	c.Customers = 500
+	c.SalesOrders = 2_000
+	c.PurchaseOrders = 50
+	c.PurchaseOrderItemsPerOrder = 20
+	c.BatchSize = 500
+	c.CopyWorkers = 1
+	return c
+}
+
+// MediumConfig returns a config a tenth the size of DefaultConfig
+// Targets: ~50K products, ~200K customers, ~2M sales orders
+func MediumConfig() *Config {
+	c := DefaultConfig()
+	c.Categories = 500
+	c.Suppliers = 2_000
+	c.Warehouses = 200
+	c.Products = 50_000
+	c.Customers = 200_000
+	c.SalesOrders = 2_000_000
+	c.PurchaseOrders = 20_000
+	return c
+}
+
+// HugeConfig returns a config five times the size of DefaultConfig, loaded
+// UNLOGGED with more workers
+// Targets: ~2.5M products, ~10M customers, ~100M sales orders
+func HugeConfig() *Config {
+	c := DefaultConfig()
+	c.Categories = 5_000
+	c.Suppliers = 25_000
+	c.Warehouses = 2_500
+	c.Products = 2_500_000
+	c.Customers = 10_000_000
+	c.SalesOrders = 100_000_000
+	c.PurchaseOrders = 500_000
+	c.BatchSize = 10_000
+	c.CopyWorkers = 8
+	c.UnloggedStaging = true
+	return c
+}
+
+// Validate checks the config can be seeded: counts are not negative, every
+// table orders and links draw from has rows, and Tables names seeded tables
+func (c *Config) Validate() error {
+	counts := []struct {
+		name  string
+		value int
+		min   int
+	}{
+		{"categories", c.Categories, 1},
+		{"suppliers", c.Suppliers, 1},
+		{"warehouses", c.Warehouses, 1},
+		{"products", c.Products, 1},
+		{"product_categories_per_product", c.ProductCategoriesPerProduct, 0},
+		{"supplier_products_per_product", c.SupplierProductsPerProduct, 0},
+		{"product_prices_per_product", c.ProductPricesPerProduct, 1},
+		{"product_costs_per_product", c.ProductCostsPerProduct, 1},
+		{"customers", c.Customers, 1},
+		{"customer_addresses_per_customer", c.CustomerAddressesPerCustomer, 0},
+		{"sales_orders", c.SalesOrders, 0},
+		{"sales_order_items_per_order", c.SalesOrderItemsPerOrder, 1},
+		{"sales_order_payments_per_order", c.SalesOrderPaymentsPerOrder, 0},
+		{"purchase_orders", c.PurchaseOrders, 0},
+		{"purchase_order_items_per_order", c.PurchaseOrderItemsPerOrder, 1},
+		{"purchase_order_receipts_per_item", c.PurchaseOrderReceiptsPerItem, 0},
+		{"inventory_records_per_product", c.InventoryRecordsPerProduct, 0},
+		{"inventory_transactions_per_product", c.InventoryTransactionsPerProduct, 0},
+		{"batch_size", c.BatchSize, 1},
+		{"copy_workers", c.CopyWorkers, 0},
+	}
+	for _, count := range counts {
+		if count.value < count.min {
+			return fmt.Errorf("%s must be at least %d, got %d", count.name, count.min, count.value)
+		}
+	}
+
+	if c.ProductZipf < 0 || c.CustomerZipf < 0 {
+		return fmt.Errorf("zipf exponents must not be negative, got product %g and customer %g", c.ProductZipf, c.CustomerZipf)
+	}
+	if c.SalesOrderItemsTail != 0 && c.SalesOrderItemsTail <= 1 {
+		return fmt.Errorf("sales_order_items_tail must be above 1 for a finite mean, or 0 for uniform, got %g", c.SalesOrderItemsTail)
+	}
+	if err := validateSeasonality(c.OrderSeasonality); err != nil {
+		return fmt.Errorf("order_seasonality: %w", err)
+	}
 
-		BatchSize: 1000,
+	for _, table := range c.Tables {
+		if !slices.Contains(Tables, table) {
+			return fmt.Errorf("unknown table %q (seeded tables: %s)", table, strings.Join(Tables, ", "))
+		}
 	}
+	return nil
+}
+
+func validateSeasonality(s *generators.Seasonality) error {
+	if s == nil {
+		return nil
+	}
+	for day, weight := range s.Weekday {
+		if weight < 0 {
+			return fmt.Errorf("%s weight must not be negative, got %g", time.Weekday(day), weight)
+		}
+	}
+	for _, h := range s.Holidays {
+		if h.Month < time.January || h.Month > time.December || h.Day < 1 || h.Day > 31 {
+			return fmt.Errorf("holiday %q has no date %d/%d", h.Name, h.Month, h.Day)
+		}
+		if h.Days < 1 || h.Weight < 0 {
+			return fmt.Errorf("holiday %q needs at least 1 day and a weight of at least 0, got %d and %g", h.Name, h.Days, h.Weight)
+		}
+	}
+	return nil
+}
+
+// writes reports whether table is seeded, rather than only generated for
+// the tables after it
+func (c *Config) writes(table string) bool {
+	return len(c.Tables) == 0 || slices.Contains(c.Tables, table)
+}
+
+// TableEstimate is the estimated size of one seeded table
+type TableEstimate struct {
+	Table string
+	Rows  int
+	Bytes int64 // on disk, heap and indexes
+}
+
+// rowBytes estimates the on-disk bytes per row of each table: the tuple
+// header and typical column widths, plus an entry in each of its indexes.
+// They are rough averages of freshly loaded tables, with text columns at the
+// lengths the generators produce.
+var rowBytes = map[string]int64{
+	"categories":              290,
+	"suppliers":               270,
+	"warehouses":              280,
+	"products":                420,
+	"product_categories":      230,
+	"product_prices":          190,
+	"product_costs":           190,
+	"supplier_products":       270,
+	"customers":               250,
+	"customer_addresses":      240,
+	"sales_orders":            440,
+	"sales_order_items":       225,
+	"sales_order_payments":    240,
+	"purchase_orders":         460,
+	"purchase_order_items":    220,
+	"purchase_order_receipts": 300,
+	"inventory":               250,
+	"inventory_transactions":  310,
+}
+
+// TableEstimates returns the estimated rows and disk size of each table
+// seeded, in Tables order
+func (c *Config) TableEstimates() []TableEstimate {
+	rows := map[string]int{
+		"categories":              c.Categories,
+		"suppliers":               c.Suppliers,
+		"warehouses":              c.Warehouses,
+		"products":                c.Products,
+		"product_categories":      c.Products * c.ProductCategoriesPerProduct,
+		"product_prices":          c.Products * c.ProductPricesPerProduct,
+		"product_costs":           c.Products * c.ProductCostsPerProduct,
+		"supplier_products":       c.Products * c.SupplierProductsPerProduct,
+		"customers":               c.Customers,
+		"customer_addresses":      c.Customers * c.CustomerAddressesPerCustomer,
+		"sales_orders":            c.SalesOrders,
+		"sales_order_items":       c.SalesOrders * c.SalesOrderItemsPerOrder,
+		"sales_order_payments":    c.SalesOrders * c.SalesOrderPaymentsPerOrder,
+		"purchase_orders":         c.PurchaseOrders,
+		"purchase_order_items":    c.PurchaseOrders * c.PurchaseOrderItemsPerOrder,
+		"purchase_order_receipts": c.PurchaseOrders * c.PurchaseOrderItemsPerOrder * c.PurchaseOrderReceiptsPerItem,
+		"inventory":               c.Products * c.InventoryRecordsPerProduct,
+		"inventory_transactions":  c.inventoryTransactionsEstimate(),
+	}
+
+	estimates := make([]TableEstimate, 0, len(Tables))
+	for _, table := range Tables {
+		if !c.writes(table) {
+			continue
+		}
+		estimates = append(estimates, TableEstimate{
+			Table: table,
+			Rows:  rows[table],
+			Bytes: int64(rows[table]) * rowBytes[table],
+		})
+	}
+	return estimates
 }
 
 // TotalRecordsEstimate returns the estimated total number of records
 func (c *Config) TotalRecordsEstimate() int {
 	total := 0
-	total += c.Categories
-	total += c.Suppliers
-	total += c.Warehouses
-	total += c.Products
-	total += c.Products * c.ProductCategoriesPerProduct
-	total += c.Products * c.SupplierProductsPerProduct
-	total += c.Products * c.ProductPricesPerProduct
-	total += c.Products * c.ProductCostsPerProduct
-	total += c.Customers
-	total += c.Customers * c.CustomerAddressesPerCustomer
-	total += c.SalesOrders
-	total += c.SalesOrders * c.SalesOrderItemsPerOrder
-	total += c.SalesOrders * c.SalesOrderPaymentsPerOrder
-	total += c.PurchaseOrders
-	total += c.PurchaseOrders * c.PurchaseOrderItemsPerOrder
-	total += c.PurchaseOrders * c.PurchaseOrderItemsPerOrder * c.PurchaseOrderReceiptsPerItem
-	total += c.Products * c.InventoryRecordsPerProduct
-	total += c.Products * c.InventoryTransactionsPerProduct
+	for _, e := range c.TableEstimates() {
+		total += e.Rows
+	}
+	return total
+}
+
+// DiskSizeEstimate returns the estimated bytes the seeded tables take on
+// disk, heap and indexes
+func (c *Config) DiskSizeEstimate() int64 {
+	var total int64
+	for _, e := range c.TableEstimates() {
+		total += e.Bytes
+	}
 	return total
 }
+
+// inventoryTransactionsEstimate returns the estimated inventory transaction
+// count: random history, or with InventoryFromLedger an opening balance per
+// inventory record, a sale per shipped item (a third of orders ship) and a
+// purchase per receipt
+func (c *Config) inventoryTransactionsEstimate() int {
+	if !c.InventoryFromLedger {
+		return c.Products * c.InventoryTransactionsPerProduct
+	}
+	return c.Products*c.InventoryRecordsPerProduct +
+		c.SalesOrders*c.SalesOrderItemsPerOrder/3 +
+		c.PurchaseOrders*c.PurchaseOrderItemsPerOrder*c.PurchaseOrderReceiptsPerItem
+}
@@ -0,0 +1,87 @@
+package seeder
+
+import (
+	"bananas/internal/seeder/generators"
+	"testing"
+	"time"
+)
+
+func TestProfilesValidate(t *testing.T) {
+	for _, profile := range Profiles {
+		t.Run(profile, func(t *testing.T) {
+			cfg, err := LoadProfile(profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(*Config)
+		wantErr bool
+	}{
+		{name: "tiny", edit: func(*Config) {}},
+		{name: "known tables", edit: func(c *Config) { c.Tables = []string{"customers", "sales_orders"} }},
+		{name: "unknown table", edit: func(c *Config) { c.Tables = []string{"users"} }, wantErr: true},
+		{name: "no products", edit: func(c *Config) { c.Products = 0 }, wantErr: true},
+		{name: "no sales orders", edit: func(c *Config) { c.SalesOrders = 0 }},
+		{name: "negative orders", edit: func(c *Config) { c.PurchaseOrders = -1 }, wantErr: true},
+		{name: "zero batch size", edit: func(c *Config) { c.BatchSize = 0 }, wantErr: true},
+		{name: "negative zipf", edit: func(c *Config) { c.ProductZipf = -1 }, wantErr: true},
+		{name: "tail of 1", edit: func(c *Config) { c.SalesOrderItemsTail = 1 }, wantErr: true},
+		{name: "tail above 1", edit: func(c *Config) { c.SalesOrderItemsTail = 1.2 }},
+		{name: "retail seasonality", edit: func(c *Config) { c.OrderSeasonality = generators.RetailSeasonality() }},
+		{
+			name: "holiday without a date",
+			edit: func(c *Config) {
+				c.OrderSeasonality = &generators.Seasonality{Holidays: []generators.Holiday{{Name: "x", Month: 2, Day: 0, Days: 1}}}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := TinyConfig()
+			tt.edit(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := fingerprint(TinyConfig())
+
+	tests := []struct {
+		name string
+		edit func(*Config)
+		same bool
+	}{
+		{name: "seed", edit: func(c *Config) { c.RandomSeed = 99 }, same: true},
+		{name: "as of", edit: func(c *Config) { c.AsOf = time.Now() }, same: true},
+		{name: "batch size", edit: func(c *Config) { c.BatchSize *= 2 }, same: true},
+		{name: "loading", edit: func(c *Config) { c.CopyWorkers, c.DropIndexes, c.UnloggedStaging, c.Analyze = 8, true, true, true }, same: true},
+		{name: "sales orders", edit: func(c *Config) { c.SalesOrders++ }},
+		{name: "tables", edit: func(c *Config) { c.Tables = []string{"customers"} }},
+		{name: "ledger", edit: func(c *Config) { c.InventoryFromLedger = !c.InventoryFromLedger }},
+		{name: "zipf", edit: func(c *Config) { c.ProductZipf = 1.1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := TinyConfig()
+			tt.edit(cfg)
+			if same := fingerprint(cfg) == base; same != tt.same {
+				t.Errorf("same fingerprint = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
@@ -2,77 +2,78 @@ package generators
 
 import (
 	"fmt"
-	"time"
-
-	"github.com/brianvoe/gofakeit/v7"
-	"github.com/google/uuid"
+	"iter"
 )
 
-// GenerateCustomers creates customer data
-func GenerateCustomers(count int, idMap *IDMap) [][]interface{} {
-	rows := make([][]interface{}, 0, count)
-	now := time.Now()
+// GenerateCustomers streams customer data
+func GenerateCustomers(src *Source, count int, idMap *IDMap) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("customers", 0)
+		now := rng.Now
 
-	for i := 0; i < count; i++ {
-		id := uuid.New()
-		idMap.CustomerIDs = append(idMap.CustomerIDs, id)
+		for i := 0; i < count; i++ {
+			id := rng.ID()
+			idMap.CustomerIDs = append(idMap.CustomerIDs, id)
 
-		person := gofakeit.Person()
-		phone := person.Contact.Phone
+			person := rng.Person()
+			phone := person.Contact.Phone
 
-		rows = append(rows, []interface{}{
-			id,                   // id
-			person.FirstName,     // first_name
-			person.LastName,      // last_name
-			person.Contact.Email, // email
-			&phone,               // phone
-			now,                  // created_at
-			now,                  // updated_at
-			nil,                  // deleted_at
-		})
+			if !yield([]interface{}{
+				id,                   // id
+				person.FirstName,     // first_name
+				person.LastName,      // last_name
+				person.Contact.Email, // email
+				&phone,               // phone
+				now,                  // created_at
+				now,                  // updated_at
+				nil,                  // deleted_at
+			}) {
+				return
+			}
+		}
 	}
-
-	return rows
 }
 
-// GenerateCustomerAddresses creates customer addresses
-func GenerateCustomerAddresses(idMap *IDMap, addressesPerCustomer int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.CustomerIDs)*addressesPerCustomer)
-	now := time.Now()
+// GenerateCustomerAddresses streams customer addresses
+func GenerateCustomerAddresses(src *Source, idMap *IDMap, addressesPerCustomer int) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("customer_addresses", 0)
+		now := rng.Now
 
-	addressTypes := []string{"billing", "shipping", "both"}
+		addressTypes := []string{"billing", "shipping", "both"}
 
-	for _, customerID := range idMap.CustomerIDs {
-		// Generate 1-3 addresses per customer
-		numAddresses := gofakeit.Number(1, addressesPerCustomer+1)
+		for _, customerID := range idMap.CustomerIDs {
+			// Generate 1-3 addresses per customer
+			numAddresses := rng.Number(1, addressesPerCustomer+1)
 
-		for j := 0; j < numAddresses; j++ {
-			addr := gofakeit.Address()
-			addressType := addressTypes[gofakeit.Number(0, len(addressTypes)-1)]
-			isDefault := j == 0 // First address is default
-			addressLine2 := ""
-			if gofakeit.Bool() {
-				addressLine2 = fmt.Sprintf("Apt %d", gofakeit.Number(1, 999))
-			}
+			for j := 0; j < numAddresses; j++ {
+				addr := rng.Address()
+				addressType := addressTypes[rng.Number(0, len(addressTypes)-1)]
+				isDefault := j == 0 // First address is default
+				addressLine2 := ""
+				if rng.Bool() {
+					addressLine2 = fmt.Sprintf("Apt %d", rng.Number(1, 999))
+				}
 
-			rows = append(rows, []interface{}{
-				uuid.New(),       // id
-				customerID,       // customer_id
-				addressType,      // address_type
-				addr.Address,     // address_line1
-				&addressLine2,    // address_line2
-				addr.City,        // city
-				addr.State,       // state
-				addr.Zip,         // postal_code
-				addr.Country,     // country
-				isDefault,        // is_default
-				now,              // created_at
-				now,              // updated_at
-			})
+				if !yield([]interface{}{
+					rng.ID(),      // id
+					customerID,    // customer_id
+					addressType,   // address_type
+					addr.Address,  // address_line1
+					&addressLine2, // address_line2
+					addr.City,     // city
+					addr.State,    // state
+					addr.Zip,      // postal_code
+					addr.Country,  // country
+					isDefault,     // is_default
+					now,           // created_at
+					now,           // updated_at
+				}) {
+					return
+				}
+			}
 		}
 	}
-
-	return rows
 }
 
 // Customer-related column functions
@@ -0,0 +1,233 @@
+package generators
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Distributions shapes the sales orders generated: how popular each product
+// and customer is, which days orders fall on and how many items they have.
+// Real workloads have hot products, heavy customers and seasonal peaks, and
+// those are what stress indexes and caches. Without one in IDMap, or for a
+// zero setting, the choice is uniform.
+type Distributions struct {
+	products  *zipf     // nil for uniform
+	customers *zipf     // nil for uniform
+	days      []float64 // cumulative order weight of each day back from now, nil for uniform
+	itemsTail float64   // Pareto shape of items per order, 0 for uniform
+}
+
+// Seasonality is the relative order volume through the year
+type Seasonality struct {
+	// Weekday weights order volume by day of week, Sunday first. All zero
+	// is flat.
+	Weekday [7]float64 `yaml:"weekday"`
+
+	// Holidays multiply the volume around fixed dates. Overlapping
+	// holidays multiply together.
+	Holidays []Holiday `yaml:"holidays"`
+}
+
+// Holiday is a peak (or dip) in order volume over the days leading up to
+// and including a date, every year
+type Holiday struct {
+	Name   string     `yaml:"name"`
+	Month  time.Month `yaml:"month"`
+	Day    int        `yaml:"day"`
+	Days   int        `yaml:"days"`   // days the peak lasts, 1 for the date alone
+	Weight float64    `yaml:"weight"` // volume multiplier on those days
+}
+
+// RetailSeasonality returns a typical online retail curve: busiest early in
+// the week, quiet at weekends, peaking from Black Friday through Cyber
+// Monday and in the run up to Christmas, and dead on Christmas Day
+func RetailSeasonality() *Seasonality {
+	return &Seasonality{
+		Weekday: [7]float64{0.9, 1.15, 1.1, 1.05, 1.0, 0.95, 0.85},
+		Holidays: []Holiday{
+			{Name: "Valentine's Day", Month: time.February, Day: 14, Days: 7, Weight: 1.3},
+			{Name: "Mother's Day", Month: time.May, Day: 10, Days: 7, Weight: 1.2},
+			{Name: "Back to school", Month: time.August, Day: 31, Days: 21, Weight: 1.25},
+			{Name: "Black Friday to Cyber Monday", Month: time.December, Day: 1, Days: 5, Weight: 3},
+			{Name: "Christmas shopping", Month: time.December, Day: 23, Days: 21, Weight: 1.8},
+			{Name: "Christmas Day", Month: time.December, Day: 25, Days: 1, Weight: 0.3},
+		},
+	}
+}
+
+// weight returns the relative order volume on date
+func (s *Seasonality) weight(date time.Time) float64 {
+	w := s.Weekday[date.Weekday()]
+	if s.Weekday == [7]float64{} {
+		w = 1
+	}
+
+	year, month, day := date.Date()
+	date = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for _, h := range s.Holidays {
+		end := time.Date(year, h.Month, h.Day, 0, 0, 0, 0, time.UTC)
+		if end.Before(date) {
+			// The date may fall in a peak ending early next year
+			end = end.AddDate(1, 0, 0)
+		}
+		if end.Sub(date) < time.Duration(max(h.Days, 1))*24*time.Hour {
+			w *= h.Weight
+		}
+	}
+	return w
+}
+
+// NewDistributions creates the distributions for the products and customers
+// in idMap. productZipf and customerZipf are Zipf exponents of popularity,
+// where 1 means the second most popular is picked half as often as the
+// first; seasonality weights order dates; itemsTail is the Pareto shape of
+// items per sales order, above 1, lower for a longer tail.
+func NewDistributions(src *Source, idMap *IDMap, productZipf, customerZipf float64, seasonality *Seasonality, itemsTail float64) *Distributions {
+	d := &Distributions{itemsTail: itemsTail}
+	if productZipf > 0 {
+		d.products = newZipf(len(idMap.ProductIDs), productZipf)
+	}
+	if customerZipf > 0 {
+		d.customers = newZipf(len(idMap.CustomerIDs), customerZipf)
+	}
+
+	if seasonality != nil {
+		d.days = make([]float64, orderWindowDays+1)
+		total := 0.0
+		for day := range d.days {
+			total += seasonality.weight(src.Now.AddDate(0, 0, -day))
+			d.days[day] = total
+		}
+	}
+	return d
+}
+
+// product returns the index of the product an order item picks
+func (d *Distributions) product(n int, rng *Source) int {
+	if d == nil || d.products == nil {
+		return rng.Number(0, n-1)
+	}
+	return d.products.sample(rng)
+}
+
+// customer returns the index of the customer placing an order
+func (d *Distributions) customer(n int, rng *Source) int {
+	if d == nil || d.customers == nil {
+		return rng.Number(0, n-1)
+	}
+	return d.customers.sample(rng)
+}
+
+// orderDate returns the date of an order in the order window
+func (d *Distributions) orderDate(rng *Source) time.Time {
+	if d == nil || d.days == nil {
+		return rng.Now.AddDate(0, 0, -rng.Number(0, orderWindowDays))
+	}
+
+	u := rng.Float64() * d.days[len(d.days)-1]
+	day := sort.Search(len(d.days), func(i int) bool { return d.days[i] > u })
+	return rng.Now.AddDate(0, 0, -min(day, orderWindowDays))
+}
+
+// maxSalesOrderItems caps the long tail of items per sales order
+const maxSalesOrderItems = 500
+
+// salesOrderItems returns how many items a sales order has: 1 to
+// itemsPerOrder+7 uniformly, or with a tail a shifted Pareto (Lomax)
+// count averaging itemsPerOrder, mostly one or two items with the odd
+// order of hundreds
+func (d *Distributions) salesOrderItems(itemsPerOrder int, rng *Source) int {
+	if d == nil || d.itemsTail <= 1 {
+		return rng.Number(1, itemsPerOrder+7)
+	}
+
+	// A Lomax of shape a and scale l averages l/(a-1), and flooring takes
+	// about half an item off that
+	scale := (float64(itemsPerOrder) - 0.5) * (d.itemsTail - 1)
+	x := scale * (math.Pow(1-rng.Float64(), -1/d.itemsTail) - 1)
+	return 1 + int(min(x, maxSalesOrderItems-1))
+}
+
+// zipf samples ranks 1 to n with probability proportional to 1/rank^s, for
+// any s > 0, by rejection-inversion (Hörmann and Derflinger, "Rejection-
+// inversion to generate variates from monotone discrete distributions",
+// 1996), in constant time and memory however large n is. Ranks are
+// scattered over the indexes, so the most popular rows are not all the
+// first ones written.
+type zipf struct {
+	n      int
+	s      float64
+	hX1    float64 // hIntegral(1.5) - 1
+	hN     float64 // hIntegral(n + 0.5)
+	sv     float64 // squeeze: ranks this close above x are accepted at once
+	stride int     // coprime to n, so rank*stride mod n is a permutation
+}
+
+func newZipf(n int, s float64) *zipf {
+	z := &zipf{n: n, s: s}
+	z.hX1 = z.hIntegral(1.5) - 1
+	z.hN = z.hIntegral(float64(n) + 0.5)
+	z.sv = 2 - z.hIntegralInverse(z.hIntegral(2.5)-z.h(2))
+
+	// A stride near n over the golden ratio spreads neighbouring ranks
+	// far apart
+	z.stride = max(int(float64(n)*0.6180339887), 1)
+	for gcd(z.stride, n) != 1 {
+		z.stride++
+	}
+	return z
+}
+
+// sample returns the index of a row, 0 to n-1
+func (z *zipf) sample(rng *Source) int {
+	for {
+		u := z.hN + rng.Float64()*(z.hX1-z.hN)
+		x := z.hIntegralInverse(u)
+		k := min(max(int(x+0.5), 1), z.n)
+
+		if float64(k)-x <= z.sv || u >= z.hIntegral(float64(k)+0.5)-z.h(float64(k)) {
+			return int(int64(k-1) * int64(z.stride) % int64(z.n))
+		}
+	}
+}
+
+// h is the unnormalized density 1/x^s
+func (z *zipf) h(x float64) float64 {
+	return math.Exp(-z.s * math.Log(x))
+}
+
+// hIntegral is an antiderivative of h, (x^(1-s) - 1) / (1-s), stable at s = 1
+func (z *zipf) hIntegral(x float64) float64 {
+	logX := math.Log(x)
+	return helperExpm1((1-z.s)*logX) * logX
+}
+
+// hIntegralInverse inverts hIntegral
+func (z *zipf) hIntegralInverse(x float64) float64 {
+	t := max(x*(1-z.s), -1)
+	return math.Exp(helperLog1p(t) * x)
+}
+
+// helperLog1p is log(1+x)/x, continued to 1 at 0
+func helperLog1p(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Log1p(x) / x
+	}
+	return 1 - x*(0.5-x*(1.0/3-0.25*x))
+}
+
+// helperExpm1 is (exp(x)-1)/x, continued to 1 at 0
+func helperExpm1(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Expm1(x) / x
+	}
+	return 1 + x*0.5*(1+x/3*(1+0.25*x))
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
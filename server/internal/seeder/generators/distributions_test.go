@@ -0,0 +1,107 @@
+package generators
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestZipfStridePermutes(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 10, 97, 100, 1000, 1024, 4096} {
+		z := newZipf(n, 1)
+		if gcd(z.stride, n) != 1 {
+			t.Errorf("n=%d: stride %d is not coprime to n", n, z.stride)
+		}
+
+		seen := make([]bool, n)
+		for rank := 0; rank < n; rank++ {
+			index := rank * z.stride % n
+			if seen[index] {
+				t.Fatalf("n=%d: index %d hit twice", n, index)
+			}
+			seen[index] = true
+		}
+	}
+}
+
+func TestZipfFrequencies(t *testing.T) {
+	const n, samples = 100, 400_000
+
+	tests := []struct {
+		name string
+		s    float64
+		rank int     // compared with rank 1
+		tol  float64 // relative tolerance of the frequency ratio
+	}{
+		{name: "s=0.5 rank 2", s: 0.5, rank: 2, tol: 0.05},
+		{name: "s=1 rank 2", s: 1, rank: 2, tol: 0.05},
+		{name: "s=1 rank 10", s: 1, rank: 10, tol: 0.08},
+		{name: "s=1.5 rank 2", s: 1.5, rank: 2, tol: 0.05},
+		{name: "s=1.5 rank 5", s: 1.5, rank: 5, tol: 0.08},
+		{name: "s=2 rank 3", s: 2, rank: 3, tol: 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z := newZipf(n, tt.s)
+			rng := NewSource(1, testNow).Fork("zipf", 0)
+
+			counts := make([]int, n)
+			for range samples {
+				index := z.sample(rng)
+				if index < 0 || index >= n {
+					t.Fatalf("sampled index %d out of [0, %d)", index, n)
+				}
+				counts[index]++
+			}
+
+			first := counts[0]
+			other := counts[(tt.rank-1)*z.stride%n]
+			if other == 0 {
+				t.Fatalf("rank %d never sampled", tt.rank)
+			}
+			got := float64(first) / float64(other)
+			want := math.Pow(float64(tt.rank), tt.s)
+			if math.Abs(got-want)/want > tt.tol {
+				t.Errorf("rank 1 sampled %.3f times as often as rank %d, want %.3f", got, tt.rank, want)
+			}
+		})
+	}
+}
+
+func TestSeasonalityWeight(t *testing.T) {
+	s := &Seasonality{
+		Weekday: [7]float64{0.5, 1, 1, 1, 1, 1, 2},
+		Holidays: []Holiday{
+			{Name: "peak", Month: 12, Day: 25, Days: 3, Weight: 4},
+			{Name: "new year", Month: 1, Day: 2, Days: 5, Weight: 3},
+		},
+	}
+
+	tests := []struct {
+		date string
+		want float64
+	}{
+		{"2024-12-20", 1},       // Friday
+		{"2024-12-21", 2},       // Saturday
+		{"2024-12-22", 0.5},     // Sunday
+		{"2024-12-23", 4},       // Monday, in the peak
+		{"2024-12-25", 4},       // last day of the peak
+		{"2024-12-26", 1},       // after it
+		{"2024-12-29", 0.5 * 3}, // Sunday, in a peak ending next year
+		{"2025-01-02", 3},
+		{"2025-01-03", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := time.Parse(time.DateOnly, tt.date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.weight(date); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("weight = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,102 +1,175 @@
 package generators
 
 import (
-	"time"
+	"iter"
 
-	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 )
 
-// GenerateInventory creates inventory records (product-warehouse combinations)
-func GenerateInventory(idMap *IDMap, warehousesPerProduct int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.ProductIDs)*warehousesPerProduct)
-	now := time.Now()
-
-	for _, productID := range idMap.ProductIDs {
-		// Each product exists in 1-3 warehouses
-		numWarehouses := gofakeit.Number(1, warehousesPerProduct+1)
-		if numWarehouses > len(idMap.WarehouseIDs) {
-			numWarehouses = len(idMap.WarehouseIDs)
-		}
+// GenerateInventory streams inventory records (product-warehouse combinations).
+// With a ledger, there is one per warehouse stocking a product, holding the
+// ledger balance: an opening balance that covers every sale and
+// reservation, plus receipts, minus shipments.
+func GenerateInventory(src *Source, idMap *IDMap, warehousesPerProduct int) iter.Seq[[]interface{}] {
+	if idMap.Ledger != nil {
+		return ledgerInventory(src, idMap)
+	}
 
-		usedWarehouses := make(map[uuid.UUID]bool)
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("inventory", 0)
+		now := rng.Now
 
-		for j := 0; j < numWarehouses; j++ {
-			warehouseID := idMap.WarehouseIDs[gofakeit.Number(0, len(idMap.WarehouseIDs)-1)]
+		for _, productID := range idMap.ProductIDs {
+			// Each product exists in 1-3 warehouses
+			numWarehouses := rng.Number(1, warehousesPerProduct+1)
+			if numWarehouses > len(idMap.WarehouseIDs) {
+				numWarehouses = len(idMap.WarehouseIDs)
+			}
 
-			// Avoid duplicate warehouse assignments
-			if usedWarehouses[warehouseID] {
-				continue
+			usedWarehouses := make(map[uuid.UUID]bool)
+
+			for j := 0; j < numWarehouses; j++ {
+				warehouseID := idMap.WarehouseIDs[rng.Number(0, len(idMap.WarehouseIDs)-1)]
+
+				// Avoid duplicate warehouse assignments
+				if usedWarehouses[warehouseID] {
+					continue
+				}
+				usedWarehouses[warehouseID] = true
+
+				quantity := rng.Number(0, 10000)
+				reservedQuantity := rng.Number(0, quantity/10) // Reserve up to 10%
+				reorderPoint := rng.Number(50, 500)
+				reorderQuantity := rng.Number(100, 1000)
+
+				if !yield([]interface{}{
+					rng.ID(),         // id
+					productID,        // product_id
+					warehouseID,      // warehouse_id
+					quantity,         // quantity
+					reservedQuantity, // reserved_quantity
+					reorderPoint,     // reorder_point
+					reorderQuantity,  // reorder_quantity
+					now,              // created_at
+					now,              // updated_at
+				}) {
+					return
+				}
 			}
-			usedWarehouses[warehouseID] = true
-
-			quantity := gofakeit.Number(0, 10000)
-			reservedQuantity := gofakeit.Number(0, quantity/10) // Reserve up to 10%
-			reorderPoint := gofakeit.Number(50, 500)
-			reorderQuantity := gofakeit.Number(100, 1000)
-
-			rows = append(rows, []interface{}{
-				uuid.New(),        // id
-				productID,         // product_id
-				warehouseID,       // warehouse_id
-				quantity,          // quantity
-				reservedQuantity,  // reserved_quantity
-				reorderPoint,      // reorder_point
-				reorderQuantity,   // reorder_quantity
-				now,               // created_at
-				now,               // updated_at
-			})
 		}
 	}
-
-	return rows
 }
 
-// GenerateInventoryTransactions creates transaction history
-func GenerateInventoryTransactions(idMap *IDMap, transactionsPerProduct int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.ProductIDs)*transactionsPerProduct)
-	now := time.Now()
-
-	transactionTypes := []string{"purchase", "sale", "adjustment", "return", "transfer", "damage"}
-
-	for _, productID := range idMap.ProductIDs {
-		// Random warehouse for transactions
-		warehouseID := idMap.WarehouseIDs[gofakeit.Number(0, len(idMap.WarehouseIDs)-1)]
-
-		for j := 0; j < transactionsPerProduct; j++ {
-			txnType := transactionTypes[gofakeit.Number(0, len(transactionTypes)-1)]
+// GenerateInventoryTransactions streams transaction history. With a ledger,
+// the orders have written the sales and receipts already, and this streams
+// the opening balances GenerateInventory set, dated before the first order.
+func GenerateInventoryTransactions(src *Source, idMap *IDMap, transactionsPerProduct int) iter.Seq[[]interface{}] {
+	if idMap.Ledger != nil {
+		return openingBalances(src, idMap)
+	}
 
-			// Quantity is positive for additions (purchase, return) and negative for subtractions (sale, damage)
-			quantity := gofakeit.Number(1, 100)
-			if txnType == "sale" || txnType == "damage" {
-				quantity = -quantity
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("inventory_transactions", 0)
+		now := rng.Now
+
+		transactionTypes := []string{"purchase", "sale", "adjustment", "return", "transfer", "damage"}
+
+		for _, productID := range idMap.ProductIDs {
+			// Random warehouse for transactions
+			warehouseID := idMap.WarehouseIDs[rng.Number(0, len(idMap.WarehouseIDs)-1)]
+
+			for j := 0; j < transactionsPerProduct; j++ {
+				txnType := transactionTypes[rng.Number(0, len(transactionTypes)-1)]
+
+				// Quantity is positive for additions (purchase, return) and negative for subtractions (sale, damage)
+				quantity := rng.Number(1, 100)
+				if txnType == "sale" || txnType == "damage" {
+					quantity = -quantity
+				}
+
+				// Random reference ID (could be sales order, purchase order, etc.)
+				referenceID := rng.ID()
+				referenceType := "sales_order"
+				if txnType == "purchase" {
+					referenceType = "purchase_order"
+				}
+
+				notes := rng.Sentence(8)
+				createdAt := now.AddDate(0, 0, -rng.Number(0, 365))
+
+				if !yield([]interface{}{
+					rng.ID(),       // id
+					productID,      // product_id
+					warehouseID,    // warehouse_id
+					txnType,        // transaction_type
+					quantity,       // quantity
+					&referenceID,   // reference_id
+					&referenceType, // reference_type
+					&notes,         // notes
+					createdAt,      // created_at
+				}) {
+					return
+				}
 			}
+		}
+	}
+}
 
-			// Random reference ID (could be sales order, purchase order, etc.)
-			referenceID := uuid.New()
-			referenceType := "sales_order"
-			if txnType == "purchase" {
-				referenceType = "purchase_order"
+func ledgerInventory(src *Source, idMap *IDMap) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("inventory", 0)
+		now := rng.Now
+
+		for product, levels := range idMap.Ledger.stock {
+			for i := range levels {
+				stock := &levels[i]
+
+				// Open with enough to cover what was shipped and is
+				// reserved, so the balance never goes negative, plus
+				// some spare
+				stock.opening = stock.out + stock.reserved + int64(rng.Number(0, 500))
+
+				reorderPoint := rng.Number(50, 500)
+				reorderQuantity := rng.Number(100, 1000)
+
+				if !yield([]interface{}{
+					rng.ID(),                            // id
+					idMap.ProductIDs[product],           // product_id
+					idMap.WarehouseIDs[stock.warehouse], // warehouse_id
+					int(stock.quantity()),               // quantity
+					int(stock.reserved),                 // reserved_quantity
+					reorderPoint,                        // reorder_point
+					reorderQuantity,                     // reorder_quantity
+					now,                                 // created_at
+					now,                                 // updated_at
+				}) {
+					return
+				}
 			}
-
-			notes := gofakeit.Sentence(8)
-			createdAt := now.AddDate(0, 0, -gofakeit.Number(0, 365))
-
-			rows = append(rows, []interface{}{
-				uuid.New(),        // id
-				productID,         // product_id
-				warehouseID,       // warehouse_id
-				txnType,           // transaction_type
-				quantity,          // quantity
-				&referenceID,      // reference_id
-				&referenceType,    // reference_type
-				&notes,            // notes
-				createdAt,         // created_at
-			})
 		}
 	}
+}
 
-	return rows
+func openingBalances(src *Source, idMap *IDMap) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("inventory_transactions", 0)
+		openedAt := rng.Now.AddDate(0, 0, -orderWindowDays-1)
+
+		for product, levels := range idMap.Ledger.stock {
+			for _, stock := range levels {
+				if stock.opening == 0 {
+					continue
+				}
+
+				if !yield(inventoryTransactionRow(
+					rng, idMap.ProductIDs[product], idMap.WarehouseIDs[stock.warehouse],
+					"adjustment", stock.opening, nil, nil, "Opening balance", openedAt,
+				)) {
+					return
+				}
+			}
+		}
+	}
 }
 
 // Inventory-related column functions
@@ -0,0 +1,178 @@
+package generators
+
+import (
+	"iter"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ledger tracks the stock movements generated orders make per product and
+// warehouse. With one in IDMap, shipped sales order items and purchase
+// order receipts each write an inventory transaction referencing their
+// order, and inventory is written as the balance of that ledger after an
+// opening balance, instead of at random.
+type Ledger struct {
+	// stock holds the warehouses stocking each product, indexed like
+	// IDMap.ProductIDs
+	stock [][]stockLevel
+}
+
+// stockLevel is the movements of one product at one warehouse
+type stockLevel struct {
+	warehouse int // index into IDMap.WarehouseIDs
+	opening   int64
+	in        int64 // received from purchase orders
+	out       int64 // shipped on sales orders
+	restocked int64 // adjusted in to cover shipments and reservations
+	reserved  int64 // held for sales orders not shipped yet
+
+	// For a ledger opened from existing inventory, whether the level has
+	// a row, and the reservations it had
+	stocked         bool
+	openingReserved int64
+}
+
+func (s *stockLevel) quantity() int64 {
+	return s.opening + s.in - s.out + s.restocked
+}
+
+func (s *stockLevel) changed() bool {
+	return s.in != 0 || s.out != 0 || s.restocked != 0 || s.reserved != s.openingReserved
+}
+
+// NewLedger creates an empty ledger, stocking each product in 1 to
+// warehousesPerProduct+1 random warehouses, where its sales ship from
+func NewLedger(src *Source, idMap *IDMap, warehousesPerProduct int) *Ledger {
+	rng := src.Fork("inventory_locations", 0)
+
+	l := &Ledger{stock: make([][]stockLevel, len(idMap.ProductIDs))}
+	for product := range l.stock {
+		numWarehouses := min(rng.Number(1, warehousesPerProduct+1), len(idMap.WarehouseIDs))
+
+		for j := 0; j < numWarehouses; j++ {
+			warehouse := rng.Number(0, len(idMap.WarehouseIDs)-1)
+
+			// Avoid duplicate warehouse assignments
+			if l.find(product, warehouse) != nil {
+				continue
+			}
+			l.stock[product] = append(l.stock[product], stockLevel{warehouse: warehouse})
+		}
+	}
+	return l
+}
+
+// NewOpenLedger creates a ledger for products, indexed like
+// IDMap.ProductIDs, to be opened with existing inventory by Open, so orders
+// added to a seeded database move its stock
+func NewOpenLedger(products int) *Ledger {
+	return &Ledger{stock: make([][]stockLevel, products)}
+}
+
+// Open records the inventory row of product at warehouse as its opening
+// balance, with its reservations
+func (l *Ledger) Open(product, warehouse int, quantity, reserved int64) {
+	stock := l.at(product, warehouse)
+	stock.opening, stock.reserved = quantity, reserved
+	stock.stocked, stock.openingReserved = true, reserved
+}
+
+// Restock returns an adjustment transaction at each stock level whose
+// shipments and reservations outgrew it, bringing it back to cover its
+// reservations with some spare, so balances never go negative. The
+// adjustments are dated at, and draw from src's label fork.
+func (l *Ledger) Restock(src *Source, label string, idMap *IDMap, at time.Time) [][]interface{} {
+	rng := src.Fork(label, 0)
+
+	var rows [][]interface{}
+	for product, levels := range l.stock {
+		for i := range levels {
+			stock := &levels[i]
+			if short := stock.reserved - stock.quantity(); short > 0 {
+				adjustment := short + int64(rng.Number(0, 500))
+				stock.restocked += adjustment
+				rows = append(rows, inventoryTransactionRow(
+					rng, idMap.ProductIDs[product], idMap.WarehouseIDs[stock.warehouse],
+					"adjustment", adjustment, nil, nil, "Restock", at,
+				))
+			}
+		}
+	}
+	return rows
+}
+
+// Balance is the stock of one product at one warehouse
+type Balance struct {
+	Product   int // index into IDMap.ProductIDs
+	Warehouse int // index into IDMap.WarehouseIDs
+	Quantity  int64
+	Reserved  int64
+	// Stocked reports whether the product had an inventory row at the
+	// warehouse when the ledger was opened
+	Stocked bool
+}
+
+// Changes returns the balances of the stock levels that moved since the
+// ledger was opened
+func (l *Ledger) Changes() iter.Seq[Balance] {
+	return func(yield func(Balance) bool) {
+		for product, levels := range l.stock {
+			for _, stock := range levels {
+				if !stock.changed() {
+					continue
+				}
+				if !yield(Balance{product, stock.warehouse, stock.quantity(), stock.reserved, stock.stocked}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// pick returns a random warehouse stocking product, to ship it from
+func (l *Ledger) pick(product int, rng *Source) *stockLevel {
+	levels := l.stock[product]
+	return &levels[rng.Number(0, len(levels)-1)]
+}
+
+// at returns product's stock at warehouse, which starts being stocked there
+// if it was not yet
+func (l *Ledger) at(product, warehouse int) *stockLevel {
+	if s := l.find(product, warehouse); s != nil {
+		return s
+	}
+	l.stock[product] = append(l.stock[product], stockLevel{warehouse: warehouse})
+	return &l.stock[product][len(l.stock[product])-1]
+}
+
+func (l *Ledger) find(product, warehouse int) *stockLevel {
+	for i := range l.stock[product] {
+		if l.stock[product][i].warehouse == warehouse {
+			return &l.stock[product][i]
+		}
+	}
+	return nil
+}
+
+// Inventory transaction reference types
+var (
+	salesOrderReference    = "sales_order"
+	purchaseOrderReference = "purchase_order"
+)
+
+// inventoryTransactionRow creates one ledger entry. quantity is signed:
+// positive for stock coming in, negative for stock going out.
+func inventoryTransactionRow(rng *Source, productID, warehouseID uuid.UUID, txnType string, quantity int64, referenceID *uuid.UUID, referenceType *string, notes string, at time.Time) []interface{} {
+	return []interface{}{
+		rng.ID(),      // id
+		productID,     // product_id
+		warehouseID,   // warehouse_id
+		txnType,       // transaction_type
+		int(quantity), // quantity
+		referenceID,   // reference_id
+		referenceType, // reference_type
+		&notes,        // notes
+		at,            // created_at
+	}
+}
@@ -0,0 +1,148 @@
+package generators
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type stockKey struct{ product, warehouse uuid.UUID }
+
+// transactionTotals sums inventory transaction quantities per product and
+// warehouse
+func transactionTotals(rows [][]interface{}) map[stockKey]int64 {
+	totals := make(map[stockKey]int64)
+	for _, row := range rows {
+		totals[stockKey{row[1].(uuid.UUID), row[2].(uuid.UUID)}] += int64(row[4].(int))
+	}
+	return totals
+}
+
+func TestLedgerTotalsMatchInventory(t *testing.T) {
+	tests := []struct {
+		name                  string
+		salesOrders, purchase int
+		warehousesPerProduct  int
+	}{
+		{name: "no orders", warehousesPerProduct: 2},
+		{name: "sales only", salesOrders: 300, warehousesPerProduct: 1},
+		{name: "purchases only", purchase: 20, warehousesPerProduct: 2},
+		{name: "sales and purchases", salesOrders: 300, purchase: 20, warehousesPerProduct: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewSource(11, testNow)
+			_, idMap := testDataset(src, 40, 20)
+			idMap.Distributions = NewDistributions(src, idMap, 1, 0, nil, 0)
+			idMap.Ledger = NewLedger(src, idMap, tt.warehousesPerProduct)
+
+			var transactions [][]interface{}
+			for batch := range GenerateSalesOrders(src, 0, tt.salesOrders, 50, idMap, 4, 1) {
+				transactions = append(transactions, batch.Transactions...)
+			}
+			for batch := range GeneratePurchaseOrders(src, 0, tt.purchase, 50, idMap, 45, 1) {
+				transactions = append(transactions, batch.Transactions...)
+			}
+			inventory := collect(GenerateInventory(src, idMap, tt.warehousesPerProduct))
+			transactions = append(transactions, collect(GenerateInventoryTransactions(src, idMap, 0))...)
+
+			totals := transactionTotals(transactions)
+			seen := make(map[stockKey]bool)
+			for _, row := range inventory {
+				key := stockKey{row[1].(uuid.UUID), row[2].(uuid.UUID)}
+				quantity, reserved := int64(row[3].(int)), int64(row[4].(int))
+
+				if seen[key] {
+					t.Errorf("product %s stocked twice at warehouse %s", key.product, key.warehouse)
+				}
+				seen[key] = true
+				if totals[key] != quantity {
+					t.Errorf("product %s at warehouse %s: transactions total %d, inventory holds %d", key.product, key.warehouse, totals[key], quantity)
+				}
+				if reserved < 0 || reserved > quantity {
+					t.Errorf("product %s at warehouse %s: %d reserved of %d", key.product, key.warehouse, reserved, quantity)
+				}
+			}
+			for key := range totals {
+				if !seen[key] {
+					t.Errorf("product %s has transactions at warehouse %s but no inventory", key.product, key.warehouse)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenLedgerBalances(t *testing.T) {
+	idMap := &IDMap{
+		ProductIDs:   []uuid.UUID{uuid.New(), uuid.New()},
+		WarehouseIDs: []uuid.UUID{uuid.New(), uuid.New()},
+	}
+
+	tests := []struct {
+		name     string
+		opening  int64 // quantity of product 0 at warehouse 0
+		reserved int64
+		in, out  int64 // moved by orders after opening
+		reserve  int64 // reserved by orders after opening
+		restock  bool  // whether Restock adjusts the level
+		changed  bool
+	}{
+		{name: "untouched", opening: 100, reserved: 10},
+		{name: "received", opening: 100, in: 50, changed: true},
+		{name: "shipped within stock", opening: 100, reserved: 10, out: 90, changed: true},
+		{name: "shipped past stock", opening: 100, out: 150, restock: true, changed: true},
+		{name: "reserved past stock", opening: 20, reserved: 10, out: 5, reserve: 30, restock: true, changed: true},
+		{name: "reserved within stock", opening: 100, reserve: 30, changed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewOpenLedger(len(idMap.ProductIDs))
+			l.Open(0, 0, tt.opening, tt.reserved)
+			l.Open(1, 1, 5, 0)
+
+			stock := l.at(0, 0)
+			stock.in += tt.in
+			stock.out += tt.out
+			stock.reserved += tt.reserve
+
+			restock := l.Restock(NewSource(1, testNow), "restock", idMap, testNow)
+			if got := len(restock) == 1; got != tt.restock {
+				t.Fatalf("restocked %d levels, want restock %v", len(restock), tt.restock)
+			}
+			adjustment := int64(0)
+			if tt.restock {
+				adjustment = int64(restock[0][4].(int))
+			}
+
+			var changes []Balance
+			for b := range l.Changes() {
+				changes = append(changes, b)
+			}
+			if !tt.changed {
+				if len(changes) != 0 {
+					t.Errorf("changes = %+v, want none", changes)
+				}
+				return
+			}
+			if len(changes) != 1 {
+				t.Fatalf("changes = %+v, want product 0 at warehouse 0", changes)
+			}
+
+			b := changes[0]
+			want := Balance{
+				Product:  0,
+				Quantity: tt.opening + tt.in - tt.out + adjustment,
+				Reserved: tt.reserved + tt.reserve,
+				Stocked:  true,
+			}
+			if b != want {
+				t.Errorf("balance = %+v, want %+v", b, want)
+			}
+			if b.Quantity < b.Reserved {
+				t.Errorf("%d reserved of %d after restocking", b.Reserved, b.Quantity)
+			}
+		})
+	}
+}
@@ -2,9 +2,8 @@ package generators
 
 import (
 	"fmt"
-	"time"
+	"iter"
 
-	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 )
 
@@ -15,130 +14,139 @@ type IDMap struct {
 	WarehouseIDs []uuid.UUID
 	ProductIDs   []uuid.UUID
 	CustomerIDs  []uuid.UUID
-}
 
-// GenerateCategories creates hierarchical category data
-func GenerateCategories(count int) ([][]interface{}, *IDMap) {
-	idMap := &IDMap{
-		CategoryIDs: make([]uuid.UUID, 0, count),
-	}
+	// Price and cost histories of the products, which order lines are
+	// priced from
+	Prices *PriceHistory
+	Costs  *PriceHistory
 
-	rows := make([][]interface{}, 0, count)
-	now := time.Now()
+	// Ledger, when set, records the stock movements of generated orders
+	// for inventory to be derived from
+	Ledger *Ledger
 
-	// Create root categories (20% of total)
-	rootCount := count / 5
-	if rootCount == 0 {
-		rootCount = 1
-	}
+	// Distributions, when set, skews which products, customers and dates
+	// sales orders pick, which are uniform otherwise
+	Distributions *Distributions
+}
 
-	// Root categories
-	for i := 0; i < rootCount; i++ {
-		id := uuid.New()
-		idMap.CategoryIDs = append(idMap.CategoryIDs, id)
-
-		rows = append(rows, []interface{}{
-			id,                          // id
-			gofakeit.ProductCategory(),  // name
-			gofakeit.Sentence(10),       // description
-			nil,                         // parent_id (NULL for root)
-			now,                         // created_at
-			now,                         // updated_at
-			nil,                         // deleted_at
-		})
+// GenerateCategories streams hierarchical category data. The category IDs
+// land in the returned IDMap as the rows are consumed.
+func GenerateCategories(src *Source, count int) (iter.Seq[[]interface{}], *IDMap) {
+	idMap := &IDMap{
+		CategoryIDs: make([]uuid.UUID, 0, count),
 	}
 
-	// Child categories
-	for i := rootCount; i < count; i++ {
-		id := uuid.New()
-		idMap.CategoryIDs = append(idMap.CategoryIDs, id)
-
-		// Randomly assign to a parent category
-		parentID := idMap.CategoryIDs[gofakeit.Number(0, len(idMap.CategoryIDs)-1)]
-
-		rows = append(rows, []interface{}{
-			id,                          // id
-			gofakeit.ProductCategory(),  // name
-			gofakeit.Sentence(10),       // description
-			parentID,                    // parent_id
-			now,                         // created_at
-			now,                         // updated_at
-			nil,                         // deleted_at
-		})
+	rows := func(yield func([]interface{}) bool) {
+		rng := src.Fork("categories", 0)
+		now := rng.Now
+
+		// Create root categories (20% of total)
+		rootCount := count / 5
+		if rootCount == 0 {
+			rootCount = 1
+		}
+
+		for i := 0; i < count; i++ {
+			id := rng.ID()
+
+			// Root categories have no parent, the rest are randomly
+			// assigned to a category generated before them
+			var parentID interface{}
+			if i >= rootCount {
+				parentID = idMap.CategoryIDs[rng.Number(0, len(idMap.CategoryIDs)-1)]
+			}
+			idMap.CategoryIDs = append(idMap.CategoryIDs, id)
+
+			if !yield([]interface{}{
+				id,                    // id
+				rng.ProductCategory(), // name
+				rng.Sentence(10),      // description
+				parentID,              // parent_id (NULL for root)
+				now,                   // created_at
+				now,                   // updated_at
+				nil,                   // deleted_at
+			}) {
+				return
+			}
+		}
 	}
 
 	return rows, idMap
 }
 
-// GenerateSuppliers creates supplier data
-func GenerateSuppliers(count int, idMap *IDMap) [][]interface{} {
-	rows := make([][]interface{}, 0, count)
-	now := time.Now()
-
-	for i := 0; i < count; i++ {
-		id := uuid.New()
-		idMap.SupplierIDs = append(idMap.SupplierIDs, id)
-
-		contactName := gofakeit.Name()
-		email := gofakeit.Email()
-		phone := gofakeit.Phone()
-		address := gofakeit.Address().Address
-		city := gofakeit.City()
-		state := gofakeit.StateAbr()
-		postalCode := gofakeit.Zip()
-		country := gofakeit.Country()
-
-		rows = append(rows, []interface{}{
-			id,                    // id
-			gofakeit.Company(),    // name
-			&contactName,          // contact_name
-			&email,                // email
-			&phone,                // phone
-			&address,              // address
-			&city,                 // city
-			&state,                // state
-			&postalCode,           // postal_code
-			&country,              // country
-			now,                   // created_at
-			now,                   // updated_at
-			nil,                   // deleted_at
-		})
+// GenerateSuppliers streams supplier data
+func GenerateSuppliers(src *Source, count int, idMap *IDMap) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("suppliers", 0)
+		now := rng.Now
+
+		for i := 0; i < count; i++ {
+			id := rng.ID()
+			idMap.SupplierIDs = append(idMap.SupplierIDs, id)
+
+			contactName := rng.Name()
+			email := rng.Email()
+			phone := rng.Phone()
+			address := rng.Address().Address
+			city := rng.City()
+			state := rng.StateAbr()
+			postalCode := rng.Zip()
+			country := rng.Country()
+
+			if !yield([]interface{}{
+				id,            // id
+				rng.Company(), // name
+				&contactName,  // contact_name
+				&email,        // email
+				&phone,        // phone
+				&address,      // address
+				&city,         // city
+				&state,        // state
+				&postalCode,   // postal_code
+				&country,      // country
+				now,           // created_at
+				now,           // updated_at
+				nil,           // deleted_at
+			}) {
+				return
+			}
+		}
 	}
-
-	return rows
 }
 
-// GenerateWarehouses creates warehouse data
-func GenerateWarehouses(count int, idMap *IDMap) [][]interface{} {
-	rows := make([][]interface{}, 0, count)
-	now := time.Now()
-
-	for i := 0; i < count; i++ {
-		id := uuid.New()
-		idMap.WarehouseIDs = append(idMap.WarehouseIDs, id)
-
-		address := gofakeit.Address().Address
-		city := gofakeit.City()
-		state := gofakeit.StateAbr()
-		postalCode := gofakeit.Zip()
-		country := gofakeit.Country()
-
-		rows = append(rows, []interface{}{
-			id,                           // id
-			fmt.Sprintf("Warehouse %s", city), // name
-			fmt.Sprintf("WH-%04d", i+1),  // code
-			&address,                     // address
-			&city,                        // city
-			&state,                       // state
-			&postalCode,                  // postal_code
-			&country,                     // country
-			now,                          // created_at
-			now,                          // updated_at
-			nil,                          // deleted_at
-		})
+// GenerateWarehouses streams warehouse data
+func GenerateWarehouses(src *Source, count int, idMap *IDMap) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("warehouses", 0)
+		now := rng.Now
+
+		for i := 0; i < count; i++ {
+			id := rng.ID()
+			idMap.WarehouseIDs = append(idMap.WarehouseIDs, id)
+
+			address := rng.Address().Address
+			city := rng.City()
+			state := rng.StateAbr()
+			postalCode := rng.Zip()
+			country := rng.Country()
+
+			if !yield([]interface{}{
+				id,                                // id
+				fmt.Sprintf("Warehouse %s", city), // name
+				fmt.Sprintf("WH-%04d", i+1),       // code
+				&address,                          // address
+				&city,                             // city
+				&state,                            // state
+				&postalCode,                       // postal_code
+				&country,                          // country
+				now,                               // created_at
+				now,                               // updated_at
+				nil,                               // deleted_at
+			}) {
+				return
+			}
+		}
 	}
-
-	return rows
 }
 
 // CategoryColumns returns the column names for categories table
@@ -0,0 +1,92 @@
+package generators
+
+import (
+	"math"
+	"time"
+)
+
+// orderWindowDays is how far back order dates go. Price and cost histories
+// cover the whole window, so every order date has an effective price.
+const orderWindowDays = 365
+
+// PriceHistory holds the price or cost series of every product, Per
+// entries each and newest first, indexed like IDMap.ProductIDs. Amounts are
+// in cents, as the DECIMAL(10,2) columns store them, so sums over them are
+// exact.
+type PriceHistory struct {
+	Per       int
+	Cents     []int64
+	Effective []int64 // effective_date, Unix seconds
+}
+
+// NewPriceHistory creates an empty history of per entries per product
+func NewPriceHistory(products, per int) *PriceHistory {
+	return &PriceHistory{
+		Per:       per,
+		Cents:     make([]int64, 0, products*per),
+		Effective: make([]int64, 0, products*per),
+	}
+}
+
+func (h *PriceHistory) add(cents int64, effective time.Time) {
+	h.Cents = append(h.Cents, cents)
+	h.Effective = append(h.Effective, effective.Unix())
+}
+
+// AddProduct appends the history of the next product, as loaded from the
+// database, newest first. A history shorter than Per is padded with its
+// oldest entry, which At picks for the same dates.
+func (h *PriceHistory) AddProduct(cents []int64, effective []time.Time) {
+	for j := range h.Per {
+		k := min(j, len(cents)-1)
+		h.add(cents[k], effective[k])
+	}
+}
+
+// At returns the amount in effect for the product at index product at t:
+// that of the newest entry effective at or before t, or the oldest entry
+// for a t before the history starts.
+func (h *PriceHistory) At(product int, t time.Time) int64 {
+	base := product * h.Per
+	unix := t.Unix()
+	for j := 0; j < h.Per; j++ {
+		if h.Effective[base+j] <= unix {
+			return h.Cents[base+j]
+		}
+	}
+	return h.Cents[base+h.Per-1]
+}
+
+// Current returns the amount in effect now for the product at index product
+func (h *PriceHistory) Current(product int) int64 {
+	return h.Cents[product*h.Per]
+}
+
+// historyDates returns the effective and end dates of entry j of a history
+// of n entries, newest first. The entries split the order window evenly and
+// the oldest starts with it; the newest has no end date.
+func historyDates(now time.Time, j, n int) (time.Time, *time.Time) {
+	step := time.Duration(orderWindowDays) * 24 * time.Hour / time.Duration(n)
+	effective := now.Add(-step * time.Duration(j+1))
+
+	if j == 0 {
+		return effective, nil
+	}
+	end := now.Add(-step * time.Duration(j)).Add(-time.Second)
+	return effective, &end
+}
+
+// cents converts a dollar amount to cents, rounding half away from zero
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// percentOf returns pct percent of c, in cents
+func percentOf(c int64, pct float64) int64 {
+	return int64(math.Round(float64(c) * pct / 100))
+}
+
+// dollars converts cents to the float64 written to DECIMAL(10,2) columns
+func dollars(c int64) float64 {
+	return float64(c) / 100
+}
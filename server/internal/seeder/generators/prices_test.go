@@ -0,0 +1,84 @@
+package generators
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPriceHistoryAddProduct(t *testing.T) {
+	day := func(d int) time.Time { return testNow.AddDate(0, 0, -d) }
+
+	tests := []struct {
+		name      string
+		per       int
+		cents     []int64
+		effective []time.Time
+		want      []int64
+	}{
+		{name: "full", per: 3, cents: []int64{300, 200, 100}, effective: []time.Time{day(10), day(20), day(30)}, want: []int64{300, 200, 100}},
+		{name: "padded with the oldest", per: 4, cents: []int64{300, 200}, effective: []time.Time{day(10), day(20)}, want: []int64{300, 200, 200, 200}},
+		{name: "single entry", per: 3, cents: []int64{500}, effective: []time.Time{day(5)}, want: []int64{500, 500, 500}},
+		{name: "longer than per", per: 2, cents: []int64{300, 200, 100}, effective: []time.Time{day(10), day(20), day(30)}, want: []int64{300, 200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewPriceHistory(2, tt.per)
+			h.AddProduct([]int64{1}, []time.Time{day(1)})
+			h.AddProduct(tt.cents, tt.effective)
+
+			if got := h.Cents[tt.per:]; !slices.Equal(got, tt.want) {
+				t.Errorf("cents = %v, want %v", got, tt.want)
+			}
+			if got := h.Current(1); got != tt.want[0] {
+				t.Errorf("Current = %d, want %d", got, tt.want[0])
+			}
+			// Padding changes nothing At returns, and entries past Per are
+			// dropped
+			kept := min(len(tt.cents), tt.per)
+			for _, d := range []int{0, 5, 10, 15, 20, 25, 30, 40} {
+				want := tt.cents[kept-1]
+				for j, e := range tt.effective[:kept] {
+					if !e.After(day(d)) {
+						want = tt.cents[j]
+						break
+					}
+				}
+				if got := h.At(1, day(d)); got != want {
+					t.Errorf("At %d days back = %d, want %d", d, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPriceHistoryAt(t *testing.T) {
+	h := NewPriceHistory(1, 3)
+	for j := range 3 {
+		effective, _ := historyDates(testNow, j, 3)
+		h.add(int64(300-100*j), effective)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int64
+	}{
+		{name: "now", at: testNow, want: 300},
+		{name: "newest starts", at: time.Unix(h.Effective[0], 0), want: 300},
+		{name: "a second before the newest", at: time.Unix(h.Effective[0]-1, 0), want: 200},
+		{name: "middle", at: time.Unix(h.Effective[1]+3600, 0), want: 200},
+		{name: "oldest starts", at: time.Unix(h.Effective[2], 0), want: 100},
+		{name: "start of the order window", at: testNow.AddDate(0, 0, -orderWindowDays), want: 100},
+		{name: "before the history", at: testNow.AddDate(-2, 0, 0), want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.At(0, tt.at); got != tt.want {
+				t.Errorf("At = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
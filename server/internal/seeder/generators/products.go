@@ -2,196 +2,205 @@ package generators
 
 import (
 	"fmt"
-	"time"
+	"iter"
 
-	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 )
 
-// GenerateProducts creates product data
-func GenerateProducts(count int, idMap *IDMap) [][]interface{} {
-	rows := make([][]interface{}, 0, count)
-	now := time.Now()
-
-	for i := 0; i < count; i++ {
-		id := uuid.New()
-		idMap.ProductIDs = append(idMap.ProductIDs, id)
-
-		description := gofakeit.Paragraph(2, 3, 10, " ")
-		weight := float64(gofakeit.Number(1, 10000)) / 100.0
-		dimensions := fmt.Sprintf("%dx%dx%d cm",
-			gofakeit.Number(1, 100),
-			gofakeit.Number(1, 100),
-			gofakeit.Number(1, 100))
-
-		rows = append(rows, []interface{}{
-			id,                                 // id
-			fmt.Sprintf("SKU-%09d", i+1),       // sku
-			gofakeit.ProductName(),             // name
-			&description,                       // description
-			&weight,                            // weight
-			&dimensions,                        // dimensions
-			true,                               // is_active
-			now,                                // created_at
-			now,                                // updated_at
-			nil,                                // deleted_at
-		})
+// GenerateProducts streams product data
+func GenerateProducts(src *Source, count int, idMap *IDMap) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("products", 0)
+		now := rng.Now
+
+		for i := 0; i < count; i++ {
+			id := rng.ID()
+			idMap.ProductIDs = append(idMap.ProductIDs, id)
+
+			description := rng.Paragraph(2, 3, 10, " ")
+			weight := float64(rng.Number(1, 10000)) / 100.0
+			dimensions := fmt.Sprintf("%dx%dx%d cm",
+				rng.Number(1, 100),
+				rng.Number(1, 100),
+				rng.Number(1, 100))
+
+			if !yield([]interface{}{
+				id,                           // id
+				fmt.Sprintf("SKU-%09d", i+1), // sku
+				rng.ProductName(),            // name
+				&description,                 // description
+				&weight,                      // weight
+				&dimensions,                  // dimensions
+				true,                         // is_active
+				now,                          // created_at
+				now,                          // updated_at
+				nil,                          // deleted_at
+			}) {
+				return
+			}
+		}
 	}
-
-	return rows
 }
 
-// GenerateProductCategories creates product-category relationships
-func GenerateProductCategories(idMap *IDMap, categoriesPerProduct int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.ProductIDs)*categoriesPerProduct)
-	now := time.Now()
-
-	for _, productID := range idMap.ProductIDs {
-		// Randomly assign 1-3 categories per product
-		numCategories := gofakeit.Number(1, categoriesPerProduct+1)
-		usedCategories := make(map[uuid.UUID]bool)
-
-		for j := 0; j < numCategories; j++ {
-			categoryID := idMap.CategoryIDs[gofakeit.Number(0, len(idMap.CategoryIDs)-1)]
-
-			// Avoid duplicate category assignments
-			if usedCategories[categoryID] {
-				continue
+// GenerateProductCategories streams product-category relationships
+func GenerateProductCategories(src *Source, idMap *IDMap, categoriesPerProduct int) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("product_categories", 0)
+		now := rng.Now
+
+		for _, productID := range idMap.ProductIDs {
+			// Randomly assign 1-3 categories per product
+			numCategories := rng.Number(1, categoriesPerProduct+1)
+			usedCategories := make(map[uuid.UUID]bool)
+
+			for j := 0; j < numCategories; j++ {
+				categoryID := idMap.CategoryIDs[rng.Number(0, len(idMap.CategoryIDs)-1)]
+
+				// Avoid duplicate category assignments
+				if usedCategories[categoryID] {
+					continue
+				}
+				usedCategories[categoryID] = true
+
+				if !yield([]interface{}{
+					rng.ID(),   // id
+					productID,  // product_id
+					categoryID, // category_id
+					now,        // created_at
+				}) {
+					return
+				}
 			}
-			usedCategories[categoryID] = true
-
-			rows = append(rows, []interface{}{
-				uuid.New(),  // id
-				productID,   // product_id
-				categoryID,  // category_id
-				now,         // created_at
-			})
 		}
 	}
-
-	return rows
 }
 
-// GenerateProductPrices creates pricing history
-func GenerateProductPrices(idMap *IDMap, pricesPerProduct int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.ProductIDs)*pricesPerProduct)
-	now := time.Now()
-
-	for _, productID := range idMap.ProductIDs {
-		basePrice := float64(gofakeit.Number(500, 50000)) / 100.0
-
-		for j := 0; j < pricesPerProduct; j++ {
-			// Create price history going back in time
-			effectiveDate := now.AddDate(0, 0, -j*30)
-
-			// Price varies by ±20% from base
-			priceVariation := float64(gofakeit.Number(80, 120)) / 100.0
-			price := basePrice * priceVariation
-
-			var endDate *time.Time
-			if j > 0 {
-				end := now.AddDate(0, 0, -(j-1)*30).Add(-time.Second)
-				endDate = &end
+// GenerateProductPrices streams pricing history, recording it in idMap for
+// pricing sales order lines
+func GenerateProductPrices(src *Source, idMap *IDMap, pricesPerProduct int) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("product_prices", 0)
+		now := rng.Now
+
+		pricesPerProduct = max(pricesPerProduct, 1)
+		idMap.Prices = NewPriceHistory(len(idMap.ProductIDs), pricesPerProduct)
+
+		for _, productID := range idMap.ProductIDs {
+			basePrice := float64(rng.Number(500, 50000)) / 100.0
+
+			for j := 0; j < pricesPerProduct; j++ {
+				// Create price history going back in time
+				effectiveDate, endDate := historyDates(now, j, pricesPerProduct)
+
+				// Price varies by ±20% from base
+				priceVariation := float64(rng.Number(80, 120)) / 100.0
+				price := cents(basePrice * priceVariation)
+				idMap.Prices.add(price, effectiveDate)
+
+				if !yield([]interface{}{
+					rng.ID(),       // id
+					productID,      // product_id
+					dollars(price), // price
+					"USD",          // currency
+					effectiveDate,  // effective_date
+					endDate,        // end_date
+					now,            // created_at
+					now,            // updated_at
+				}) {
+					return
+				}
 			}
-
-			rows = append(rows, []interface{}{
-				uuid.New(),      // id
-				productID,       // product_id
-				price,           // price
-				"USD",           // currency
-				effectiveDate,   // effective_date
-				endDate,         // end_date
-				now,             // created_at
-				now,             // updated_at
-			})
 		}
 	}
-
-	return rows
 }
 
-// GenerateProductCosts creates cost history
-func GenerateProductCosts(idMap *IDMap, costsPerProduct int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.ProductIDs)*costsPerProduct)
-	now := time.Now()
-
-	for _, productID := range idMap.ProductIDs {
-		baseCost := float64(gofakeit.Number(200, 30000)) / 100.0
-
-		for j := 0; j < costsPerProduct; j++ {
-			// Create cost history going back in time
-			effectiveDate := now.AddDate(0, 0, -j*30)
-
-			// Cost varies by ±15% from base
-			costVariation := float64(gofakeit.Number(85, 115)) / 100.0
-			cost := baseCost * costVariation
-
-			var endDate *time.Time
-			if j > 0 {
-				end := now.AddDate(0, 0, -(j-1)*30).Add(-time.Second)
-				endDate = &end
+// GenerateProductCosts streams cost history, recording it in idMap for
+// costing purchase order lines. Costs are a margin below the product's
+// current price, so GenerateProductPrices must have run first.
+func GenerateProductCosts(src *Source, idMap *IDMap, costsPerProduct int) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("product_costs", 0)
+		now := rng.Now
+
+		costsPerProduct = max(costsPerProduct, 1)
+		idMap.Costs = NewPriceHistory(len(idMap.ProductIDs), costsPerProduct)
+
+		for i, productID := range idMap.ProductIDs {
+			// Base cost is 40-80% of the current price
+			baseCost := float64(idMap.Prices.Current(i)) / 100.0 * float64(rng.Number(40, 80)) / 100.0
+
+			for j := 0; j < costsPerProduct; j++ {
+				// Create cost history going back in time
+				effectiveDate, endDate := historyDates(now, j, costsPerProduct)
+
+				// Cost varies by ±15% from base
+				costVariation := float64(rng.Number(85, 115)) / 100.0
+				cost := max(cents(baseCost*costVariation), 1)
+				idMap.Costs.add(cost, effectiveDate)
+
+				if !yield([]interface{}{
+					rng.ID(),      // id
+					productID,     // product_id
+					dollars(cost), // cost
+					"USD",         // currency
+					effectiveDate, // effective_date
+					endDate,       // end_date
+					now,           // created_at
+					now,           // updated_at
+				}) {
+					return
+				}
 			}
-
-			rows = append(rows, []interface{}{
-				uuid.New(),      // id
-				productID,       // product_id
-				cost,            // cost
-				"USD",           // currency
-				effectiveDate,   // effective_date
-				endDate,         // end_date
-				now,             // created_at
-				now,             // updated_at
-			})
 		}
 	}
-
-	return rows
 }
 
-// GenerateSupplierProducts creates supplier-product relationships
-func GenerateSupplierProducts(idMap *IDMap, suppliersPerProduct int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(idMap.ProductIDs)*suppliersPerProduct)
-	now := time.Now()
-
-	for _, productID := range idMap.ProductIDs {
-		// Randomly assign 1-5 suppliers per product
-		numSuppliers := gofakeit.Number(1, suppliersPerProduct+2)
-		if numSuppliers > len(idMap.SupplierIDs) {
-			numSuppliers = len(idMap.SupplierIDs)
-		}
-
-		usedSuppliers := make(map[uuid.UUID]bool)
-
-		for j := 0; j < numSuppliers; j++ {
-			supplierID := idMap.SupplierIDs[gofakeit.Number(0, len(idMap.SupplierIDs)-1)]
+// GenerateSupplierProducts streams supplier-product relationships
+func GenerateSupplierProducts(src *Source, idMap *IDMap, suppliersPerProduct int) iter.Seq[[]interface{}] {
+	return func(yield func([]interface{}) bool) {
+		rng := src.Fork("supplier_products", 0)
+		now := rng.Now
+
+		for _, productID := range idMap.ProductIDs {
+			// Randomly assign 1-5 suppliers per product
+			numSuppliers := rng.Number(1, suppliersPerProduct+2)
+			if numSuppliers > len(idMap.SupplierIDs) {
+				numSuppliers = len(idMap.SupplierIDs)
+			}
 
-			// Avoid duplicate supplier assignments
-			if usedSuppliers[supplierID] {
-				continue
+			usedSuppliers := make(map[uuid.UUID]bool)
+
+			for j := 0; j < numSuppliers; j++ {
+				supplierID := idMap.SupplierIDs[rng.Number(0, len(idMap.SupplierIDs)-1)]
+
+				// Avoid duplicate supplier assignments
+				if usedSuppliers[supplierID] {
+					continue
+				}
+				usedSuppliers[supplierID] = true
+
+				supplierSKU := fmt.Sprintf("SUP-%s-%d", supplierID.String()[:8], rng.Number(1000, 9999))
+				cost := float64(rng.Number(200, 30000)) / 100.0
+				leadTime := rng.Number(1, 60)
+				minOrder := rng.Number(1, 100)
+
+				if !yield([]interface{}{
+					rng.ID(),     // id
+					supplierID,   // supplier_id
+					productID,    // product_id
+					&supplierSKU, // supplier_sku
+					&cost,        // cost
+					"USD",        // currency
+					&leadTime,    // lead_time_days
+					&minOrder,    // minimum_order_quantity
+					now,          // created_at
+					now,          // updated_at
+				}) {
+					return
+				}
 			}
-			usedSuppliers[supplierID] = true
-
-			supplierSKU := fmt.Sprintf("SUP-%s-%d", supplierID.String()[:8], gofakeit.Number(1000, 9999))
-			cost := float64(gofakeit.Number(200, 30000)) / 100.0
-			leadTime := gofakeit.Number(1, 60)
-			minOrder := gofakeit.Number(1, 100)
-
-			rows = append(rows, []interface{}{
-				uuid.New(),     // id
-				supplierID,     // supplier_id
-				productID,      // product_id
-				&supplierSKU,   // supplier_sku
-				&cost,          // cost
-				"USD",          // currency
-				&leadTime,      // lead_time_days
-				&minOrder,      // minimum_order_quantity
-				now,            // created_at
-				now,            // updated_at
-			})
 		}
 	}
-
-	return rows
 }
 
 // Product-related column functions
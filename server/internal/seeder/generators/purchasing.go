@@ -2,159 +2,229 @@ package generators
 
 import (
 	"fmt"
+	"iter"
 	"time"
 
-	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 )
 
-// PurchaseOrderData holds generated purchase order IDs and item IDs
-type PurchaseOrderData struct {
-	OrderIDs []uuid.UUID
-	ItemIDs  []uuid.UUID
+// PurchaseOrderBatch holds a run of purchase orders with their line items
+// and the receipts against those items, in foreign key order. Transactions
+// holds the inventory transactions of the receipts when IDMap has a Ledger.
+type PurchaseOrderBatch struct {
+	Orders       [][]interface{}
+	Items        [][]interface{}
+	Receipts     [][]interface{}
+	Transactions [][]interface{}
 }
 
-// GeneratePurchaseOrders creates purchase order data
-func GeneratePurchaseOrders(count int, idMap *IDMap) ([][]interface{}, *PurchaseOrderData) {
-	rows := make([][]interface{}, 0, count)
-	data := &PurchaseOrderData{
-		OrderIDs: make([]uuid.UUID, 0, count),
-	}
-	now := time.Now()
+// GeneratePurchaseOrders streams count purchase orders, starting with the
+// order at index first, in batches of up to batchSize orders, each with its
+// items and their receipts.
+func GeneratePurchaseOrders(src *Source, first, count, batchSize int, idMap *IDMap, itemsPerOrder, receiptsPerItem int) iter.Seq[*PurchaseOrderBatch] {
+	return func(yield func(*PurchaseOrderBatch) bool) {
+		for start := first; start < first+count; start += batchSize {
+			end := min(start+batchSize, first+count)
+
+			batch := &PurchaseOrderBatch{
+				Orders:   make([][]interface{}, 0, end-start),
+				Items:    make([][]interface{}, 0, (end-start)*itemsPerOrder),
+				Receipts: make([][]interface{}, 0, (end-start)*itemsPerOrder*receiptsPerItem),
+			}
+			for i := start; i < end; i++ {
+				// Each order draws from its own fork, so the data does not
+				// depend on the batch size and any order can be regenerated
+				rng := src.Fork("purchase_orders", i)
+				order := newPurchaseOrder(i, idMap, rng)
+				appendPurchaseOrderItems(batch, order, idMap, itemsPerOrder, receiptsPerItem, rng)
+				// Freight on bulk orders
+				order.shipping = int64(rng.Number(25_00, 250_00))
+				batch.Orders = append(batch.Orders, order.row(rng.Now))
+			}
 
-	statuses := []string{"pending", "confirmed", "partially_received", "received", "cancelled"}
+			if !yield(batch) {
+				return
+			}
+		}
+	}
+}
 
-	for i := 0; i < count; i++ {
-		id := uuid.New()
-		data.OrderIDs = append(data.OrderIDs, id)
+var purchaseOrderStatuses = []string{"pending", "confirmed", "partially_received", "received", "cancelled"}
+
+// purchaseOrder is a purchase order being generated. Its money fields are
+// in cents and summed from the items as they are generated.
+type purchaseOrder struct {
+	id           uuid.UUID
+	number       int
+	supplierID   uuid.UUID
+	warehouse    int // index into IDMap.WarehouseIDs
+	warehouseID  uuid.UUID
+	date         time.Time
+	expectedDate time.Time
+	status       string
+	notes        string
+
+	subtotal int64
+	tax      int64
+	shipping int64
+}
 
+// newPurchaseOrder starts the i-th purchase order
+func newPurchaseOrder(i int, idMap *IDMap, rng *Source) *purchaseOrder {
+	o := &purchaseOrder{
+		id:     rng.ID(),
+		number: i + 1,
 		// Random supplier
-		supplierID := idMap.SupplierIDs[gofakeit.Number(0, len(idMap.SupplierIDs)-1)]
-
+		supplierID: idMap.SupplierIDs[rng.Number(0, len(idMap.SupplierIDs)-1)],
 		// Random warehouse
-		warehouseID := idMap.WarehouseIDs[gofakeit.Number(0, len(idMap.WarehouseIDs)-1)]
-
-		// Random order date in the past year
-		orderDate := now.AddDate(0, 0, -gofakeit.Number(0, 365))
-
-		// Expected date 7-60 days after order
-		expectedDays := gofakeit.Number(7, 60)
-		expectedDate := orderDate.AddDate(0, 0, expectedDays)
-
-		status := statuses[gofakeit.Number(0, len(statuses)-1)]
-		notes := gofakeit.Sentence(15)
-
-		// Totals will be calculated from items
-		rows = append(rows, []interface{}{
-			id,                               // id
-			fmt.Sprintf("PO-%010d", i+1),     // po_number
-			supplierID,                       // supplier_id
-			warehouseID,                      // warehouse_id
-			orderDate,                        // order_date
-			&expectedDate,                    // expected_date
-			status,                           // status
-			0.0,                              // subtotal
-			0.0,                              // tax
-			0.0,                              // shipping
-			0.0,                              // total
-			&notes,                           // notes
-			now,                              // created_at
-			now,                              // updated_at
-			nil,                              // deleted_at
-		})
+		warehouse: rng.Number(0, len(idMap.WarehouseIDs)-1),
+		// Random order date in the order window
+		date: rng.Now.AddDate(0, 0, -rng.Number(0, orderWindowDays)),
 	}
+	o.warehouseID = idMap.WarehouseIDs[o.warehouse]
 
-	return rows, data
+	// Expected date 7-60 days after order
+	o.expectedDate = o.date.AddDate(0, 0, rng.Number(7, 60))
+
+	o.status = purchaseOrderStatuses[rng.Number(0, len(purchaseOrderStatuses)-1)]
+	o.notes = rng.Sentence(15)
+	return o
 }
 
-// GeneratePurchaseOrderItems creates purchase order line items
-func GeneratePurchaseOrderItems(purchaseData *PurchaseOrderData, idMap *IDMap, itemsPerOrder int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(purchaseData.OrderIDs)*itemsPerOrder)
-	now := time.Now()
+func (o *purchaseOrder) total() int64 {
+	return o.subtotal + o.tax + o.shipping
+}
 
-	for _, orderID := range purchaseData.OrderIDs {
-		// Generate 10-100 items per order (bulk purchases)
-		numItems := gofakeit.Number(itemsPerOrder-40, itemsPerOrder+50)
+func (o *purchaseOrder) row(now time.Time) []interface{} {
+	return []interface{}{
+		o.id,                              // id
+		fmt.Sprintf("PO-%010d", o.number), // po_number
+		o.supplierID,                      // supplier_id
+		o.warehouseID,                     // warehouse_id
+		o.date,                            // order_date
+		&o.expectedDate,                   // expected_date
+		o.status,                          // status
+		dollars(o.subtotal),               // subtotal
+		dollars(o.tax),                    // tax
+		dollars(o.shipping),               // shipping
+		dollars(o.total()),                // total
+		&o.notes,                          // notes
+		now,                               // created_at
+		now,                               // updated_at
+		nil,                               // deleted_at
+	}
+}
 
-		usedProducts := make(map[uuid.UUID]bool)
+// appendPurchaseOrderItems appends the line items of one purchase order to
+// batch, costed at each product's cost on the order date, along with the
+// receipts against each item. Items are received as the order's status
+// says: nothing before confirmation or once cancelled, everything once
+// received, and some of some items in between.
+func appendPurchaseOrderItems(batch *PurchaseOrderBatch, order *purchaseOrder, idMap *IDMap, itemsPerOrder, receiptsPerItem int, rng *Source) {
+	now := rng.Now
 
-		for j := 0; j < numItems; j++ {
-			productID := idMap.ProductIDs[gofakeit.Number(0, len(idMap.ProductIDs)-1)]
+	// Generate 10-100 items per order (bulk purchases)
+	numItems := rng.Number(max(itemsPerOrder-40, 1), itemsPerOrder+50)
 
-			// Avoid duplicate products in same order
-			if usedProducts[productID] {
-				continue
-			}
-			usedProducts[productID] = true
+	usedProducts := make(map[int]bool)
 
-			itemID := uuid.New()
-			purchaseData.ItemIDs = append(purchaseData.ItemIDs, itemID)
+	for j := 0; j < numItems; j++ {
+		product := rng.Number(0, len(idMap.ProductIDs)-1)
 
-			quantity := gofakeit.Number(10, 1000) // Bulk quantities
-			unitCost := float64(gofakeit.Number(200, 30000)) / 100.0
-			tax := (unitCost * float64(quantity)) * 0.05 // 5% tax
-			total := (unitCost * float64(quantity)) + tax
+		// Avoid duplicate products in same order
+		if usedProducts[product] {
+			continue
+		}
+		usedProducts[product] = true
 
+		itemID := rng.ID()
+
+		quantity := rng.Number(10, 1000) // Bulk quantities
+		unitCost := idMap.Costs.At(product, order.date)
+		line := unitCost * int64(quantity)
+		tax := percentOf(line, 5) // 5% tax
+		total := line + tax
+
+		order.subtotal += line
+		order.tax += tax
+
+		receivedQty := 0
+		switch order.status {
+		case "received":
+			receivedQty = quantity
+		case "partially_received":
 			// Some items partially received
-			receivedQty := 0
-			if gofakeit.Bool() {
-				receivedQty = gofakeit.Number(0, quantity)
+			if rng.Bool() {
+				receivedQty = rng.Number(0, quantity)
 			}
-
-			rows = append(rows, []interface{}{
-				itemID,         // id
-				orderID,        // purchase_order_id
-				productID,      // product_id
-				quantity,       // quantity
-				unitCost,       // unit_cost
-				tax,            // tax
-				total,          // total
-				receivedQty,    // received_quantity
-				now,            // created_at
-				now,            // updated_at
-			})
 		}
-	}
 
-	return rows
+		batch.Items = append(batch.Items, []interface{}{
+			itemID,                    // id
+			order.id,                  // purchase_order_id
+			idMap.ProductIDs[product], // product_id
+			quantity,                  // quantity
+			dollars(unitCost),         // unit_cost
+			dollars(tax),              // tax
+			dollars(total),            // total
+			receivedQty,               // received_quantity
+			now,                       // created_at
+			now,                       // updated_at
+		})
+
+		appendPurchaseOrderReceipts(batch, order, idMap, product, itemID, receivedQty, receiptsPerItem, rng)
+	}
 }
 
-// GeneratePurchaseOrderReceipts creates receipt records for items
-func GeneratePurchaseOrderReceipts(purchaseData *PurchaseOrderData, receiptsPerItem int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(purchaseData.ItemIDs)*receiptsPerItem)
-	now := time.Now()
+// appendPurchaseOrderReceipts appends the receipts for one purchase order
+// item to batch, splitting the received quantity over the deliveries. With
+// a ledger, each receipt puts stock into the order's warehouse.
+func appendPurchaseOrderReceipts(batch *PurchaseOrderBatch, order *purchaseOrder, idMap *IDMap, product int, itemID uuid.UUID, receivedQty, receiptsPerItem int, rng *Source) {
+	now := rng.Now
 
-	for i, itemID := range purchaseData.ItemIDs {
-		// Get the order ID for this item (use modulo to map back)
-		orderIdx := i / 50 // Approximate, assuming ~50 items per order
-		if orderIdx >= len(purchaseData.OrderIDs) {
-			orderIdx = len(purchaseData.OrderIDs) - 1
+	// Usually 1 receipt, sometimes 2 (partial deliveries)
+	numReceipts := min(rng.Number(1, receiptsPerItem+1), receivedQty)
+
+	remaining := receivedQty
+	for j := 0; j < numReceipts; j++ {
+		qtyReceived := remaining
+		if j < numReceipts-1 {
+			qtyReceived = rng.Number(1, remaining-(numReceipts-1-j))
+		}
+		remaining -= qtyReceived
+
+		// Delivered around the expected date, never before ordering or
+		// after now
+		receivedDate := order.expectedDate.AddDate(0, 0, rng.Number(-5, 10))
+		if receivedDate.Before(order.date) {
+			receivedDate = order.date
 		}
-		orderID := purchaseData.OrderIDs[orderIdx]
-
-		// Usually 1 receipt, sometimes 2 (partial deliveries)
-		numReceipts := gofakeit.Number(1, receiptsPerItem+1)
-
-		for j := 0; j < numReceipts; j++ {
-			qtyReceived := gofakeit.Number(10, 100)
-			receivedDate := now.AddDate(0, 0, -gofakeit.Number(0, 365))
-			receivedBy := gofakeit.Name()
-			notes := gofakeit.Sentence(10)
-
-			rows = append(rows, []interface{}{
-				uuid.New(),          // id
-				orderID,             // purchase_order_id
-				itemID,              // purchase_order_item_id
-				qtyReceived,         // quantity_received
-				receivedDate,        // received_date
-				&receivedBy,         // received_by
-				&notes,              // notes
-				now,                 // created_at
-			})
+		if receivedDate.After(now) {
+			receivedDate = now
 		}
-	}
+		receivedBy := rng.Name()
+		notes := rng.Sentence(10)
+
+		batch.Receipts = append(batch.Receipts, []interface{}{
+			rng.ID(),     // id
+			order.id,     // purchase_order_id
+			itemID,       // purchase_order_item_id
+			qtyReceived,  // quantity_received
+			receivedDate, // received_date
+			&receivedBy,  // received_by
+			&notes,       // notes
+			now,          // created_at
+		})
 
-	return rows
+		if idMap.Ledger != nil {
+			idMap.Ledger.at(product, order.warehouse).in += int64(qtyReceived)
+			batch.Transactions = append(batch.Transactions, inventoryTransactionRow(
+				rng, idMap.ProductIDs[product], order.warehouseID,
+				"purchase", int64(qtyReceived), &order.id, &purchaseOrderReference,
+				fmt.Sprintf("Received on PO-%010d", order.number), receivedDate,
+			))
+		}
+	}
 }
 
 // Purchase-related column functions
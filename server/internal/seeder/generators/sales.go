@@ -2,140 +2,245 @@ package generators
 
 import (
 	"fmt"
+	"iter"
 	"time"
 
-	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 )
 
-// SalesOrderData holds generated sales order IDs for item relationships
-type SalesOrderData struct {
-	OrderIDs []uuid.UUID
+// SalesOrderBatch holds a run of sales orders with their line items and
+// payments. Items and payments reference the orders by foreign key, so a
+// batch is written orders first. Transactions holds the inventory
+// transactions of shipped items when IDMap has a Ledger.
+type SalesOrderBatch struct {
+	Orders       [][]interface{}
+	Items        [][]interface{}
+	Payments     [][]interface{}
+	Transactions [][]interface{}
 }
 
-// GenerateSalesOrders creates sales order data
-func GenerateSalesOrders(count int, idMap *IDMap) ([][]interface{}, *SalesOrderData) {
-	rows := make([][]interface{}, 0, count)
-	data := &SalesOrderData{
-		OrderIDs: make([]uuid.UUID, 0, count),
-	}
-	now := time.Now()
+// GenerateSalesOrders streams count sales orders, starting with the order
+// at index first, in batches of up to batchSize orders, each with its items
+// and payments, so only one batch needs to be in memory at a time however
+// many orders there are.
+func GenerateSalesOrders(src *Source, first, count, batchSize int, idMap *IDMap, itemsPerOrder, paymentsPerOrder int) iter.Seq[*SalesOrderBatch] {
+	return func(yield func(*SalesOrderBatch) bool) {
+		for start := first; start < first+count; start += batchSize {
+			end := min(start+batchSize, first+count)
 
-	statuses := []string{"pending", "confirmed", "processing", "shipped", "delivered", "cancelled"}
+			batch := &SalesOrderBatch{
+				Orders:   make([][]interface{}, 0, end-start),
+				Items:    make([][]interface{}, 0, (end-start)*itemsPerOrder),
+				Payments: make([][]interface{}, 0, (end-start)*paymentsPerOrder),
+			}
+			for i := start; i < end; i++ {
+				// Each order draws from its own fork, so the data does not
+				// depend on the batch size and any order can be regenerated
+				rng := src.Fork("sales_orders", i)
+				order := newSalesOrder(i, idMap, rng)
+				appendSalesOrderItems(batch, order, idMap, itemsPerOrder, rng)
+				order.shipping = shippingFor(order.subtotal, rng)
+				batch.Orders = append(batch.Orders, order.row(rng.Now))
+				batch.Payments = appendSalesOrderPayments(batch.Payments, order, paymentsPerOrder, rng)
+			}
 
-	for i := 0; i < count; i++ {
-		id := uuid.New()
-		data.OrderIDs = append(data.OrderIDs, id)
+			if !yield(batch) {
+				return
+			}
+		}
+	}
+}
 
-		// Random customer
-		customerID := idMap.CustomerIDs[gofakeit.Number(0, len(idMap.CustomerIDs)-1)]
+var salesOrderStatuses = []string{"pending", "confirmed", "processing", "shipped", "delivered", "cancelled"}
 
-		// Random order date in the past year
-		orderDate := now.AddDate(0, 0, -gofakeit.Number(0, 365))
+// salesOrder is a sales order being generated. Its money fields are in
+// cents and summed from the items as they are generated.
+type salesOrder struct {
+	id         uuid.UUID
+	number     int
+	customerID uuid.UUID
+	date       time.Time
+	status     string
+	notes      string
 
-		status := statuses[gofakeit.Number(0, len(statuses)-1)]
-		notes := gofakeit.Sentence(15)
+	subtotal int64 // items after discounts, before tax
+	tax      int64
+	shipping int64
+}
 
-		// Totals will be calculated after items are generated
-		// For now, use placeholder values
-		rows = append(rows, []interface{}{
-			id,                               // id
-			fmt.Sprintf("SO-%010d", i+1),     // order_number
-			customerID,                       // customer_id
-			orderDate,                        // order_date
-			status,                           // status
-			0.0,                              // subtotal (will update)
-			0.0,                              // tax (will update)
-			0.0,                              // shipping (will update)
-			0.0,                              // total (will update)
-			&notes,                           // notes
-			now,                              // created_at
-			now,                              // updated_at
-			nil,                              // deleted_at
-		})
+// newSalesOrder starts the i-th sales order
+func newSalesOrder(i int, idMap *IDMap, rng *Source) *salesOrder {
+	return &salesOrder{
+		id:     rng.ID(),
+		number: i + 1,
+		// Customer by popularity
+		customerID: idMap.CustomerIDs[idMap.Distributions.customer(len(idMap.CustomerIDs), rng)],
+		// Order date in the order window, by season
+		date:   idMap.Distributions.orderDate(rng),
+		status: salesOrderStatuses[rng.Number(0, len(salesOrderStatuses)-1)],
+		notes:  rng.Sentence(15),
 	}
+}
 
-	return rows, data
+func (o *salesOrder) total() int64 {
+	return o.subtotal + o.tax + o.shipping
 }
 
-// GenerateSalesOrderItems creates sales order line items
-func GenerateSalesOrderItems(salesData *SalesOrderData, idMap *IDMap, itemsPerOrder int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(salesData.OrderIDs)*itemsPerOrder)
-	now := time.Now()
+func (o *salesOrder) row(now time.Time) []interface{} {
+	return []interface{}{
+		o.id,                              // id
+		fmt.Sprintf("SO-%010d", o.number), // order_number
+		o.customerID,                      // customer_id
+		o.date,                            // order_date
+		o.status,                          // status
+		dollars(o.subtotal),               // subtotal
+		dollars(o.tax),                    // tax
+		dollars(o.shipping),               // shipping
+		dollars(o.total()),                // total
+		&o.notes,                          // notes
+		now,                               // created_at
+		now,                               // updated_at
+		nil,                               // deleted_at
+	}
+}
 
-	for _, orderID := range salesData.OrderIDs {
-		// Generate 1-10 items per order
-		numItems := gofakeit.Number(1, itemsPerOrder+7)
+// appendSalesOrderItems appends the line items of one sales order to batch,
+// priced at each product's price on the order date, and adds them to the
+// order's subtotal and tax. With a ledger, items of shipped orders are
+// taken out of stock and those of orders still to ship are reserved.
+func appendSalesOrderItems(batch *SalesOrderBatch, order *salesOrder, idMap *IDMap, itemsPerOrder int, rng *Source) {
+	now := rng.Now
 
-		usedProducts := make(map[uuid.UUID]bool)
+	// Shipped within a few days of ordering
+	shipDate := order.date.AddDate(0, 0, rng.Number(1, 3))
+	if shipDate.After(now) {
+		shipDate = now
+	}
 
-		for j := 0; j < numItems; j++ {
-			productID := idMap.ProductIDs[gofakeit.Number(0, len(idMap.ProductIDs)-1)]
+	// Generate 1-10 items per order, or a long tail
+	numItems := idMap.Distributions.salesOrderItems(itemsPerOrder, rng)
 
-			// Avoid duplicate products in same order
-			if usedProducts[productID] {
-				continue
-			}
-			usedProducts[productID] = true
+	usedProducts := make(map[int]bool)
 
-			quantity := gofakeit.Number(1, 20)
-			unitPrice := float64(gofakeit.Number(500, 50000)) / 100.0
-			discount := 0.0
-			if gofakeit.Bool() {
-				discount = float64(gofakeit.Number(0, 2000)) / 100.0
-			}
-			tax := (unitPrice * float64(quantity) - discount) * 0.08 // 8% tax
-			total := (unitPrice * float64(quantity)) - discount + tax
-
-			rows = append(rows, []interface{}{
-				uuid.New(),     // id
-				orderID,        // sales_order_id
-				productID,      // product_id
-				quantity,       // quantity
-				unitPrice,      // unit_price
-				discount,       // discount
-				tax,            // tax
-				total,          // total
-				now,            // created_at
-				now,            // updated_at
-			})
+	for j := 0; j < numItems; j++ {
+		product := idMap.Distributions.product(len(idMap.ProductIDs), rng)
+
+		// Avoid duplicate products in same order
+		if usedProducts[product] {
+			continue
+		}
+		usedProducts[product] = true
+
+		quantity := rng.Number(1, 20)
+		unitPrice := idMap.Prices.At(product, order.date)
+		line := unitPrice * int64(quantity)
+		discount := int64(0)
+		if rng.Bool() {
+			discount = percentOf(line, float64(rng.Number(0, 20))) // up to 20% off
+		}
+		tax := percentOf(line-discount, 8) // 8% tax
+		total := line - discount + tax
+
+		order.subtotal += line - discount
+		order.tax += tax
+
+		batch.Items = append(batch.Items, []interface{}{
+			rng.ID(),                  // id
+			order.id,                  // sales_order_id
+			idMap.ProductIDs[product], // product_id
+			quantity,                  // quantity
+			dollars(unitPrice),        // unit_price
+			dollars(discount),         // discount
+			dollars(tax),              // tax
+			dollars(total),            // total
+			now,                       // created_at
+			now,                       // updated_at
+		})
+
+		if idMap.Ledger == nil {
+			continue
+		}
+		switch order.status {
+		case "shipped", "delivered":
+			stock := idMap.Ledger.pick(product, rng)
+			stock.out += int64(quantity)
+			batch.Transactions = append(batch.Transactions, inventoryTransactionRow(
+				rng, idMap.ProductIDs[product], idMap.WarehouseIDs[stock.warehouse],
+				"sale", -int64(quantity), &order.id, &salesOrderReference,
+				fmt.Sprintf("Shipped on SO-%010d", order.number), shipDate,
+			))
+		case "confirmed", "processing":
+			idMap.Ledger.pick(product, rng).reserved += int64(quantity)
 		}
 	}
+}
 
-	return rows
+// shippingFor returns the shipping charge for a subtotal: free from $100,
+// a flat rate below
+func shippingFor(subtotal int64, rng *Source) int64 {
+	if subtotal >= 100_00 {
+		return 0
+	}
+	return int64(rng.Number(499, 1499))
 }
 
-// GenerateSalesOrderPayments creates payment records
-func GenerateSalesOrderPayments(salesData *SalesOrderData, paymentsPerOrder int) [][]interface{} {
-	rows := make([][]interface{}, 0, len(salesData.OrderIDs)*paymentsPerOrder)
-	now := time.Now()
+var paymentMethods = []string{"credit_card", "debit_card", "paypal", "bank_transfer", "cash"}
+
+// appendSalesOrderPayments appends the payments of one sales order to rows,
+// reconciled with its total according to its status: a pending order has a
+// pending payment for the total, a cancelled one nothing taken or a full
+// refund, and any other completed payments summing to the total, split in
+// up to paymentsPerOrder+1 parts. Some orders show a failed attempt first.
+func appendSalesOrderPayments(rows [][]interface{}, order *salesOrder, paymentsPerOrder int, rng *Source) [][]interface{} {
+	now := rng.Now
+	total := order.total()
 
-	paymentMethods := []string{"credit_card", "debit_card", "paypal", "bank_transfer", "cash"}
-	paymentStatuses := []string{"pending", "completed", "failed", "refunded"}
+	add := func(amount int64, status string) {
+		method := paymentMethods[rng.Number(0, len(paymentMethods)-1)]
+		transactionID := fmt.Sprintf("TXN-%s", rng.ID().String()[:13])
 
-	for _, orderID := range salesData.OrderIDs {
+		// Paid within a few days of ordering
+		paymentDate := order.date.AddDate(0, 0, rng.Number(0, 3))
+		if paymentDate.After(now) {
+			paymentDate = now
+		}
+
+		rows = append(rows, []interface{}{
+			rng.ID(),        // id
+			order.id,        // sales_order_id
+			method,          // payment_method
+			dollars(amount), // amount
+			&transactionID,  // transaction_id
+			status,          // status
+			paymentDate,     // payment_date
+			now,             // created_at
+			now,             // updated_at
+		})
+	}
+
+	// About one order in ten has a declined attempt first
+	if rng.Number(1, 10) == 1 {
+		add(total, "failed")
+	}
+
+	switch order.status {
+	case "pending":
+		add(total, "pending")
+	case "cancelled":
+		// Cancelled after paying about half the time
+		if rng.Bool() {
+			add(total, "refunded")
+		}
+	default:
 		// Usually 1 payment, sometimes 2 (split payments)
-		numPayments := gofakeit.Number(1, paymentsPerOrder+1)
-
-		for j := 0; j < numPayments; j++ {
-			method := paymentMethods[gofakeit.Number(0, len(paymentMethods)-1)]
-			status := paymentStatuses[gofakeit.Number(0, len(paymentStatuses)-1)]
-			amount := float64(gofakeit.Number(1000, 100000)) / 100.0
-			transactionID := fmt.Sprintf("TXN-%s", uuid.New().String()[:13])
-			paymentDate := now.AddDate(0, 0, -gofakeit.Number(0, 365))
-
-			rows = append(rows, []interface{}{
-				uuid.New(),       // id
-				orderID,          // sales_order_id
-				method,           // payment_method
-				amount,           // amount
-				&transactionID,   // transaction_id
-				status,           // status
-				paymentDate,      // payment_date
-				now,              // created_at
-				now,              // updated_at
-			})
+		numPayments := rng.Number(1, paymentsPerOrder+1)
+		remaining := total
+		for j := 1; j < numPayments && remaining > 1; j++ {
+			part := max(percentOf(remaining, float64(rng.Number(20, 80))), 1)
+			add(part, "completed")
+			remaining -= part
 		}
+		add(remaining, "completed")
 	}
 
 	return rows
@@ -0,0 +1,82 @@
+package generators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// centsOf converts a DECIMAL(10,2) column value back to cents
+func centsOf(v interface{}) int64 {
+	return int64(math.Round(v.(float64) * 100))
+}
+
+func TestSalesOrderReconciliation(t *testing.T) {
+	tests := []struct {
+		name             string
+		paymentsPerOrder int
+		itemsTail        float64
+	}{
+		{name: "single payments", paymentsPerOrder: 0},
+		{name: "split payments", paymentsPerOrder: 3},
+		{name: "long tail of items", paymentsPerOrder: 1, itemsTail: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewSource(3, testNow)
+			_, idMap := testDataset(src, 50, 20)
+			idMap.Distributions = NewDistributions(src, idMap, 0, 0, nil, tt.itemsTail)
+
+			type sums struct{ items, completed, pending, refunded int64 }
+			for batch := range GenerateSalesOrders(src, 0, 500, 100, idMap, 4, tt.paymentsPerOrder) {
+				orders := make(map[uuid.UUID]*sums, len(batch.Orders))
+				for _, order := range batch.Orders {
+					orders[order[0].(uuid.UUID)] = &sums{}
+				}
+				for _, item := range batch.Items {
+					orders[item[1].(uuid.UUID)].items += centsOf(item[7])
+				}
+				for _, payment := range batch.Payments {
+					s := orders[payment[1].(uuid.UUID)]
+					switch payment[5] {
+					case "completed":
+						s.completed += centsOf(payment[3])
+					case "pending":
+						s.pending += centsOf(payment[3])
+					case "refunded":
+						s.refunded += centsOf(payment[3])
+					}
+				}
+
+				for _, order := range batch.Orders {
+					status, s := order[4].(string), orders[order[0].(uuid.UUID)]
+					subtotal, tax, shipping, total := centsOf(order[5]), centsOf(order[6]), centsOf(order[7]), centsOf(order[8])
+
+					if subtotal+tax+shipping != total {
+						t.Errorf("%s: subtotal %d + tax %d + shipping %d != total %d", order[1], subtotal, tax, shipping, total)
+					}
+					if s.items != subtotal+tax {
+						t.Errorf("%s: items total %d, want subtotal and tax %d", order[1], s.items, subtotal+tax)
+					}
+
+					switch status {
+					case "pending":
+						if s.pending != total || s.completed != 0 || s.refunded != 0 {
+							t.Errorf("%s: pending order has %+v, want %d pending", order[1], *s, total)
+						}
+					case "cancelled":
+						if s.completed != 0 || s.pending != 0 || s.refunded != 0 && s.refunded != total {
+							t.Errorf("%s: cancelled order has %+v, want nothing or a %d refund", order[1], *s, total)
+						}
+					default:
+						if s.completed != total || s.pending != 0 || s.refunded != 0 {
+							t.Errorf("%s: %s order has %+v, want %d completed", order[1], status, *s, total)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package generators
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/google/uuid"
+)
+
+// Source is where generators draw every random value, ID and timestamp
+// from, so a run is reproducible: the same seed, reference time and config
+// give the same rows.
+//
+// Each table, and each sales and purchase order, draws from its own fork of
+// the source, so what one table generates does not depend on how much
+// randomness the tables before it used, and an order can be regenerated
+// without the orders before it.
+type Source struct {
+	*gofakeit.Faker
+
+	// Now is the time the data is generated as of, used for created_at
+	// and updated_at and as the end of every date range
+	Now time.Time
+
+	seed uint64
+}
+
+// NewSource creates a source seeded with seed, generating data as of now
+func NewSource(seed uint64, now time.Time) *Source {
+	return &Source{
+		Faker: gofakeit.New(seed),
+		Now:   now,
+		seed:  seed,
+	}
+}
+
+// Fork returns the source for the n-th part of what label names, such as
+// a table or one batch of it. Forks derive from the seed alone, not from
+// the source forked, and are not safe for concurrent use.
+func (s *Source) Fork(label string, n int) *Source {
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, s.seed)
+	h.Write([]byte(label))
+	binary.Write(h, binary.LittleEndian, int64(n))
+
+	return &Source{
+		Faker: gofakeit.NewFaker(rand.NewPCG(s.seed, h.Sum64()), false),
+		Now:   s.Now,
+		seed:  s.seed,
+	}
+}
+
+// Seed returns the seed the source was created with
+func (s *Source) Seed() uint64 {
+	return s.seed
+}
+
+// ID returns a random (version 4) UUID drawn from the source
+func (s *Source) ID() uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[:8], s.Uint64())
+	binary.BigEndian.PutUint64(id[8:], s.Uint64())
+	id[6] = (id[6] & 0x0f) | 0x40 // version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // variant 10
+	return id
+}
@@ -0,0 +1,173 @@
+package generators
+
+import (
+	"iter"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// collect drains rows into a slice
+func collect(rows iter.Seq[[]interface{}]) [][]interface{} {
+	var all [][]interface{}
+	for row := range rows {
+		all = append(all, row)
+	}
+	return all
+}
+
+// testDataset generates master data for a few products and customers,
+// returning every table's rows by name along with the IDMap they fill
+func testDataset(src *Source, products, customers int) (map[string][][]interface{}, *IDMap) {
+	categories, idMap := GenerateCategories(src, 5)
+	tables := map[string][][]interface{}{"categories": collect(categories)}
+	tables["suppliers"] = collect(GenerateSuppliers(src, 3, idMap))
+	tables["warehouses"] = collect(GenerateWarehouses(src, 4, idMap))
+	tables["products"] = collect(GenerateProducts(src, products, idMap))
+	tables["product_prices"] = collect(GenerateProductPrices(src, idMap, 3))
+	tables["product_costs"] = collect(GenerateProductCosts(src, idMap, 2))
+	tables["customers"] = collect(GenerateCustomers(src, customers, idMap))
+	return tables, idMap
+}
+
+// testOrders generates count sales and purchase orders in batches of
+// batchSize, flattening the batches per table
+func testOrders(src *Source, idMap *IDMap, count, batchSize int) map[string][][]interface{} {
+	tables := make(map[string][][]interface{})
+	for batch := range GenerateSalesOrders(src, 0, count, batchSize, idMap, 4, 1) {
+		tables["sales_orders"] = append(tables["sales_orders"], batch.Orders...)
+		tables["sales_order_items"] = append(tables["sales_order_items"], batch.Items...)
+		tables["sales_order_payments"] = append(tables["sales_order_payments"], batch.Payments...)
+		tables["inventory_transactions"] = append(tables["inventory_transactions"], batch.Transactions...)
+	}
+	for batch := range GeneratePurchaseOrders(src, 0, count, batchSize, idMap, 45, 1) {
+		tables["purchase_orders"] = append(tables["purchase_orders"], batch.Orders...)
+		tables["purchase_order_items"] = append(tables["purchase_order_items"], batch.Items...)
+		tables["purchase_order_receipts"] = append(tables["purchase_order_receipts"], batch.Receipts...)
+		tables["inventory_transactions"] = append(tables["inventory_transactions"], batch.Transactions...)
+	}
+	if idMap.Ledger == nil {
+		delete(tables, "inventory_transactions")
+	}
+	return tables
+}
+
+func TestForkDeterminism(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   func() *Source
+		sameAs bool
+	}{
+		{
+			name:   "same seed, label and part",
+			a:      func() *Source { return NewSource(42, testNow).Fork("sales_orders", 7) },
+			b:      func() *Source { return NewSource(42, testNow).Fork("sales_orders", 7) },
+			sameAs: true,
+		},
+		{
+			name: "regardless of what the parent drew",
+			a:    func() *Source { return NewSource(42, testNow).Fork("sales_orders", 7) },
+			b: func() *Source {
+				src := NewSource(42, testNow)
+				src.Uint64()
+				src.Fork("sales_orders", 6).Uint64()
+				return src.Fork("sales_orders", 7)
+			},
+			sameAs: true,
+		},
+		{
+			name:   "of a fork",
+			a:      func() *Source { return NewSource(42, testNow).Fork("products", 0) },
+			b:      func() *Source { return NewSource(42, testNow).Fork("customers", 3).Fork("products", 0) },
+			sameAs: true,
+		},
+		{
+			name: "other part",
+			a:    func() *Source { return NewSource(42, testNow).Fork("sales_orders", 7) },
+			b:    func() *Source { return NewSource(42, testNow).Fork("sales_orders", 8) },
+		},
+		{
+			name: "other label",
+			a:    func() *Source { return NewSource(42, testNow).Fork("sales_orders", 7) },
+			b:    func() *Source { return NewSource(42, testNow).Fork("purchase_orders", 7) },
+		},
+		{
+			name: "other seed",
+			a:    func() *Source { return NewSource(42, testNow).Fork("sales_orders", 7) },
+			b:    func() *Source { return NewSource(43, testNow).Fork("sales_orders", 7) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a(), tt.b()
+			draws := func(src *Source) []interface{} {
+				return []interface{}{src.ID(), src.Uint64(), src.Name(), src.Number(0, 1000)}
+			}
+			got := reflect.DeepEqual(draws(a), draws(b))
+			if got != tt.sameAs {
+				t.Errorf("same draws = %v, want %v", got, tt.sameAs)
+			}
+			if a.Seed() != b.Seed() && tt.sameAs {
+				t.Errorf("seeds %d and %d differ", a.Seed(), b.Seed())
+			}
+		})
+	}
+}
+
+func TestSameSeedSameRows(t *testing.T) {
+	tests := []struct {
+		name       string
+		batchSizes [2]int
+		ledger     bool
+	}{
+		{name: "same batch size", batchSizes: [2]int{10, 10}},
+		{name: "other batch size", batchSizes: [2]int{1, 7}},
+		{name: "with ledger", batchSizes: [2]int{3, 50}, ledger: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs [2]map[string][][]interface{}
+			for i, batchSize := range tt.batchSizes {
+				src := NewSource(7, testNow)
+				tables, idMap := testDataset(src, 30, 20)
+				idMap.Distributions = NewDistributions(src, idMap, 1.1, 0.8, RetailSeasonality(), 1.5)
+				if tt.ledger {
+					idMap.Ledger = NewLedger(src, idMap, 2)
+				}
+				for table, rows := range testOrders(src, idMap, 25, batchSize) {
+					tables[table] = rows
+				}
+				if tt.ledger {
+					tables["inventory"] = collect(GenerateInventory(src, idMap, 2))
+					tables["inventory_transactions"] = append(tables["inventory_transactions"],
+						collect(GenerateInventoryTransactions(src, idMap, 0))...)
+				}
+				runs[i] = tables
+			}
+
+			if len(runs[0]) != len(runs[1]) {
+				t.Fatalf("generated %d and %d tables", len(runs[0]), len(runs[1]))
+			}
+			for table, rows := range runs[0] {
+				if len(rows) == 0 {
+					t.Errorf("%s: no rows generated", table)
+				}
+				if !reflect.DeepEqual(rows, runs[1][table]) {
+					t.Errorf("%s: rows differ between runs with the same seed", table)
+				}
+			}
+		})
+	}
+
+	t.Run("other seed", func(t *testing.T) {
+		a, _ := testDataset(NewSource(7, testNow), 30, 20)
+		b, _ := testDataset(NewSource(8, testNow), 30, 20)
+		if reflect.DeepEqual(a["products"], b["products"]) {
+			t.Error("products are the same with another seed")
+		}
+	})
+}
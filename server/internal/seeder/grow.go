@@ -0,0 +1,303 @@
+package seeder
+
+import (
+	"bananas/internal/seeder/generators"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GrowTables are the tables Grow appends to, in dependency order
+var GrowTables = []string{
+	"sales_orders", "sales_order_items", "sales_order_payments",
+	"purchase_orders", "purchase_order_items", "purchase_order_receipts",
+}
+
+// Grow appends the config's SalesOrders and PurchaseOrders to an already
+// seeded database, leaving what is there in place. The orders draw from the
+// suppliers, warehouses, products and customers in the database, priced
+// from their price and cost histories, and are numbered after the last
+// seeded order. The rest of the config shapes them as it does seeding.
+// With InventoryFromLedger the inventory is opened as a ledger: the orders
+// write their inventory transactions, levels they outgrow are restocked by
+// an adjustment, and inventory is updated to the new balances, so it still
+// reconciles with the transactions.
+func (s *Seeder) Grow(ctx context.Context) error {
+	c := s.config
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid seeding config: %w", err)
+	}
+	if c.SalesOrders == 0 && c.PurchaseOrders == 0 {
+		return errors.New("nothing to grow, set sales or purchase orders to append")
+	}
+	if c.InventoryFromLedger && len(c.Tables) > 0 {
+		// The ledger's tables move with the orders
+		grown := *c
+		grown.Tables = append(slices.Clone(c.Tables), "inventory", "inventory_transactions")
+		s.config, c = &grown, &grown
+	}
+
+	s.logger.Info(fmt.Sprintf("Growing by %d sales orders and %d purchase orders", c.SalesOrders, c.PurchaseOrders))
+	s.logger.Info(fmt.Sprintf("Random seed %d, as of %s", s.source.Seed(), s.source.Now.Format(time.RFC3339)))
+
+	s.logger.Info("Loading master data...")
+	idMap, err := loadIDMap(ctx, s.db.PGX, c.InventoryFromLedger)
+	if err != nil {
+		return err
+	}
+	s.logger.Info(fmt.Sprintf("Loaded %d suppliers, %d warehouses, %d products, %d customers",
+		len(idMap.SupplierIDs), len(idMap.WarehouseIDs), len(idMap.ProductIDs), len(idMap.CustomerIDs)))
+	idMap.Distributions = generators.NewDistributions(s.source, idMap, c.ProductZipf, c.CustomerZipf, c.OrderSeasonality, c.SalesOrderItemsTail)
+	if c.InventoryFromLedger {
+		if idMap.Ledger, err = loadLedger(ctx, s.db.PGX, idMap); err != nil {
+			return err
+		}
+	}
+
+	var grown []string
+	if c.SalesOrders > 0 {
+		if len(idMap.CustomerIDs) == 0 || len(idMap.ProductIDs) == 0 {
+			return errors.New("sales orders need seeded customers and priced, stocked products")
+		}
+		first, err := s.nextOrder(ctx, "sales_orders", "order_number", "SO-")
+		if err != nil {
+			return err
+		}
+		if err := s.writeSalesOrders(ctx, idMap, first, c.SalesOrders); err != nil {
+			return fmt.Errorf("failed to grow sales orders: %w", err)
+		}
+		grown = append(grown, GrowTables[:3]...)
+	}
+	if c.PurchaseOrders > 0 {
+		if len(idMap.SupplierIDs) == 0 || len(idMap.WarehouseIDs) == 0 || len(idMap.ProductIDs) == 0 {
+			return errors.New("purchase orders need seeded suppliers, warehouses and costed products")
+		}
+		first, err := s.nextOrder(ctx, "purchase_orders", "po_number", "PO-")
+		if err != nil {
+			return err
+		}
+		if err := s.writePurchaseOrders(ctx, idMap, first, c.PurchaseOrders); err != nil {
+			return fmt.Errorf("failed to grow purchase orders: %w", err)
+		}
+		grown = append(grown, GrowTables[3:]...)
+	}
+
+	if idMap.Ledger != nil {
+		if err := s.growInventory(ctx, idMap); err != nil {
+			return err
+		}
+		grown = append(grown, "inventory", "inventory_transactions")
+	}
+
+	// Nothing was staged, so this only analyzes the grown tables if asked
+	if err := s.inserter.Finalize(ctx, grown, nil); err != nil {
+		return fmt.Errorf("failed to finalize tables: %w", err)
+	}
+	s.reportStats()
+
+	s.logger.Info(fmt.Sprintf("Growing completed in %s", FormatDuration(s.progress.Elapsed())))
+	return nil
+}
+
+// growInventory restocks the levels the grown orders outgrew and updates
+// inventory to the ledger's balances, adding rows where receipts stocked a
+// product at a new warehouse. It runs in one transaction.
+func (s *Seeder) growInventory(ctx context.Context, idMap *generators.IDMap) error {
+	restock := idMap.Ledger.Restock(s.source, "inventory_restock", idMap, s.source.Now)
+	if err := s.insertBatch(ctx, "inventory_transactions", generators.InventoryTransactionColumns(), restock); err != nil {
+		return fmt.Errorf("failed to restock inventory: %w", err)
+	}
+	s.logger.Info(fmt.Sprintf("Restocked %d inventory levels", len(restock)))
+
+	var balances [][]interface{}
+	for b := range idMap.Ledger.Changes() {
+		balances = append(balances, []interface{}{
+			idMap.ProductIDs[b.Product], idMap.WarehouseIDs[b.Warehouse], int(b.Quantity), int(b.Reserved),
+		})
+	}
+
+	tx, err := s.db.PGX.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `CREATE TEMP TABLE inventory_balances (
+		product_id UUID, warehouse_id UUID, quantity INTEGER, reserved_quantity INTEGER
+	) ON COMMIT DROP`); err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"inventory_balances"},
+		[]string{"product_id", "warehouse_id", "quantity", "reserved_quantity"}, pgx.CopyFromRows(balances)); err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	updated, err := tx.Exec(ctx, `
+		UPDATE inventory i SET quantity = b.quantity, reserved_quantity = b.reserved_quantity, updated_at = $1
+		FROM inventory_balances b
+		WHERE i.product_id = b.product_id AND i.warehouse_id = b.warehouse_id`, s.source.Now)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+	added, err := tx.Exec(ctx, `
+		INSERT INTO inventory (product_id, warehouse_id, quantity, reserved_quantity, created_at, updated_at)
+		SELECT b.product_id, b.warehouse_id, b.quantity, b.reserved_quantity, $1, $1
+		FROM inventory_balances b
+		WHERE NOT EXISTS (SELECT 1 FROM inventory i WHERE i.product_id = b.product_id AND i.warehouse_id = b.warehouse_id)`, s.source.Now)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Updated %d inventory levels, added %d", updated.RowsAffected(), added.RowsAffected()))
+	return nil
+}
+
+// nextOrder returns the index of the order after the last one in table
+// numbered as the generators number them, prefix and ten digits, so grown
+// orders continue the numbering
+func (s *Seeder) nextOrder(ctx context.Context, table, numberColumn, prefix string) (int, error) {
+	query := fmt.Sprintf(`SELECT coalesce(max(substring(%[1]s from %[3]d)::bigint), 0)
+		FROM %[2]s WHERE %[1]s ~ '^%[4]s[0-9]{10}$'`, numberColumn, table, len(prefix)+1, prefix)
+
+	var last int64
+	if err := s.db.PGX.QueryRow(ctx, query).Scan(&last); err != nil {
+		return 0, fmt.Errorf("failed to find the last of %s: %w", table, err)
+	}
+	return int(last), nil
+}
+
+// loadIDMap reads the master data orders draw from out of the database.
+// Products are those with both a price and a cost, so every order line can
+// be priced, and if stocked, an inventory row for sales to ship from; each
+// list is ordered by ID, so the same data loads the same.
+func loadIDMap(ctx context.Context, db *pgxpool.Pool, stocked bool) (*generators.IDMap, error) {
+	idMap := &generators.IDMap{}
+
+	products := `SELECT id FROM products p
+		WHERE EXISTS (SELECT 1 FROM product_prices WHERE product_id = p.id)
+		AND EXISTS (SELECT 1 FROM product_costs WHERE product_id = p.id)`
+	if stocked {
+		products += `
+		AND EXISTS (SELECT 1 FROM inventory WHERE product_id = p.id)`
+	}
+
+	for _, list := range []struct {
+		ids   *[]uuid.UUID
+		query string
+	}{
+		{&idMap.SupplierIDs, "SELECT id FROM suppliers ORDER BY id"},
+		{&idMap.WarehouseIDs, "SELECT id FROM warehouses ORDER BY id"},
+		{&idMap.CustomerIDs, "SELECT id FROM customers WHERE deleted_at IS NULL ORDER BY id"},
+		{&idMap.ProductIDs, products + " ORDER BY id"},
+	} {
+		rows, err := db.Query(ctx, list.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load master data: %w", err)
+		}
+		if *list.ids, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID]); err != nil {
+			return nil, fmt.Errorf("failed to load master data: %w", err)
+		}
+	}
+
+	index := indexOf(idMap.ProductIDs)
+	var err error
+	if idMap.Prices, err = loadPriceHistory(ctx, db, "product_prices", "price", index); err != nil {
+		return nil, err
+	}
+	if idMap.Costs, err = loadPriceHistory(ctx, db, "product_costs", "cost", index); err != nil {
+		return nil, err
+	}
+	return idMap, nil
+}
+
+// loadPriceHistory reads the price or cost history of the products, indexed
+// by products, out of table. An entry without an effective date counts as
+// the oldest.
+func loadPriceHistory(ctx context.Context, db *pgxpool.Pool, table, column string, products map[uuid.UUID]int) (*generators.PriceHistory, error) {
+	rows, err := db.Query(ctx, fmt.Sprintf(
+		`SELECT product_id, (%s * 100)::bigint, coalesce(effective_date, 'epoch')
+		FROM %s ORDER BY product_id, 3 DESC`,
+		column, table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	type entries struct {
+		cents     []int64
+		effective []time.Time
+	}
+	history := make([]entries, len(products))
+	per := 0
+	for rows.Next() {
+		var productID uuid.UUID
+		var cents int64
+		var effective time.Time
+		if err := rows.Scan(&productID, &cents, &effective); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", table, err)
+		}
+		i, ok := products[productID]
+		if !ok {
+			continue
+		}
+		history[i].cents = append(history[i].cents, cents)
+		history[i].effective = append(history[i].effective, effective)
+		per = max(per, len(history[i].cents))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", table, err)
+	}
+
+	h := generators.NewPriceHistory(len(products), per)
+	for _, e := range history {
+		h.AddProduct(e.cents, e.effective)
+	}
+	return h, nil
+}
+
+// loadLedger opens a ledger with the inventory of the products and
+// warehouses in idMap
+func loadLedger(ctx context.Context, db *pgxpool.Pool, idMap *generators.IDMap) (*generators.Ledger, error) {
+	rows, err := db.Query(ctx, "SELECT product_id, warehouse_id, quantity, reserved_quantity FROM inventory")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load inventory: %w", err)
+	}
+	defer rows.Close()
+
+	products, warehouses := indexOf(idMap.ProductIDs), indexOf(idMap.WarehouseIDs)
+	ledger := generators.NewOpenLedger(len(idMap.ProductIDs))
+	for rows.Next() {
+		var productID, warehouseID uuid.UUID
+		var quantity, reserved int64
+		if err := rows.Scan(&productID, &warehouseID, &quantity, &reserved); err != nil {
+			return nil, fmt.Errorf("failed to load inventory: %w", err)
+		}
+		product, ok := products[productID]
+		warehouse, stocked := warehouses[warehouseID]
+		if ok && stocked {
+			ledger.Open(product, warehouse, quantity, reserved)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to load inventory: %w", err)
+	}
+	return ledger, nil
+}
+
+// indexOf maps each ID to its index in ids
+func indexOf(ids []uuid.UUID) map[uuid.UUID]int {
+	index := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+	return index
+}
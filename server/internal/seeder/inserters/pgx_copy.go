@@ -1,22 +1,58 @@
 package inserters
 
 import (
+	"bananas/internal/logger"
 	"context"
 	"fmt"
+	"io"
+	"iter"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/sync/errgroup"
 )
 
+// Options tunes how PGXCopyInserter loads tables
+type Options struct {
+	// Workers is the number of pool connections batches are fanned out
+	// to. 1 or less sends batches one after another on one connection.
+	// The pool's MaxConns caps how many actually run at once.
+	Workers int
+	// DropIndexes drops secondary indexes in Stage and rebuilds them in
+	// Finalize
+	DropIndexes bool
+	// Unlogged switches tables to UNLOGGED in Stage and back to LOGGED in
+	// Finalize, skipping WAL while loading
+	Unlogged bool
+	// Analyze runs ANALYZE on every table in Finalize
+	Analyze bool
+}
+
 // PGXCopyInserter uses PostgreSQL COPY protocol for maximum performance
 type PGXCopyInserter struct {
 	pool *pgxpool.Pool
+	opts Options
+	log  logger.Logger
+
+	mu      sync.Mutex
+	stats   map[string]*TableStats
+	order   []string
+	indexes map[string][]string
 }
 
 // NewPGXCopyInserter creates a new PGX COPY inserter
-func NewPGXCopyInserter(pool *pgxpool.Pool) *PGXCopyInserter {
-	return &PGXCopyInserter{pool: pool}
+func NewPGXCopyInserter(pool *pgxpool.Pool, opts Options) *PGXCopyInserter {
+	return &PGXCopyInserter{
+		pool:    pool,
+		opts:    opts,
+		log:     logger.New("inserter"),
+		stats:   make(map[string]*TableStats),
+		indexes: make(map[string][]string),
+	}
 }
 
 // BulkInsert performs a bulk insert using COPY protocol
@@ -50,22 +86,192 @@ func (p *PGXCopyInserter) BulkInsert(ctx context.Context, tableName string, colu
 
 // BulkInsertBatched performs a bulk insert in batches
 func (p *PGXCopyInserter) BulkInsertBatched(ctx context.Context, tableName string, columns []string, rows [][]interface{}, batchSize int) error {
-	if len(rows) == 0 {
-		return nil
+	_, err := p.CopyRows(ctx, tableName, columns, slices.Values(rows), batchSize, nil)
+	return err
+}
+
+// CopyRows streams rows into a table with one COPY per batch of up to
+// batchSize rows. Serially, each row is pulled from the sequence only as
+// COPY sends it; in parallel mode batches are collected and handed to
+// Workers connections, so about two batches per worker are in memory.
+// written, when not nil, is called with the size of every batch once it is
+// in, never concurrently. It returns the number of rows copied.
+func (p *PGXCopyInserter) CopyRows(ctx context.Context, tableName string, columns []string, rows iter.Seq[[]interface{}], batchSize int, written func(int)) (int, error) {
+	start := time.Now()
+
+	var n int
+	var err error
+	if p.opts.Workers > 1 {
+		n, err = p.copyParallel(ctx, tableName, columns, rows, batchSize, written)
+	} else {
+		n, err = p.copySerial(ctx, tableName, columns, rows, batchSize, written)
+	}
+
+	p.record(tableName, n, time.Since(start))
+	return n, err
+}
+
+// CopyReader loads a table with one COPY from r, which holds the output of
+// COPY TO in format, such as binary, for the same columns. It returns the
+// number of rows copied.
+func (p *PGXCopyInserter) CopyReader(ctx context.Context, tableName string, columns []string, format string, r io.Reader) (int64, error) {
+	start := time.Now()
+
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	identifiers := make([]string, len(columns))
+	for i, column := range columns {
+		identifiers[i] = pgx.Identifier{column}.Sanitize()
+	}
+	query := fmt.Sprintf("COPY %s (%s) FROM STDIN (FORMAT %s)",
+		pgx.Identifier{tableName}.Sanitize(), strings.Join(identifiers, ", "), format)
+
+	tag, err := conn.Conn().PgConn().CopyFrom(ctx, r, query)
+	p.record(tableName, int(tag.RowsAffected()), time.Since(start))
+	if err != nil {
+		return tag.RowsAffected(), fmt.Errorf("copy failed: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (p *PGXCopyInserter) copySerial(ctx context.Context, tableName string, columns []string, rows iter.Seq[[]interface{}], batchSize int, written func(int)) (int, error) {
+	next, stop := iter.Pull(rows)
+	defer stop()
+
+	total := 0
+	for {
+		// Peek at the first row so a sequence that ends on a batch
+		// boundary does not cost an empty COPY
+		first, ok := next()
+		if !ok {
+			return total, nil
+		}
+
+		src := &batchSource{next: next, pending: first, limit: batchSize}
+		n, err := p.copyFrom(ctx, tableName, columns, src)
+		if err != nil {
+			return total, fmt.Errorf("batch insert failed at offset %d: %w", total, err)
+		}
+
+		total += int(n)
+		if written != nil {
+			written(int(n))
+		}
+	}
+}
+
+func (p *PGXCopyInserter) copyParallel(ctx context.Context, tableName string, columns []string, rows iter.Seq[[]interface{}], batchSize int, written func(int)) (int, error) {
+	type batch struct {
+		offset int
+		rows   [][]interface{}
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	batches := make(chan batch, p.opts.Workers)
+
+	var mu sync.Mutex
+	total := 0
+	for range p.opts.Workers {
+		g.Go(func() error {
+			for b := range batches {
+				n, err := p.copyFrom(ctx, tableName, columns, pgx.CopyFromRows(b.rows))
+				if err != nil {
+					return fmt.Errorf("batch insert failed at offset %d: %w", b.offset, err)
+				}
+
+				mu.Lock()
+				total += int(n)
+				if written != nil {
+					written(int(n))
+				}
+				mu.Unlock()
+			}
+			return nil
+		})
 	}
 
-	for i := 0; i < len(rows); i += batchSize {
-		end := i + batchSize
-		if end > len(rows) {
-			end = len(rows)
+	g.Go(func() error {
+		defer close(batches)
+
+		offset := 0
+		current := make([][]interface{}, 0, batchSize)
+		send := func() bool {
+			select {
+			case batches <- batch{offset: offset, rows: current}:
+				offset += len(current)
+				current = make([][]interface{}, 0, batchSize)
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for row := range rows {
+			current = append(current, row)
+			if len(current) == batchSize && !send() {
+				return nil
+			}
+		}
+		if len(current) > 0 {
+			send()
 		}
+		return nil
+	})
+
+	err := g.Wait()
+	return total, err
+}
 
-		batch := rows[i:end]
-		if err := p.BulkInsert(ctx, tableName, columns, batch); err != nil {
-			return fmt.Errorf("batch insert failed at offset %d: %w", i, err)
+func (p *PGXCopyInserter) copyFrom(ctx context.Context, tableName string, columns []string, src pgx.CopyFromSource) (int64, error) {
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	n, err := conn.Conn().CopyFrom(ctx, pgx.Identifier{tableName}, columns, src)
+	if err != nil {
+		return n, fmt.Errorf("copy failed: %w", err)
+	}
+	return n, nil
+}
+
+// batchSource is a pgx.CopyFromSource over the next limit rows of a pulled
+// sequence, starting with the row already pulled into pending.
+type batchSource struct {
+	next    func() ([]interface{}, bool)
+	pending []interface{}
+	row     []interface{}
+	limit   int
+	sent    int
+}
+
+func (b *batchSource) Next() bool {
+	if b.sent >= b.limit {
+		return false
+	}
+	if b.pending != nil {
+		b.row, b.pending = b.pending, nil
+	} else {
+		row, ok := b.next()
+		if !ok {
+			return false
 		}
+		b.row = row
 	}
+	b.sent++
+	return true
+}
+
+func (b *batchSource) Values() ([]interface{}, error) {
+	return b.row, nil
+}
 
+func (b *batchSource) Err() error {
 	return nil
 }
 
@@ -0,0 +1,281 @@
+package inserters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/errgroup"
+)
+
+// TableStats is what loading one table took
+type TableStats struct {
+	Table string
+	Rows  int
+	// Copy is the time spent in COPY, summed over every call that wrote
+	// to the table
+	Copy time.Duration
+	// Index is the time spent rebuilding the table's dropped indexes
+	Index time.Duration
+	// Analyze is the time spent in ANALYZE
+	Analyze time.Duration
+}
+
+// RowsPerSec returns the COPY throughput
+func (s TableStats) RowsPerSec() float64 {
+	if s.Copy <= 0 {
+		return 0
+	}
+	return float64(s.Rows) / s.Copy.Seconds()
+}
+
+// secondaryIndexes lists the indexes of a table that no constraint owns,
+// which are safe to drop and recreate from their definition.
+const secondaryIndexes = `
+	SELECT i.relname, pg_get_indexdef(i.oid)
+	FROM pg_index x
+	JOIN pg_class i ON i.oid = x.indexrelid
+	WHERE x.indrelid = $1::regclass
+	  AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = x.indexrelid)
+	ORDER BY i.relname`
+
+// loggedReferences lists the foreign keys referencing tables, with whether
+// the referencing table is LOGGED
+const loggedReferences = `
+	SELECT DISTINCT c.conrelid::regclass::text, c.confrelid::regclass::text, r.relpersistence = 'p'
+	FROM pg_constraint c
+	JOIN pg_class r ON r.oid = c.conrelid
+	WHERE c.contype = 'f'
+	  AND c.conrelid <> c.confrelid
+	  AND c.confrelid = ANY($1::text[]::regclass[])`
+
+// Stage readies tables for loading as the options ask: secondary indexes
+// are dropped, their definitions kept for Finalize, and tables switched to
+// UNLOGGED. tables must be in dependency order, parents first; they are
+// staged children first, as PostgreSQL refuses to make a table UNLOGGED
+// while a LOGGED table references it. For that reason tables referenced by
+// a LOGGED table not in tables stay LOGGED, as do the tables they
+// reference in turn, which Stage logs. If loading fails before Finalize the
+// dropped indexes are gone, and running the migrations again restores them,
+// as does Finalize after RestoreIndexes with what DroppedIndexes returned.
+func (p *PGXCopyInserter) Stage(ctx context.Context, tables []string) error {
+	if !p.opts.DropIndexes && !p.opts.Unlogged {
+		return nil
+	}
+
+	conn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	var logged []string
+	if p.opts.Unlogged {
+		if logged, err = keptLogged(ctx, conn.Conn(), tables); err != nil {
+			return err
+		}
+		for _, table := range logged {
+			p.log.Info(fmt.Sprintf("Keeping %s LOGGED, a LOGGED table outside those loaded references it", table))
+		}
+	}
+
+	for _, table := range slices.Backward(tables) {
+		if p.opts.DropIndexes {
+			rows, err := conn.Query(ctx, secondaryIndexes, table)
+			if err != nil {
+				return fmt.Errorf("failed to list indexes of %s: %w", table, err)
+			}
+			type index struct{ name, def string }
+			indexes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (index, error) {
+				var ix index
+				err := row.Scan(&ix.name, &ix.def)
+				return ix, err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list indexes of %s: %w", table, err)
+			}
+
+			for _, ix := range indexes {
+				if _, err := conn.Exec(ctx, "DROP INDEX "+pgx.Identifier{ix.name}.Sanitize()); err != nil {
+					return fmt.Errorf("failed to drop index %s: %w", ix.name, err)
+				}
+				if !slices.Contains(p.indexes[table], ix.def) {
+					p.indexes[table] = append(p.indexes[table], ix.def)
+				}
+			}
+		}
+
+		if p.opts.Unlogged && !slices.Contains(logged, table) {
+			if _, err := conn.Exec(ctx, "ALTER TABLE "+pgx.Identifier{table}.Sanitize()+" SET UNLOGGED"); err != nil {
+				return fmt.Errorf("failed to set %s unlogged: %w", table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// keptLogged returns the tables among tables that must stay LOGGED: those
+// a LOGGED table outside tables references, directly or through tables that
+// stay LOGGED themselves
+func keptLogged(ctx context.Context, conn *pgx.Conn, tables []string) ([]string, error) {
+	rows, err := conn.Query(ctx, loggedReferences, tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references to tables: %w", err)
+	}
+	type reference struct {
+		child, parent string
+		logged        bool
+	}
+	references, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (reference, error) {
+		var r reference
+		err := row.Scan(&r.child, &r.parent, &r.logged)
+		return r, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references to tables: %w", err)
+	}
+
+	var logged []string
+	for grew := true; grew; {
+		grew = false
+		for _, r := range references {
+			if slices.Contains(logged, r.parent) {
+				continue
+			}
+			if r.logged && !slices.Contains(tables, r.child) || slices.Contains(logged, r.child) {
+				logged = append(logged, r.parent)
+				grew = true
+			}
+		}
+	}
+	return logged, nil
+}
+
+// DroppedIndexes returns the definitions of the indexes Stage dropped and
+// Finalize has yet to rebuild, per table
+func (p *PGXCopyInserter) DroppedIndexes() map[string][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	indexes := make(map[string][]string, len(p.indexes))
+	for table, defs := range p.indexes {
+		indexes[table] = slices.Clone(defs)
+	}
+	return indexes
+}
+
+// RestoreIndexes adds the definitions of indexes an earlier, failed load
+// dropped to those Finalize rebuilds
+func (p *PGXCopyInserter) RestoreIndexes(indexes map[string][]string) {
+	for table, defs := range indexes {
+		for _, def := range defs {
+			if !slices.Contains(p.indexes[table], def) {
+				p.indexes[table] = append(p.indexes[table], def)
+			}
+		}
+	}
+}
+
+// Finalize undoes Stage once tables are loaded, parents first: tables go
+// back to LOGGED, then dropped indexes are rebuilt, up to Workers at a time,
+// and every table is analyzed if asked. An index that already exists, as
+// one rebuilt by an earlier, failed Finalize does, is left as it is.
+// rebuilt, if not nil, is called after each index is rebuilt with what
+// DroppedIndexes then returns, one call at a time.
+func (p *PGXCopyInserter) Finalize(ctx context.Context, tables []string, rebuilt func(context.Context, map[string][]string) error) error {
+	if p.opts.Unlogged {
+		for _, table := range tables {
+			if _, err := p.pool.Exec(ctx, "ALTER TABLE "+pgx.Identifier{table}.Sanitize()+" SET LOGGED"); err != nil {
+				return fmt.Errorf("failed to set %s logged: %w", table, err)
+			}
+		}
+	}
+
+	var recording sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(1, p.opts.Workers))
+	dropped := p.DroppedIndexes()
+	for _, table := range tables {
+		for _, def := range dropped[table] {
+			g.Go(func() error {
+				start := time.Now()
+				if _, err := p.pool.Exec(gctx, ifNotExists(def)); err != nil {
+					return fmt.Errorf("failed to rebuild index on %s: %w", table, err)
+				}
+				p.tableStats(table, func(s *TableStats) { s.Index += time.Since(start) })
+
+				recording.Lock()
+				defer recording.Unlock()
+				p.mu.Lock()
+				p.indexes[table] = slices.DeleteFunc(p.indexes[table], func(d string) bool { return d == def })
+				if len(p.indexes[table]) == 0 {
+					delete(p.indexes, table)
+				}
+				p.mu.Unlock()
+				if rebuilt == nil {
+					return nil
+				}
+				return rebuilt(gctx, p.DroppedIndexes())
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if p.opts.Analyze {
+		for _, table := range tables {
+			start := time.Now()
+			if _, err := p.pool.Exec(ctx, "ANALYZE "+pgx.Identifier{table}.Sanitize()); err != nil {
+				return fmt.Errorf("failed to analyze %s: %w", table, err)
+			}
+			p.tableStats(table, func(s *TableStats) { s.Analyze += time.Since(start) })
+		}
+	}
+
+	return nil
+}
+
+// ifNotExists turns a pg_get_indexdef definition, CREATE [UNIQUE] INDEX
+// name ON ..., into one that skips an index of that name already there
+func ifNotExists(def string) string {
+	return strings.Replace(def, " INDEX ", " INDEX IF NOT EXISTS ", 1)
+}
+
+// Stats returns what loading took per table, in the order tables were
+// first written
+func (p *PGXCopyInserter) Stats() []TableStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]TableStats, len(p.order))
+	for i, table := range p.order {
+		stats[i] = *p.stats[table]
+	}
+	return stats
+}
+
+func (p *PGXCopyInserter) record(table string, rows int, elapsed time.Duration) {
+	p.tableStats(table, func(s *TableStats) {
+		s.Rows += rows
+		s.Copy += elapsed
+	})
+}
+
+func (p *PGXCopyInserter) tableStats(table string, update func(*TableStats)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stats[table]
+	if !ok {
+		s = &TableStats{Table: table}
+		p.stats[table] = s
+		p.order = append(p.order, table)
+	}
+	update(s)
+}
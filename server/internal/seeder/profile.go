@@ -0,0 +1,79 @@
+package seeder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles lists the built-in profile names, smallest first
+var Profiles = []string{"tiny", "small", "medium", "default", "huge"}
+
+var profileConfigs = map[string]func() *Config{
+	"tiny":    TinyConfig,
+	"small":   SmallConfig,
+	"medium":  MediumConfig,
+	"default": DefaultConfig,
+	"huge":    HugeConfig,
+}
+
+// profileFile is a YAML profile: a built-in profile to start from, "default"
+// unless set, with any Config fields overridden
+type profileFile struct {
+	Base   string `yaml:"base"`
+	Config `yaml:",inline"`
+}
+
+// LoadProfile returns the validated config of a built-in profile, or of the
+// YAML profile file at that path. For example:
+//
+//	base: medium
+//	random_seed: 42
+//	sales_orders: 5000000
+//	product_zipf: 1.2
+//	order_seasonality: null
+func LoadProfile(profile string) (*Config, error) {
+	if newConfig, ok := profileConfigs[profile]; ok {
+		c := newConfig()
+		return c, c.Validate()
+	}
+
+	data, err := os.ReadFile(profile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unknown profile %q: not one of %s, nor a YAML file", profile, strings.Join(Profiles, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+
+	// Read the base first, so the file's fields override it
+	var base struct {
+		Base string `yaml:"base"`
+	}
+	if err := yaml.Unmarshal(data, &base); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", profile, err)
+	}
+	if base.Base == "" {
+		base.Base = "default"
+	}
+	newConfig, ok := profileConfigs[base.Base]
+	if !ok {
+		return nil, fmt.Errorf("profile %s: unknown base %q, use one of %s", profile, base.Base, strings.Join(Profiles, ", "))
+	}
+
+	file := profileFile{Config: *newConfig()}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %s: %w", profile, err)
+	}
+
+	if err := file.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", profile, err)
+	}
+	return &file.Config, nil
+}
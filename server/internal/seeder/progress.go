@@ -11,6 +11,8 @@ import (
 type ProgressTracker struct {
 	currentBar *progressbar.ProgressBar
 	startTime  time.Time
+	total      int
+	written    int
 }
 
 // NewProgressTracker creates a new progress tracker
@@ -20,8 +22,13 @@ func NewProgressTracker() *ProgressTracker {
 	}
 }
 
-// StartTable starts tracking progress for a table
+// StartTable starts tracking progress for a table. total may be an estimate
+// for tables whose row count is only known once generated; the bar grows
+// if more rows than that are written.
 func (pt *ProgressTracker) StartTable(tableName string, total int) {
+	total = max(total, 1)
+	pt.total = total
+	pt.written = 0
 	pt.currentBar = progressbar.NewOptions(total,
 		progressbar.OptionEnableColorCodes(true),
 		progressbar.OptionShowBytes(false),
@@ -44,6 +51,11 @@ func (pt *ProgressTracker) StartTable(tableName string, total int) {
 // Add increments the progress bar
 func (pt *ProgressTracker) Add(count int) error {
 	if pt.currentBar != nil {
+		pt.written += count
+		if pt.written > pt.total {
+			pt.total = pt.written
+			pt.currentBar.ChangeMax(pt.total)
+		}
 		return pt.currentBar.Add(count)
 	}
 	return nil
@@ -62,6 +74,20 @@ func (pt *ProgressTracker) Elapsed() time.Duration {
 	return time.Since(pt.startTime)
 }
 
+// FormatBytes formats a byte count for display
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 // FormatDuration formats a duration for display
 func FormatDuration(d time.Duration) string {
 	if d < time.Minute {
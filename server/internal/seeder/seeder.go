@@ -6,9 +6,27 @@ import (
 	"bananas/internal/seeder/generators"
 	"bananas/internal/seeder/inserters"
 	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// Tables lists the seeded tables in dependency order, parents first
+var Tables = []string{
+	"categories", "suppliers", "warehouses",
+	"products", "product_categories", "product_prices", "product_costs", "supplier_products",
+	"customers", "customer_addresses",
+	"sales_orders", "sales_order_items", "sales_order_payments",
+	"purchase_orders", "purchase_order_items", "purchase_order_receipts",
+	"inventory", "inventory_transactions",
+}
+
 // Seeder orchestrates the seeding process
 type Seeder struct {
 	db       *database.DB
@@ -16,23 +34,245 @@ type Seeder struct {
 	inserter *inserters.PGXCopyInserter
 	logger   logger.Logger
 	progress *ProgressTracker
+	source   *generators.Source
+
+	// checkpoints records progress for Resume, and resuming is set while
+	// it runs
+	checkpoints *checkpoints
+	resuming    bool
 }
 
 // New creates a new seeder
 func New(db *database.DB, config *Config) *Seeder {
+	inserter := inserters.NewPGXCopyInserter(db.PGX, inserters.Options{
+		Workers:     config.CopyWorkers,
+		DropIndexes: config.DropIndexes,
+		Unlogged:    config.UnloggedStaging,
+		Analyze:     config.Analyze,
+	})
+
 	return &Seeder{
 		db:       db,
 		config:   config,
-		inserter: inserters.NewPGXCopyInserter(db.PGX),
+		inserter: inserter,
 		logger:   logger.New("seeder"),
 		progress: NewProgressTracker(),
+		source:   newSource(config),
+
+		checkpoints: newCheckpoints(db.PGX),
+	}
+}
+
+// newSource seeds generation from config, picking a seed when none is set
+func newSource(config *Config) *generators.Source {
+	seed, asOf := config.RandomSeed, config.AsOf
+	if asOf.IsZero() {
+		asOf = DefaultAsOf
+		if seed == 0 {
+			asOf = time.Now().UTC().Truncate(time.Second)
+		}
+	}
+	for seed == 0 {
+		seed = rand.Uint64()
+	}
+	return generators.NewSource(seed, asOf)
+}
+
+// tables returns the tables seeded, in dependency order
+func (s *Seeder) tables() []string {
+	tables := make([]string, 0, len(Tables))
+	for _, table := range Tables {
+		if s.config.writes(table) {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// Truncate empties the seeded tables before seeding them. Tables that are
+// not seeded but reference them, by foreign key or as the orders of
+// inventory transactions, keep their rows: Truncate fails, naming them,
+// if any has rows, and empties them along with the seeded tables if not.
+func (s *Seeder) Truncate(ctx context.Context) error {
+	tables := s.tables()
+
+	referencing, err := s.referencingTables(ctx, tables)
+	if err != nil {
+		return err
+	}
+	var kept []string
+	for _, table := range referencing {
+		var rows bool
+		query := "SELECT EXISTS (SELECT 1 FROM " + pgx.Identifier{table}.Sanitize() + ")"
+		if err := s.db.PGX.QueryRow(ctx, query).Scan(&rows); err != nil {
+			return fmt.Errorf("failed to check %s: %w", table, err)
+		}
+		if rows {
+			kept = append(kept, table)
+		}
+	}
+	if !slices.Contains(tables, "inventory_transactions") && !slices.Contains(kept, "inventory_transactions") {
+		for table, referenceType := range orderReferences {
+			if !slices.Contains(tables, table) {
+				continue
+			}
+			var rows bool
+			if err := s.db.PGX.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM inventory_transactions WHERE reference_type = $1)", referenceType).Scan(&rows); err != nil {
+				return fmt.Errorf("failed to check inventory_transactions: %w", err)
+			}
+			if rows {
+				kept = append(kept, "inventory_transactions")
+				break
+			}
+		}
+	}
+	if len(kept) > 0 {
+		return fmt.Errorf("cannot empty %s: %s reference them and are not seeded, seed them too or empty them first",
+			strings.Join(tables, ", "), strings.Join(kept, ", "))
 	}
+
+	// The tables referencing the seeded tables are empty, but TRUNCATE
+	// needs them listed
+	tables = append(tables, referencing...)
+	s.logger.Info(fmt.Sprintf("Truncating %s", strings.Join(tables, ", ")))
+
+	identifiers := make([]string, len(tables))
+	for i, table := range tables {
+		identifiers[i] = pgx.Identifier{table}.Sanitize()
+	}
+	if _, err := s.db.PGX.Exec(ctx, "TRUNCATE "+strings.Join(identifiers, ", ")); err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	return nil
+}
+
+// orderReferences are the reference_type inventory transactions reference
+// the rows of each order table by
+var orderReferences = map[string]string{
+	"sales_orders":    "sales_order",
+	"purchase_orders": "purchase_order",
+}
+
+// referencingTables returns the tables outside tables that reference them
+// by foreign key, directly or through one another
+func (s *Seeder) referencingTables(ctx context.Context, tables []string) ([]string, error) {
+	keys, err := foreignKeys(ctx, s.db.PGX)
+	if err != nil {
+		return nil, err
+	}
+
+	var referencing []string
+	for grew := true; grew; {
+		grew = false
+		for child, parents := range keys {
+			if slices.Contains(tables, child) || slices.Contains(referencing, child) {
+				continue
+			}
+			for _, parent := range parents {
+				if slices.Contains(tables, parent) || slices.Contains(referencing, parent) {
+					referencing = append(referencing, child)
+					grew = true
+					break
+				}
+			}
+		}
+	}
+	slices.Sort(referencing)
+	return referencing, nil
 }
 
-// SeedAll seeds all tables with data
+// SeedAll seeds all tables with data, recording its progress in
+// seed_progress so a run that fails can be resumed
 func (s *Seeder) SeedAll(ctx context.Context) error {
+	if err := s.config.Validate(); err != nil {
+		return fmt.Errorf("invalid seeding config: %w", err)
+	}
+
+	// A failed run may have left indexes dropped, which this run rebuilds
+	// along with its own
+	last, err := s.checkpoints.load(ctx)
+	if err == nil {
+		s.inserter.RestoreIndexes(s.checkpoints.droppedIndexes())
+	} else if !errors.Is(err, errNoRun) {
+		return err
+	}
+
+	// Selected tables reference the IDs of the others, which only their
+	// seed generates again
+	if len(s.config.Tables) > 0 && s.config.RandomSeed == 0 {
+		if err != nil {
+			return errors.New("seeding selected tables needs the seed the others were seeded with, and seed_progress records none: set the seed")
+		}
+		asOf := s.config.AsOf
+		if asOf.IsZero() {
+			asOf = last.asOf
+		}
+		s.source = generators.NewSource(last.seed, asOf)
+		s.logger.Info(fmt.Sprintf("Seeding with seed %d, as of %s, of the last run", last.seed, asOf.Format(time.RFC3339)))
+	}
+
+	r := run{seed: s.source.Seed(), asOf: s.source.Now, fingerprint: fingerprint(s.config)}
+	if err := s.checkpoints.begin(ctx, r, s.tables()); err != nil {
+		return err
+	}
+
 	s.logger.Info("Starting full database seeding")
-	s.logger.Info(fmt.Sprintf("Estimated total records: %d", s.config.TotalRecordsEstimate()))
+	return s.seed(ctx)
+}
+
+// Resume continues the last run of SeedAll from where seed_progress says it
+// stopped: completed tables and phases are only generated again, for the
+// IDs and ledger the rest need, and the sales and purchase orders continue
+// after the last batch fully written. Generation is deterministic, so the
+// rows written match those of an uninterrupted run. The seeder's config
+// must be the one the run started with, but for its seed and reference
+// time, which are those of the run, and how it loads.
+func (s *Seeder) Resume(ctx context.Context) error {
+	if err := s.config.Validate(); err != nil {
+		return fmt.Errorf("invalid seeding config: %w", err)
+	}
+
+	r, err := s.checkpoints.load(ctx)
+	if err != nil {
+		return err
+	}
+	if fingerprint(s.config) != r.fingerprint {
+		return fmt.Errorf("the config differs from that of the run to resume (seed %d), use the same profile and tables", r.seed)
+	}
+	s.source = generators.NewSource(r.seed, r.asOf)
+	s.resuming = true
+
+	phase := s.checkpoints.resumePhase()
+	dropped := s.checkpoints.droppedIndexes()
+	if phase == 0 && len(dropped) == 0 {
+		s.logger.Info(fmt.Sprintf("Seeding run with seed %d already completed, nothing to resume", r.seed))
+		return nil
+	}
+	s.inserter.RestoreIndexes(dropped)
+
+	if phase == 0 {
+		s.logger.Info("Resuming seeding to rebuild indexes")
+	} else {
+		s.logger.Info(fmt.Sprintf("Resuming seeding at phase %d", phase))
+	}
+	return s.seed(ctx)
+}
+
+// seed runs the seeding phases, skipping what the checkpoints record as
+// already written
+func (s *Seeder) seed(ctx context.Context) error {
+	if len(s.config.Tables) > 0 {
+		s.logger.Info(fmt.Sprintf("Seeding only %s", strings.Join(s.tables(), ", ")))
+	}
+	s.logger.Info(fmt.Sprintf("Estimated total records: %d (%s)", s.config.TotalRecordsEstimate(), FormatBytes(s.config.DiskSizeEstimate())))
+	s.logger.Info(fmt.Sprintf("Random seed %d, as of %s", s.source.Seed(), s.source.Now.Format(time.RFC3339)))
+
+	if err := s.inserter.Stage(ctx, s.tables()); err != nil {
+		return fmt.Errorf("failed to stage tables: %w", err)
+	}
+	if err := s.checkpoints.saveIndexes(ctx, s.inserter.DroppedIndexes()); err != nil {
+		return err
+	}
 
 	// Phase 1: Master Data
 	s.logger.Info("=== Phase 1: Master Data ===")
@@ -40,36 +280,67 @@ func (s *Seeder) SeedAll(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to seed master data: %w", err)
 	}
+	if err := s.checkpoints.completePhase(ctx, phaseMasterData); err != nil {
+		return err
+	}
 
 	// Phase 2: Products and Relationships
 	s.logger.Info("=== Phase 2: Products and Relationships ===")
 	if err := s.seedProducts(ctx, idMap); err != nil {
 		return fmt.Errorf("failed to seed products: %w", err)
 	}
+	if err := s.checkpoints.completePhase(ctx, phaseProducts); err != nil {
+		return err
+	}
 
 	// Phase 3: Customers
 	s.logger.Info("=== Phase 3: Customers ===")
 	if err := s.seedCustomers(ctx, idMap); err != nil {
 		return fmt.Errorf("failed to seed customers: %w", err)
 	}
+	if err := s.checkpoints.completePhase(ctx, phaseCustomers); err != nil {
+		return err
+	}
+
+	idMap.Distributions = generators.NewDistributions(s.source, idMap, s.config.ProductZipf, s.config.CustomerZipf, s.config.OrderSeasonality, s.config.SalesOrderItemsTail)
+	if s.config.InventoryFromLedger {
+		idMap.Ledger = generators.NewLedger(s.source, idMap, s.config.InventoryRecordsPerProduct)
+	}
 
 	// Phase 4: Sales Orders
 	s.logger.Info("=== Phase 4: Sales Orders ===")
 	if err := s.seedSalesOrders(ctx, idMap); err != nil {
 		return fmt.Errorf("failed to seed sales orders: %w", err)
 	}
+	if err := s.checkpoints.completePhase(ctx, phaseSalesOrders); err != nil {
+		return err
+	}
 
 	// Phase 5: Purchase Orders
 	s.logger.Info("=== Phase 5: Purchase Orders ===")
 	if err := s.seedPurchaseOrders(ctx, idMap); err != nil {
 		return fmt.Errorf("failed to seed purchase orders: %w", err)
 	}
+	if err := s.checkpoints.completePhase(ctx, phasePurchaseOrders); err != nil {
+		return err
+	}
 
 	// Phase 6: Inventory
 	s.logger.Info("=== Phase 6: Inventory ===")
 	if err := s.seedInventory(ctx, idMap); err != nil {
 		return fmt.Errorf("failed to seed inventory: %w", err)
 	}
+	if err := s.checkpoints.completePhase(ctx, phaseInventory); err != nil {
+		return err
+	}
+
+	// Each rebuilt index is recorded as it completes, so a resumed run
+	// rebuilds only the rest
+	s.logger.Info("=== Finalizing: indexes and statistics ===")
+	if err := s.inserter.Finalize(ctx, s.tables(), s.checkpoints.saveIndexes); err != nil {
+		return fmt.Errorf("failed to finalize tables: %w", err)
+	}
+	s.reportStats()
 
 	elapsed := s.progress.Elapsed()
 	s.logger.Info(fmt.Sprintf("Seeding completed in %s", FormatDuration(elapsed)))
@@ -78,227 +349,435 @@ func (s *Seeder) SeedAll(ctx context.Context) error {
 }
 
 func (s *Seeder) seedMasterData(ctx context.Context) (*generators.IDMap, error) {
-	// Categories
-	s.logger.Info(fmt.Sprintf("Generating %d categories...", s.config.Categories))
-	categoryRows, idMap := generators.GenerateCategories(s.config.Categories)
-	s.logger.Info(fmt.Sprintf("Generated %d categories, inserting...", len(categoryRows)))
-	s.progress.StartTable("Categories", s.config.Categories)
-	if err := s.inserter.BulkInsertBatched(ctx, "categories", generators.CategoryColumns(), categoryRows, s.config.BatchSize); err != nil {
+	c := s.config
+
+	categoryRows, idMap := generators.GenerateCategories(s.source, c.Categories)
+	if _, err := s.copyTable(ctx, "categories", generators.CategoryColumns(), categoryRows, c.Categories); err != nil {
 		return nil, err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d categories", len(categoryRows)))
-
-	// Suppliers
-	s.logger.Info(fmt.Sprintf("Generating %d suppliers...", s.config.Suppliers))
-	supplierRows := generators.GenerateSuppliers(s.config.Suppliers, idMap)
-	s.logger.Info(fmt.Sprintf("Generated %d suppliers, inserting...", len(supplierRows)))
-	s.progress.StartTable("Suppliers", s.config.Suppliers)
-	if err := s.inserter.BulkInsertBatched(ctx, "suppliers", generators.SupplierColumns(), supplierRows, s.config.BatchSize); err != nil {
+
+	supplierRows := generators.GenerateSuppliers(s.source, c.Suppliers, idMap)
+	if _, err := s.copyTable(ctx, "suppliers", generators.SupplierColumns(), supplierRows, c.Suppliers); err != nil {
 		return nil, err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d suppliers", len(supplierRows)))
-
-	// Warehouses
-	s.logger.Info(fmt.Sprintf("Generating %d warehouses...", s.config.Warehouses))
-	warehouseRows := generators.GenerateWarehouses(s.config.Warehouses, idMap)
-	s.logger.Info(fmt.Sprintf("Generated %d warehouses, inserting...", len(warehouseRows)))
-	s.progress.StartTable("Warehouses", s.config.Warehouses)
-	if err := s.inserter.BulkInsertBatched(ctx, "warehouses", generators.WarehouseColumns(), warehouseRows, s.config.BatchSize); err != nil {
+
+	warehouseRows := generators.GenerateWarehouses(s.source, c.Warehouses, idMap)
+	if _, err := s.copyTable(ctx, "warehouses", generators.WarehouseColumns(), warehouseRows, c.Warehouses); err != nil {
 		return nil, err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d warehouses", len(warehouseRows)))
 
 	return idMap, nil
 }
 
 func (s *Seeder) seedProducts(ctx context.Context, idMap *generators.IDMap) error {
-	// Products
-	s.logger.Info(fmt.Sprintf("Generating %d products...", s.config.Products))
-	productRows := generators.GenerateProducts(s.config.Products, idMap)
-	s.logger.Info(fmt.Sprintf("Generated %d products, inserting...", len(productRows)))
-	s.progress.StartTable("Products", len(productRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "products", generators.ProductColumns(), productRows, s.config.BatchSize); err != nil {
+	c := s.config
+
+	productRows := generators.GenerateProducts(s.source, c.Products, idMap)
+	if _, err := s.copyTable(ctx, "products", generators.ProductColumns(), productRows, c.Products); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d products", len(productRows)))
-
-	// Product Categories
-	s.logger.Info("Generating product-category relationships...")
-	pcRows := generators.GenerateProductCategories(idMap, s.config.ProductCategoriesPerProduct)
-	s.logger.Info(fmt.Sprintf("Generated %d product-category relationships, inserting...", len(pcRows)))
-	s.progress.StartTable("Product Categories", len(pcRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "product_categories", generators.ProductCategoryColumns(), pcRows, s.config.BatchSize); err != nil {
+
+	pcRows := generators.GenerateProductCategories(s.source, idMap, c.ProductCategoriesPerProduct)
+	if _, err := s.copyTable(ctx, "product_categories", generators.ProductCategoryColumns(), pcRows, c.Products*c.ProductCategoriesPerProduct); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d product-category relationships", len(pcRows)))
-
-	// Product Prices
-	s.logger.Info("Generating product prices...")
-	priceRows := generators.GenerateProductPrices(idMap, s.config.ProductPricesPerProduct)
-	s.logger.Info(fmt.Sprintf("Generated %d product prices, inserting...", len(priceRows)))
-	s.progress.StartTable("Product Prices", len(priceRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "product_prices", generators.ProductPriceColumns(), priceRows, s.config.BatchSize); err != nil {
+
+	priceRows := generators.GenerateProductPrices(s.source, idMap, c.ProductPricesPerProduct)
+	if _, err := s.copyTable(ctx, "product_prices", generators.ProductPriceColumns(), priceRows, c.Products*c.ProductPricesPerProduct); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d product prices", len(priceRows)))
-
-	// Product Costs
-	s.logger.Info("Generating product costs...")
-	costRows := generators.GenerateProductCosts(idMap, s.config.ProductCostsPerProduct)
-	s.logger.Info(fmt.Sprintf("Generated %d product costs, inserting...", len(costRows)))
-	s.progress.StartTable("Product Costs", len(costRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "product_costs", generators.ProductCostColumns(), costRows, s.config.BatchSize); err != nil {
+
+	costRows := generators.GenerateProductCosts(s.source, idMap, c.ProductCostsPerProduct)
+	if _, err := s.copyTable(ctx, "product_costs", generators.ProductCostColumns(), costRows, c.Products*c.ProductCostsPerProduct); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d product costs", len(costRows)))
-
-	// Supplier Products
-	s.logger.Info("Generating supplier-product relationships...")
-	spRows := generators.GenerateSupplierProducts(idMap, s.config.SupplierProductsPerProduct)
-	s.logger.Info(fmt.Sprintf("Generated %d supplier-product relationships, inserting...", len(spRows)))
-	s.progress.StartTable("Supplier Products", len(spRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "supplier_products", generators.SupplierProductColumns(), spRows, s.config.BatchSize); err != nil {
+
+	spRows := generators.GenerateSupplierProducts(s.source, idMap, c.SupplierProductsPerProduct)
+	if _, err := s.copyTable(ctx, "supplier_products", generators.SupplierProductColumns(), spRows, c.Products*c.SupplierProductsPerProduct); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d supplier-product relationships", len(spRows)))
 
 	return nil
 }
 
 func (s *Seeder) seedCustomers(ctx context.Context, idMap *generators.IDMap) error {
-	// Customers
-	s.logger.Info(fmt.Sprintf("Generating %d customers...", s.config.Customers))
-	customerRows := generators.GenerateCustomers(s.config.Customers, idMap)
-	s.logger.Info(fmt.Sprintf("Generated %d customers, inserting...", len(customerRows)))
-	s.progress.StartTable("Customers", len(customerRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "customers", generators.CustomerColumns(), customerRows, s.config.BatchSize); err != nil {
+	c := s.config
+
+	customerRows := generators.GenerateCustomers(s.source, c.Customers, idMap)
+	if _, err := s.copyTable(ctx, "customers", generators.CustomerColumns(), customerRows, c.Customers); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d customers", len(customerRows)))
-
-	// Customer Addresses
-	s.logger.Info("Generating customer addresses...")
-	addressRows := generators.GenerateCustomerAddresses(idMap, s.config.CustomerAddressesPerCustomer)
-	s.logger.Info(fmt.Sprintf("Generated %d customer addresses, inserting...", len(addressRows)))
-	s.progress.StartTable("Customer Addresses", len(addressRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "customer_addresses", generators.CustomerAddressColumns(), addressRows, s.config.BatchSize); err != nil {
+
+	addressRows := generators.GenerateCustomerAddresses(s.source, idMap, c.CustomerAddressesPerCustomer)
+	if _, err := s.copyTable(ctx, "customer_addresses", generators.CustomerAddressColumns(), addressRows, c.Customers*c.CustomerAddressesPerCustomer); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d customer addresses", len(addressRows)))
 
 	return nil
 }
 
 func (s *Seeder) seedSalesOrders(ctx context.Context, idMap *generators.IDMap) error {
-	// Sales Orders
-	s.logger.Info(fmt.Sprintf("Generating %d sales orders...", s.config.SalesOrders))
-	orderRows, salesData := generators.GenerateSalesOrders(s.config.SalesOrders, idMap)
-	s.logger.Info(fmt.Sprintf("Generated %d sales orders, inserting...", len(orderRows)))
-	s.progress.StartTable("Sales Orders", len(orderRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "sales_orders", generators.SalesOrderColumns(), orderRows, s.config.BatchSize); err != nil {
-		return err
+	c := s.config
+	done, write, replay := s.orderPhase(phaseSalesOrders, c.SalesOrders,
+		"sales_orders", "sales_order_items", "sales_order_payments")
+	if !write && !replay {
+		return nil
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d sales orders", len(orderRows)))
-
-	// Sales Order Items
-	s.logger.Info("Generating sales order items...")
-	itemRows := generators.GenerateSalesOrderItems(salesData, idMap, s.config.SalesOrderItemsPerOrder)
-	s.logger.Info(fmt.Sprintf("Generated %d sales order items, inserting...", len(itemRows)))
-	s.progress.StartTable("Sales Order Items", len(itemRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "sales_order_items", generators.SalesOrderItemColumns(), itemRows, s.config.BatchSize); err != nil {
+
+	if replay && done > 0 {
+		s.logger.Info(fmt.Sprintf("Generating %d sales orders for the inventory ledger...", done))
+		for range generators.GenerateSalesOrders(s.source, 0, done, s.ordersPerBatch(c.SalesOrderItemsPerOrder), idMap,
+			c.SalesOrderItemsPerOrder, c.SalesOrderPaymentsPerOrder) {
+		}
+	}
+	if !write {
+		return nil
+	}
+	if err := s.clearOrders(ctx, "sales_orders", "order_number", salesOrderNumber(done),
+		"sales_order", "sales_order_items", "sales_order_payments"); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d sales order items", len(itemRows)))
-
-	// Sales Order Payments
-	s.logger.Info("Generating sales order payments...")
-	paymentRows := generators.GenerateSalesOrderPayments(salesData, s.config.SalesOrderPaymentsPerOrder)
-	s.logger.Info(fmt.Sprintf("Generated %d sales order payments, inserting...", len(paymentRows)))
-	s.progress.StartTable("Sales Order Payments", len(paymentRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "sales_order_payments", generators.SalesOrderPaymentColumns(), paymentRows, s.config.BatchSize); err != nil {
+	return s.writeSalesOrders(ctx, idMap, done, c.SalesOrders-done)
+}
+
+// writeSalesOrders generates and writes count sales orders from the order
+// at index first, recording progress after each batch
+func (s *Seeder) writeSalesOrders(ctx context.Context, idMap *generators.IDMap, first, count int) error {
+	c := s.config
+	s.logger.Info(fmt.Sprintf("Seeding %d sales orders with items and payments...", count))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := pipeline(ctx, generators.GenerateSalesOrders(
+		s.source, first, count, s.ordersPerBatch(c.SalesOrderItemsPerOrder), idMap,
+		c.SalesOrderItemsPerOrder, c.SalesOrderPaymentsPerOrder,
+	))
+
+	s.progress.StartTable("sales_orders", count)
+	var orders, items, payments, transactions int
+	for batch := range batches {
+		if err := s.insertBatch(ctx, "sales_orders", generators.SalesOrderColumns(), batch.Orders); err != nil {
+			return err
+		}
+		if err := s.insertBatch(ctx, "sales_order_items", generators.SalesOrderItemColumns(), batch.Items); err != nil {
+			return err
+		}
+		if err := s.insertBatch(ctx, "sales_order_payments", generators.SalesOrderPaymentColumns(), batch.Payments); err != nil {
+			return err
+		}
+		if err := s.insertBatch(ctx, "inventory_transactions", generators.InventoryTransactionColumns(), batch.Transactions); err != nil {
+			return err
+		}
+
+		orders += len(batch.Orders)
+		items += len(batch.Items)
+		payments += len(batch.Payments)
+		transactions += len(batch.Transactions)
+		if err := s.checkpoints.advance(ctx, phaseSalesOrders, first+orders); err != nil {
+			return err
+		}
+		s.progress.Add(len(batch.Orders))
+	}
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d sales order payments", len(paymentRows)))
 
+	s.logger.Info(fmt.Sprintf("Seeded %d sales orders, %d sales order items, %d sales order payments", orders, items, payments))
+	if idMap.Ledger != nil {
+		s.logger.Info(fmt.Sprintf("Seeded %d inventory transactions for shipped items", transactions))
+	}
 	return nil
 }
 
 func (s *Seeder) seedPurchaseOrders(ctx context.Context, idMap *generators.IDMap) error {
-	// Purchase Orders
-	s.logger.Info(fmt.Sprintf("Generating %d purchase orders...", s.config.PurchaseOrders))
-	poRows, purchaseData := generators.GeneratePurchaseOrders(s.config.PurchaseOrders, idMap)
-	s.logger.Info(fmt.Sprintf("Generated %d purchase orders, inserting...", len(poRows)))
-	s.progress.StartTable("Purchase Orders", len(poRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "purchase_orders", generators.PurchaseOrderColumns(), poRows, s.config.BatchSize); err != nil {
-		return err
+	c := s.config
+	done, write, replay := s.orderPhase(phasePurchaseOrders, c.PurchaseOrders,
+		"purchase_orders", "purchase_order_items", "purchase_order_receipts")
+	if !write && !replay {
+		return nil
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d purchase orders", len(poRows)))
-
-	// Purchase Order Items
-	s.logger.Info("Generating purchase order items...")
-	poItemRows := generators.GeneratePurchaseOrderItems(purchaseData, idMap, s.config.PurchaseOrderItemsPerOrder)
-	s.logger.Info(fmt.Sprintf("Generated %d purchase order items, inserting...", len(poItemRows)))
-	s.progress.StartTable("Purchase Order Items", len(poItemRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "purchase_order_items", generators.PurchaseOrderItemColumns(), poItemRows, s.config.BatchSize); err != nil {
+
+	if replay && done > 0 {
+		s.logger.Info(fmt.Sprintf("Generating %d purchase orders for the inventory ledger...", done))
+		for range generators.GeneratePurchaseOrders(s.source, 0, done, s.ordersPerBatch(c.PurchaseOrderItemsPerOrder), idMap,
+			c.PurchaseOrderItemsPerOrder, c.PurchaseOrderReceiptsPerItem) {
+		}
+	}
+	if !write {
+		return nil
+	}
+	if err := s.clearOrders(ctx, "purchase_orders", "po_number", purchaseOrderNumber(done),
+		"purchase_order", "purchase_order_items", "purchase_order_receipts"); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d purchase order items", len(poItemRows)))
-
-	// Purchase Order Receipts
-	s.logger.Info("Generating purchase order receipts...")
-	receiptRows := generators.GeneratePurchaseOrderReceipts(purchaseData, s.config.PurchaseOrderReceiptsPerItem)
-	s.logger.Info(fmt.Sprintf("Generated %d purchase order receipts, inserting...", len(receiptRows)))
-	s.progress.StartTable("Purchase Order Receipts", len(receiptRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "purchase_order_receipts", generators.PurchaseOrderReceiptColumns(), receiptRows, s.config.BatchSize); err != nil {
+	return s.writePurchaseOrders(ctx, idMap, done, c.PurchaseOrders-done)
+}
+
+// writePurchaseOrders generates and writes count purchase orders from the order
+// at index first, recording progress after each batch
+func (s *Seeder) writePurchaseOrders(ctx context.Context, idMap *generators.IDMap, first, count int) error {
+	c := s.config
+	s.logger.Info(fmt.Sprintf("Seeding %d purchase orders with items and receipts...", count))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := pipeline(ctx, generators.GeneratePurchaseOrders(
+		s.source, first, count, s.ordersPerBatch(c.PurchaseOrderItemsPerOrder), idMap,
+		c.PurchaseOrderItemsPerOrder, c.PurchaseOrderReceiptsPerItem,
+	))
+
+	s.progress.StartTable("purchase_orders", count)
+	var orders, items, receipts, transactions int
+	for batch := range batches {
+		if err := s.insertBatch(ctx, "purchase_orders", generators.PurchaseOrderColumns(), batch.Orders); err != nil {
+			return err
+		}
+		if err := s.insertBatch(ctx, "purchase_order_items", generators.PurchaseOrderItemColumns(), batch.Items); err != nil {
+			return err
+		}
+		if err := s.insertBatch(ctx, "purchase_order_receipts", generators.PurchaseOrderReceiptColumns(), batch.Receipts); err != nil {
+			return err
+		}
+		if err := s.insertBatch(ctx, "inventory_transactions", generators.InventoryTransactionColumns(), batch.Transactions); err != nil {
+			return err
+		}
+
+		orders += len(batch.Orders)
+		items += len(batch.Items)
+		receipts += len(batch.Receipts)
+		transactions += len(batch.Transactions)
+		if err := s.checkpoints.advance(ctx, phasePurchaseOrders, first+orders); err != nil {
+			return err
+		}
+		s.progress.Add(len(batch.Orders))
+	}
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d purchase order receipts", len(receiptRows)))
 
+	s.logger.Info(fmt.Sprintf("Seeded %d purchase orders, %d purchase order items, %d purchase order receipts", orders, items, receipts))
+	if idMap.Ledger != nil {
+		s.logger.Info(fmt.Sprintf("Seeded %d inventory transactions for receipts", transactions))
+	}
+	return nil
+}
+
+// orderPhase returns how many of an order phase's orders need no writing,
+// as the run resumed already wrote them or none are seeded, whether the rest
+// need writing, and whether the first need generating for the inventory
+// ledger
+func (s *Seeder) orderPhase(phase, count int, tables ...string) (done int, write, replay bool) {
+	c := s.config
+	done = s.checkpoints.position(phase)
+	if s.checkpoints.phaseDone(phase) {
+		done = count
+	}
+
+	if done < count {
+		write = c.InventoryFromLedger && c.writes("inventory_transactions")
+		for _, table := range tables {
+			write = write || c.writes(table)
+		}
+	}
+	if !write {
+		// None are written, so the ledger needs them all
+		done = count
+	}
+	replay = s.feedsLater(tables[0])
+	return done, write, replay
+}
+
+// salesOrderNumber and purchaseOrderNumber return the number of the order at
+// index i, as the generators number them
+func salesOrderNumber(i int) string    { return fmt.Sprintf("SO-%010d", i+1) }
+func purchaseOrderNumber(i int) string { return fmt.Sprintf("PO-%010d", i+1) }
+
+// clearOrders deletes, when resuming, the orders of table from number on,
+// written by a batch that did not complete, with their items, payments or
+// receipts and ledger transactions
+func (s *Seeder) clearOrders(ctx context.Context, table, numberColumn, number, referenceType string, children ...string) error {
+	if !s.resuming {
+		return nil
+	}
+
+	orders := fmt.Sprintf("SELECT id FROM %s WHERE %s >= $1", table, numberColumn)
+	foreignKey := strings.TrimSuffix(table, "s") + "_id"
+	queries := []string{fmt.Sprintf(
+		"DELETE FROM inventory_transactions WHERE reference_type = '%s' AND reference_id IN (%s)", referenceType, orders)}
+	for _, child := range children {
+		queries = append(queries, fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", child, foreignKey, orders))
+	}
+	queries = append(queries, fmt.Sprintf("DELETE FROM %s WHERE %s >= $1", table, numberColumn))
+
+	for _, query := range queries {
+		if _, err := s.db.PGX.Exec(ctx, query, number); err != nil {
+			return fmt.Errorf("failed to clear partially seeded %s: %w", table, err)
+		}
+	}
 	return nil
 }
 
 func (s *Seeder) seedInventory(ctx context.Context, idMap *generators.IDMap) error {
-	// Inventory
-	s.logger.Info("Generating inventory records...")
-	invRows := generators.GenerateInventory(idMap, s.config.InventoryRecordsPerProduct)
-	s.logger.Info(fmt.Sprintf("Generated %d inventory records, inserting...", len(invRows)))
-	s.progress.StartTable("Inventory", len(invRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "inventory", generators.InventoryColumns(), invRows, s.config.BatchSize); err != nil {
+	c := s.config
+
+	invRows := generators.GenerateInventory(s.source, idMap, c.InventoryRecordsPerProduct)
+	if _, err := s.copyTable(ctx, "inventory", generators.InventoryColumns(), invRows, c.Products*c.InventoryRecordsPerProduct); err != nil {
 		return err
 	}
-	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d inventory records", len(invRows)))
-
-	// Inventory Transactions
-	s.logger.Info("Generating inventory transactions...")
-	txnRows := generators.GenerateInventoryTransactions(idMap, s.config.InventoryTransactionsPerProduct)
-	s.logger.Info(fmt.Sprintf("Generated %d inventory transactions, inserting...", len(txnRows)))
-	s.progress.StartTable("Inventory Transactions", len(txnRows))
-	if err := s.inserter.BulkInsertBatched(ctx, "inventory_transactions", generators.InventoryTransactionColumns(), txnRows, s.config.BatchSize); err != nil {
+
+	// With a ledger the orders wrote their transactions, and this adds
+	// the opening balances
+	txnRows := generators.GenerateInventoryTransactions(s.source, idMap, c.InventoryTransactionsPerProduct)
+	expected := c.Products * c.InventoryTransactionsPerProduct
+	if c.InventoryFromLedger {
+		expected = c.Products * c.InventoryRecordsPerProduct
+	}
+	if _, err := s.copyTable(ctx, "inventory_transactions", generators.InventoryTransactionColumns(), txnRows, expected); err != nil {
 		return err
 	}
+
+	return nil
+}
+
+// copyTable streams rows into table as they are generated, advancing the
+// progress bar with every batch written. expected sizes the bar and may be
+// an estimate. Tables not seeded, or already seeded by the run resumed, are
+// only generated when the tables after them need it.
+func (s *Seeder) copyTable(ctx context.Context, table string, columns []string, rows iter.Seq[[]interface{}], expected int) (int, error) {
+	if !s.config.writes(table) || s.checkpoints.tableDone(table) {
+		if s.feedsLater(table) {
+			s.logger.Info(fmt.Sprintf("Generating %s for the tables after it...", table))
+			for range rows {
+			}
+		}
+		return 0, nil
+	}
+
+	if err := s.clearTable(ctx, table); err != nil {
+		return 0, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Seeding %s...", table))
+	s.progress.StartTable(table, expected)
+
+	n, err := s.inserter.CopyRows(ctx, table, columns, rows, s.config.BatchSize, func(n int) {
+		s.progress.Add(n)
+	})
+	if err != nil {
+		return n, fmt.Errorf("failed to seed %s: %w", table, err)
+	}
+	if err := s.checkpoints.completeTable(ctx, table, n); err != nil {
+		return n, err
+	}
+
 	s.progress.Finish()
-	s.logger.Info(fmt.Sprintf("Seeded %d inventory transactions", len(txnRows)))
+	s.logger.Info(fmt.Sprintf("Seeded %d %s", n, table))
+	return n, nil
+}
+
+// clearTable empties, when resuming, a table the run did not complete. With
+// a ledger the orders already wrote their inventory transactions, and only
+// the opening balances, which reference nothing, are cleared.
+func (s *Seeder) clearTable(ctx context.Context, table string) error {
+	if !s.resuming {
+		return nil
+	}
 
+	query := "TRUNCATE " + pgx.Identifier{table}.Sanitize() + " CASCADE"
+	if table == "inventory_transactions" && s.config.InventoryFromLedger {
+		query = "DELETE FROM inventory_transactions WHERE reference_id IS NULL"
+	}
+	if _, err := s.db.PGX.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to clear partially seeded %s: %w", table, err)
+	}
 	return nil
 }
 
+// sharedTables are generated even when not seeded, as the tables after them
+// draw IDs, prices or costs from them
+var sharedTables = map[string]bool{
+	"categories": true, "suppliers": true, "warehouses": true,
+	"products": true, "product_prices": true, "product_costs": true,
+	"customers": true,
+}
+
+// feedsLater reports whether generating table records what a table still
+// to be written needs: the IDs, prices and costs of sharedTables, or with a
+// ledger, the opening balances inventory sets for inventory_transactions
+// and the orders' movements for both.
+func (s *Seeder) feedsLater(table string) bool {
+	if sharedTables[table] {
+		return true
+	}
+	if !s.config.InventoryFromLedger {
+		return false
+	}
+	switch table {
+	case "inventory":
+		return s.pending("inventory_transactions")
+	case "sales_orders", "purchase_orders":
+		return s.pending("inventory") || s.pending("inventory_transactions")
+	}
+	return false
+}
+
+// pending reports whether table is seeded and not yet written by the run
+func (s *Seeder) pending(table string) bool {
+	return s.config.writes(table) && !s.checkpoints.tableDone(table)
+}
+
+// insertBatch writes one table's rows of an order batch, if it is seeded
+func (s *Seeder) insertBatch(ctx context.Context, table string, columns []string, rows [][]interface{}) error {
+	if !s.config.writes(table) {
+		return nil
+	}
+	return s.inserter.BulkInsertBatched(ctx, table, columns, rows, s.config.BatchSize)
+}
+
+// ordersPerBatch sizes order batches so their line items, the bulk of each
+// batch, come to about one COPY batch per worker.
+func (s *Seeder) ordersPerBatch(itemsPerOrder int) int {
+	workers := max(1, s.config.CopyWorkers)
+	return max(1, s.config.BatchSize*workers/max(1, itemsPerOrder))
+}
+
+// reportStats logs rows/sec per table, to tune CopyWorkers and BatchSize
+// against the server's settings
+func (s *Seeder) reportStats() {
+	s.logger.Info(fmt.Sprintf("Load stats with %d COPY workers, batch size %d:", max(1, s.config.CopyWorkers), s.config.BatchSize))
+	for _, st := range s.inserter.Stats() {
+		s.logger.Info(fmt.Sprintf("  %-24s %12d rows  %8s  %10.0f rows/sec  index %s  analyze %s",
+			st.Table, st.Rows, FormatDuration(st.Copy), st.RowsPerSec(),
+			FormatDuration(st.Index), FormatDuration(st.Analyze)))
+	}
+}
+
+// batchDepth is how many generated order batches may wait for COPY. One is
+// being generated while they wait, so generation overlaps the writes while
+// memory stays bounded.
+const batchDepth = 2
+
+// pipeline runs gen on its own goroutine, handing its values over a channel
+// of batchDepth. The channel closes when gen is done or ctx is cancelled.
+func pipeline[T any](ctx context.Context, gen iter.Seq[T]) <-chan T {
+	ch := make(chan T, batchDepth)
+	go func() {
+		defer close(ch)
+		for v := range gen {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
 // CheckNeedsSeeding checks if seeding is needed based on customer count
 func CheckNeedsSeeding(db *database.DB, targetCount int) (bool, error) {
 	var count int
@@ -0,0 +1,129 @@
+package seeder
+
+import (
+	"bananas/internal/database"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Popularity summarizes how concentrated references to a table are, such as
+// sales order items per product
+type Popularity struct {
+	Rows       int64   // rows in the referenced table
+	Referenced int64   // rows referenced at least once
+	References int64   // references in total
+	Top        int64   // references to the most referenced row
+	Top1Share  float64 // share of references to the top 1% of rows
+	Top10Share float64 // share of references to the top 10% of rows
+}
+
+// Skew is the realized distribution of seeded sales orders, to check it
+// against the configured Zipf exponents, seasonality and items tail. With
+// uniform choices the top 1% of rows take about 1% of references; with a
+// Zipf exponent of 1 over 500,000 products about 60%.
+type Skew struct {
+	Products  Popularity // sales order items per product
+	Customers Popularity // sales orders per customer
+
+	ItemsMean   float64 // items per sales order
+	ItemsMedian float64
+	ItemsP99    float64
+	ItemsMax    int64
+
+	Weekday  [7]int64 // sales orders by order_date weekday, Sunday first
+	PeakDay  time.Time
+	PeakDays int64   // sales orders on the busiest day
+	DayMean  float64 // sales orders per day with any
+}
+
+// popularity ranks the rows of table by how often column references them
+const popularity = `
+	WITH counts AS (
+		SELECT count(*) AS n FROM %[1]s GROUP BY %[2]s
+	), ranked AS (
+		SELECT n, row_number() OVER (ORDER BY n DESC) AS rank FROM counts
+	), total AS (
+		SELECT count(*) AS rows FROM %[3]s
+	)
+	SELECT total.rows, count(ranked.n), coalesce(sum(ranked.n), 0), coalesce(max(ranked.n), 0),
+		coalesce(sum(ranked.n) FILTER (WHERE ranked.rank <= greatest(total.rows / 100, 1)), 0),
+		coalesce(sum(ranked.n) FILTER (WHERE ranked.rank <= greatest(total.rows / 10, 1)), 0)
+	FROM total LEFT JOIN ranked ON true
+	GROUP BY total.rows`
+
+const itemsPerOrder = `
+	SELECT coalesce(avg(n), 0), coalesce(percentile_cont(0.5) WITHIN GROUP (ORDER BY n), 0),
+		coalesce(percentile_cont(0.99) WITHIN GROUP (ORDER BY n), 0), coalesce(max(n), 0)
+	FROM (SELECT count(*) AS n FROM sales_order_items GROUP BY sales_order_id) t`
+
+const ordersByDay = `
+	SELECT order_date::date, count(*) FROM sales_orders GROUP BY 1`
+
+// MeasureSkew computes the realized distribution of the seeded sales orders
+func MeasureSkew(ctx context.Context, db *database.DB) (*Skew, error) {
+	conn, err := db.PGX.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// Days are counted in UTC, as they are generated
+	if _, err := conn.Exec(ctx, "SET TimeZone = 'UTC'"); err != nil {
+		return nil, fmt.Errorf("failed to set session: %w", err)
+	}
+
+	skew := &Skew{}
+	for _, p := range []struct {
+		popularity             *Popularity
+		table, column, counted string
+	}{
+		{&skew.Products, "sales_order_items", "product_id", "products"},
+		{&skew.Customers, "sales_orders", "customer_id", "customers"},
+	} {
+		var top1, top10 int64
+		query := fmt.Sprintf(popularity, p.table, p.column, p.counted)
+		if err := conn.QueryRow(ctx, query).Scan(&p.popularity.Rows, &p.popularity.Referenced,
+			&p.popularity.References, &p.popularity.Top, &top1, &top10); err != nil {
+			return nil, fmt.Errorf("failed to measure %s popularity: %w", p.counted, err)
+		}
+		if p.popularity.References > 0 {
+			p.popularity.Top1Share = float64(top1) / float64(p.popularity.References)
+			p.popularity.Top10Share = float64(top10) / float64(p.popularity.References)
+		}
+	}
+
+	if err := conn.QueryRow(ctx, itemsPerOrder).Scan(&skew.ItemsMean, &skew.ItemsMedian,
+		&skew.ItemsP99, &skew.ItemsMax); err != nil {
+		return nil, fmt.Errorf("failed to measure items per order: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, ordersByDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure orders by day: %w", err)
+	}
+	defer rows.Close()
+
+	var orders, days int64
+	for rows.Next() {
+		var day time.Time
+		var n int64
+		if err := rows.Scan(&day, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan orders by day: %w", err)
+		}
+		skew.Weekday[day.Weekday()] += n
+		if n > skew.PeakDays {
+			skew.PeakDay, skew.PeakDays = day, n
+		}
+		orders += n
+		days++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to measure orders by day: %w", err)
+	}
+	if days > 0 {
+		skew.DayMean = float64(orders) / float64(days)
+	}
+
+	return skew, nil
+}
@@ -0,0 +1,525 @@
+package seeder
+
+import (
+	"bananas/internal/database"
+	"bananas/internal/logger"
+	"bananas/internal/seeder/inserters"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/sync/errgroup"
+)
+
+// ManifestFile is the name of a snapshot's manifest in its directory. It is
+// written last, so a directory with one holds a complete snapshot.
+const ManifestFile = "manifest.json"
+
+// manifestVersion is bumped when the snapshot layout changes
+const manifestVersion = 1
+
+// Compressions lists the compressions snapshot files can use
+var Compressions = []string{"zstd", "gzip"}
+
+// Manifest describes a snapshot: the database's tables as written by COPY
+// TO in binary format, one compressed file each, along with the seeding run
+// the data came from when seed_progress records one
+type Manifest struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	Compression string    `json:"compression"`
+
+	// Profile is the profile the data was seeded with, as given to export.
+	// Seed, AsOf and Fingerprint come from seed_progress.
+	Profile     string     `json:"profile,omitempty"`
+	Seed        uint64     `json:"seed,omitempty"`
+	AsOf        *time.Time `json:"as_of,omitempty"`
+	Fingerprint string     `json:"fingerprint,omitempty"`
+
+	Tables []SnapshotTable `json:"tables"`
+	// Checksum is the overall content checksum, as the checksum command
+	// prints it
+	Checksum string `json:"checksum"`
+}
+
+// SnapshotTable is one table of a snapshot
+type SnapshotTable struct {
+	Table   string   `json:"table"`
+	File    string   `json:"file"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows"`
+	Bytes   int64    `json:"bytes"`  // size of the compressed file
+	SHA256  string   `json:"sha256"` // of the compressed file
+	// Checksum is the table's content checksum, as the checksum command
+	// prints it
+	Checksum string `json:"checksum"`
+}
+
+// SnapshotOptions tunes ExportSnapshot and ImportSnapshot
+type SnapshotOptions struct {
+	// Compression is the compression export writes files with, one of
+	// Compressions
+	Compression string
+	// Profile is recorded in the manifest on export
+	Profile string
+	// Workers is how many tables are copied at once
+	Workers int
+}
+
+const tableColumns = `
+	SELECT attname FROM pg_attribute
+	WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped
+	ORDER BY attnum`
+
+// ExportSnapshot writes every seeded table, and seed_progress if there is
+// one, to dir with COPY TO, Workers tables at a time. The tables are read
+// in one transaction snapshot, so the files are consistent with each other
+// even while the database is written to.
+func ExportSnapshot(ctx context.Context, db *database.DB, dir string, opts SnapshotOptions) (*Manifest, error) {
+	if !slices.Contains(Compressions, opts.Compression) {
+		return nil, fmt.Errorf("unknown compression %q, use one of %v", opts.Compression, Compressions)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	log := logger.New("snapshot")
+
+	manifest := &Manifest{
+		Version:     manifestVersion,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		Compression: opts.Compression,
+		Profile:     opts.Profile,
+	}
+
+	// The seeding run, if recorded, identifies the data. Exporting only
+	// reads, so a missing seed_progress is not created.
+	cp := newCheckpoints(db.PGX)
+	r, err := cp.lookup(ctx)
+	recorded := err == nil
+	switch {
+	case recorded:
+		manifest.Seed, manifest.AsOf, manifest.Fingerprint = r.seed, &r.asOf, r.fingerprint
+		if cp.resumePhase() != 0 {
+			log.Info("The seeding run recorded in seed_progress did not complete")
+		}
+		if opts.Profile != "" {
+			if c, err := LoadProfile(opts.Profile); err == nil && fingerprint(c) != r.fingerprint {
+				log.Info(fmt.Sprintf("Profile %s is not the one recorded in seed_progress, recording it anyway", opts.Profile))
+			}
+		}
+	case !errors.Is(err, errNoRun):
+		return nil, err
+	}
+
+	tables := slices.Clone(Tables)
+	if recorded {
+		tables = append(tables, "seed_progress")
+	}
+
+	// Hold a transaction open for its snapshot, which every table is read in
+	lead, err := db.PGX.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer lead.Release()
+	tx, err := lead.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	var snapshot string
+	if err := tx.QueryRow(ctx, "SELECT pg_export_snapshot()").Scan(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to export snapshot: %w", err)
+	}
+
+	manifest.Tables = make([]SnapshotTable, len(tables))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(1, opts.Workers))
+	for i, table := range tables {
+		g.Go(func() error {
+			start := time.Now()
+			t, err := exportTable(gctx, db.PGX, snapshot, dir, opts.Compression, table)
+			if err != nil {
+				return fmt.Errorf("failed to export %s: %w", table, err)
+			}
+			manifest.Tables[i] = t
+			log.Info(fmt.Sprintf("Exported %d %s to %s (%s) in %s", t.Rows, table, t.File, FormatBytes(t.Bytes), FormatDuration(time.Since(start))))
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	overall := sha256.New()
+	for _, t := range manifest.Tables {
+		if slices.Contains(Tables, t.Table) {
+			fmt.Fprintf(overall, "%s:%s\n", t.Table, t.Checksum)
+		}
+	}
+	manifest.Checksum = hex.EncodeToString(overall.Sum(nil))
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportTable copies one table out to its file, in a transaction on the
+// exported snapshot, and checksums its content there too
+func exportTable(ctx context.Context, pool *pgxpool.Pool, snapshot, dir, compression, table string) (SnapshotTable, error) {
+	t := SnapshotTable{Table: table, File: table + ".bin." + fileExtension(compression)}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return t, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return t, err
+	}
+	defer tx.Rollback(ctx)
+	// As for Checksum, the text form hashed is pinned to match across
+	// servers
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET TRANSACTION SNAPSHOT '%s'; SET LOCAL TimeZone = 'UTC'; SET LOCAL extra_float_digits = 1", snapshot)); err != nil {
+		return t, fmt.Errorf("failed to set snapshot: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, tableColumns, table)
+	if err != nil {
+		return t, err
+	}
+	if t.Columns, err = pgx.CollectRows(rows, pgx.RowTo[string]); err != nil {
+		return t, err
+	}
+	if err := tx.QueryRow(ctx, fmt.Sprintf(tableChecksum, pgx.Identifier{table}.Sanitize())).Scan(&t.Rows, &t.Checksum); err != nil {
+		return t, fmt.Errorf("failed to checksum: %w", err)
+	}
+
+	f, err := os.Create(filepath.Join(dir, t.File))
+	if err != nil {
+		return t, err
+	}
+	defer f.Close()
+
+	sum := sha256.New()
+	counted := &countingWriter{w: io.MultiWriter(f, sum)}
+	w, err := compressor(counted, compression)
+	if err != nil {
+		return t, err
+	}
+
+	query := fmt.Sprintf("COPY %s (%s) TO STDOUT (FORMAT binary)", pgx.Identifier{table}.Sanitize(), identifierList(t.Columns))
+	tag, err := tx.Conn().PgConn().CopyTo(ctx, w, query)
+	if err != nil {
+		return t, fmt.Errorf("copy failed: %w", err)
+	}
+	if tag.RowsAffected() != t.Rows {
+		return t, fmt.Errorf("copied %d rows, counted %d", tag.RowsAffected(), t.Rows)
+	}
+	if err := w.Close(); err != nil {
+		return t, err
+	}
+	if err := f.Close(); err != nil {
+		return t, err
+	}
+
+	t.Bytes, t.SHA256 = counted.n, hex.EncodeToString(sum.Sum(nil))
+	return t, tx.Commit(ctx)
+}
+
+// ReadManifest reads the manifest of the snapshot in dir
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot manifest: %w", err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("snapshot version %d is not supported, expected %d", manifest.Version, manifestVersion)
+	}
+	if !slices.Contains(Compressions, manifest.Compression) {
+		return nil, fmt.Errorf("snapshot compression %q is not supported", manifest.Compression)
+	}
+	for _, t := range manifest.Tables {
+		if !slices.Contains(Tables, t.Table) && t.Table != "seed_progress" {
+			return nil, fmt.Errorf("snapshot table %q is not a seeded table", t.Table)
+		}
+		if !filepath.IsLocal(t.File) {
+			return nil, fmt.Errorf("snapshot file %q of %s is outside the snapshot", t.File, t.Table)
+		}
+	}
+	return manifest, nil
+}
+
+// ImportSnapshot replaces the content of the snapshot's tables with the
+// snapshot in dir. Every file is checked against its SHA-256 first. The
+// tables are then emptied, their secondary indexes dropped, and loaded with
+// COPY FROM, Workers tables at a time, each once the tables it references
+// are in. Indexes are then rebuilt, Workers at a time, and the tables
+// analyzed. It returns what loading took per table.
+func ImportSnapshot(ctx context.Context, db *database.DB, dir string, opts SnapshotOptions) (*Manifest, []inserters.TableStats, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	log := logger.New("snapshot")
+	workers := max(1, opts.Workers)
+
+	log.Info(fmt.Sprintf("Verifying %d snapshot files...", len(manifest.Tables)))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	for _, t := range manifest.Tables {
+		g.Go(func() error {
+			return verifyFile(gctx, filepath.Join(dir, t.File), t.SHA256)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	tables := make([]string, len(manifest.Tables))
+	for i, t := range manifest.Tables {
+		tables[i] = t.Table
+	}
+	// seed_progress is emptied too, as what it records no longer holds,
+	// then loaded if the snapshot has the run it came from
+	if _, err := db.PGX.Exec(ctx, createSeedProgress); err != nil {
+		return nil, nil, fmt.Errorf("failed to create seed_progress: %w", err)
+	}
+	truncated := tables
+	if !slices.Contains(tables, "seed_progress") {
+		truncated = append(slices.Clone(tables), "seed_progress")
+	}
+	if _, err := db.PGX.Exec(ctx, "TRUNCATE "+identifierList(truncated)+" CASCADE"); err != nil {
+		return nil, nil, fmt.Errorf("failed to truncate tables: %w", err)
+	}
+
+	parents, err := referencedTables(ctx, db.PGX, tables)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inserter := inserters.NewPGXCopyInserter(db.PGX, inserters.Options{
+		Workers:     workers,
+		DropIndexes: true,
+		Analyze:     true,
+	})
+	if err := inserter.Stage(ctx, tables); err != nil {
+		return nil, nil, fmt.Errorf("failed to stage tables: %w", err)
+	}
+
+	// Each table waits for the tables it references, then for a worker
+	loaded := make(map[string]chan struct{}, len(tables))
+	for _, table := range tables {
+		loaded[table] = make(chan struct{})
+	}
+	slots := make(chan struct{}, workers)
+	g, gctx = errgroup.WithContext(ctx)
+	for _, t := range manifest.Tables {
+		g.Go(func() error {
+			for _, parent := range parents[t.Table] {
+				select {
+				case <-loaded[parent]:
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			defer func() { <-slots }()
+
+			if err := importTable(gctx, inserter, dir, manifest.Compression, t); err != nil {
+				return fmt.Errorf("failed to import %s: %w", t.Table, err)
+			}
+			log.Info(fmt.Sprintf("Imported %d %s", t.Rows, t.Table))
+			close(loaded[t.Table])
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	log.Info("Rebuilding indexes and analyzing...")
+	if err := inserter.Finalize(ctx, tables, nil); err != nil {
+		return nil, nil, fmt.Errorf("failed to finalize tables: %w", err)
+	}
+	return manifest, inserter.Stats(), nil
+}
+
+func importTable(ctx context.Context, inserter *inserters.PGXCopyInserter, dir, compression string, t SnapshotTable) error {
+	f, err := os.Open(filepath.Join(dir, t.File))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := decompressor(f, compression)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	n, err := inserter.CopyReader(ctx, t.Table, t.Columns, "binary", r)
+	if err != nil {
+		return err
+	}
+	if n != t.Rows {
+		return fmt.Errorf("loaded %d rows, the manifest lists %d", n, t.Rows)
+	}
+	return nil
+}
+
+// referencedTables returns, per table, the other tables among tables its
+// foreign keys reference
+func referencedTables(ctx context.Context, pool *pgxpool.Pool, tables []string) (map[string][]string, error) {
+	keys, err := foreignKeys(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make(map[string][]string)
+	for child, referenced := range keys {
+		if !slices.Contains(tables, child) {
+			continue
+		}
+		for _, parent := range referenced {
+			if slices.Contains(tables, parent) {
+				parents[child] = append(parents[child], parent)
+			}
+		}
+	}
+	return parents, nil
+}
+
+// foreignKeys returns, per table, the other tables its foreign keys
+// reference
+func foreignKeys(ctx context.Context, pool *pgxpool.Pool) (map[string][]string, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT DISTINCT c.conrelid::regclass::text, c.confrelid::regclass::text
+		FROM pg_constraint c
+		WHERE c.contype = 'f' AND c.conrelid <> c.confrelid`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	parents := make(map[string][]string)
+	for rows.Next() {
+		var child, parent string
+		if err := rows.Scan(&child, &parent); err != nil {
+			return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+		}
+		parents[child] = append(parents[child], parent)
+	}
+	return parents, rows.Err()
+}
+
+// verifyFile checks the SHA-256 of the file at path
+func verifyFile(ctx context.Context, path, want string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sum := sha256.New()
+	if _, err := io.Copy(sum, &contextReader{ctx: ctx, r: f}); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if got := hex.EncodeToString(sum.Sum(nil)); got != want {
+		return fmt.Errorf("%s is corrupt: SHA-256 %s, the manifest lists %s", path, got, want)
+	}
+	return nil
+}
+
+func fileExtension(compression string) string {
+	if compression == "gzip" {
+		return "gz"
+	}
+	return "zst"
+}
+
+func compressor(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case "zstd":
+		return zstd.NewWriter(w)
+	case "gzip":
+		return gzip.NewWriter(w), nil
+	}
+	return nil, fmt.Errorf("unknown compression %q", compression)
+}
+
+func decompressor(r io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case "zstd":
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	case "gzip":
+		return gzip.NewReader(r)
+	}
+	return nil, fmt.Errorf("unknown compression %q", compression)
+}
+
+// identifierList joins names as quoted identifiers
+func identifierList(names []string) string {
+	identifiers := make([]string, len(names))
+	for i, name := range names {
+		identifiers[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return strings.Join(identifiers, ", ")
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// contextReader stops reading once ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -6,6 +6,8 @@ import (
 	"bananas/internal/repositories"
 	"context"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Service struct {
@@ -90,10 +92,76 @@ func (s *Service) GetFrameworks(ctx context.Context, ormType string, frameworkTy
 	return repo.GetFrameworks(ctx, frameworkType)
 }
 
-func (s *Service) GetRecentOrders(ctx context.Context, ormType string, limit int) ([]*models.OrderWithDetails, int64, error) {
+func (s *Service) GetRecentOrders(ctx context.Context, ormType string, limit int) ([]*models.OrderWithDetails, time.Duration, error) {
 	start := time.Now()
 	repo := s.RepoManager.GetRepository(ormType)
 	orders, err := repo.GetRecentOrders(ctx, limit)
-	dbTime := time.Since(start).Milliseconds()
+	dbTime := time.Since(start)
+	return orders, dbTime, err
+}
+func (s *Service) ListOrders(ctx context.Context, ormType, status string, limit, offset int) ([]*models.SalesOrder, time.Duration, error) {
+	start := time.Now()
+	repo := s.RepoManager.GetRepository(ormType)
+	orders, err := repo.ListOrders(ctx, status, limit, offset)
+	dbTime := time.Since(start)
 	return orders, dbTime, err
-}
\ No newline at end of file
+}
+
+// StreamOrders hands orders to fn as they are read from the database cursor.
+func (s *Service) StreamOrders(ctx context.Context, ormType, status string, limit int, fn func(*models.SalesOrder) error) error {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.StreamOrders(ctx, status, limit, fn)
+}
+
+// The batch loads below back the GraphQL dataloaders, one query per batch of keys.
+
+func (s *Service) GetCustomersByIDs(ctx context.Context, ormType string, ids []uuid.UUID) ([]*models.Customer, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetCustomersByIDs(ctx, ids)
+}
+
+func (s *Service) GetOrderItemsByOrderIDs(ctx context.Context, ormType string, orderIDs []uuid.UUID) ([]*models.SalesOrderItem, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetOrderItemsByOrderIDs(ctx, orderIDs)
+}
+
+func (s *Service) GetProductsByIDs(ctx context.Context, ormType string, ids []uuid.UUID) ([]*models.Product, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetProductsByIDs(ctx, ids)
+}
+
+func (s *Service) GetProductCategoriesByProductIDs(ctx context.Context, ormType string, productIDs []uuid.UUID) ([]*models.ProductCategory, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetProductCategoriesByProductIDs(ctx, productIDs)
+}
+
+func (s *Service) GetCategoriesByIDs(ctx context.Context, ormType string, ids []uuid.UUID) ([]*models.Category, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetCategoriesByIDs(ctx, ids)
+}
+
+func (s *Service) GetInventoryByProductIDs(ctx context.Context, ormType string, productIDs []uuid.UUID) ([]*models.Inventory, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetInventoryByProductIDs(ctx, productIDs)
+}
+
+func (s *Service) GetSupplierProductsByProductIDs(ctx context.Context, ormType string, productIDs []uuid.UUID) ([]*models.SupplierProduct, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetSupplierProductsByProductIDs(ctx, productIDs)
+}
+
+func (s *Service) GetSuppliersByIDs(ctx context.Context, ormType string, ids []uuid.UUID) ([]*models.Supplier, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetSuppliersByIDs(ctx, ids)
+}
+
+// CreateUser stores a registered user, whose password is already hashed.
+func (s *Service) CreateUser(ctx context.Context, ormType string, user *models.User) error {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.CreateUser(ctx, user)
+}
+
+func (s *Service) GetUserByEmail(ctx context.Context, ormType, email string) (*models.User, error) {
+	repo := s.RepoManager.GetRepository(ormType)
+	return repo.GetUserByEmail(ctx, email)
+}
@@ -8,6 +8,8 @@ package templates
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import "strconv"
+
 type Framework struct {
 	Name  string
 	Value string
@@ -24,7 +26,7 @@ type Endpoint struct {
 	Path string
 }
 
-func Home() templ.Component {
+func Home(frameworks []Framework) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -57,7 +59,56 @@ func Home() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<header><h1>🍌 Bananas Framework Tester</h1><p>Templ + HTMX Client</p></header><div class=\"controls\"><div class=\"control-group\"><label for=\"framework\">Framework</label> <select id=\"framework\" name=\"framework\"><option value=\"8081\">Standard Library (:8081)</option> <option value=\"8082\">Gin (:8082)</option> <option value=\"8083\">Fiber (:8083)</option> <option value=\"8084\">Echo (:8084)</option> <option value=\"8085\">Chi (:8085)</option> <option value=\"8086\">Gorilla Mux (:8086)</option></select></div><div class=\"control-group\"><label for=\"orm\">ORM</label> <select id=\"orm\" name=\"orm\"><option value=\"sql\">database/sql</option> <option value=\"gorm\">GORM</option> <option value=\"sqlx\">SQLx</option> <option value=\"pgx\">PGX</option></select></div><div class=\"control-group\"><label for=\"endpoint\">Endpoint</label> <select id=\"endpoint\" name=\"endpoint\"><option value=\"/health\">Health Check</option> <option value=\"/api/test/simple\">Simple Test</option> <option value=\"/api/test/database?limit=10\">Database Test</option> <option value=\"/api/test/json\">JSON Test</option> <option value=\"/api/info\">Framework Info</option></select></div><button class=\"test-button\" hx-get=\"/templ/run-test\" hx-include=\"[name='framework'], [name='orm'], [name='endpoint']\" hx-target=\"#results\" hx-swap=\"innerHTML\"><span class=\"htmx-indicator\">Testing...</span> <span>Run Test</span></button></div><div id=\"results\"></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<header><h1>🍌 Bananas Framework Tester</h1><p>Templ + HTMX Client</p></header><div class=\"controls\"><div class=\"control-group\"><label for=\"framework\">Framework</label> <select id=\"framework\" name=\"framework\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, fw := range frameworks {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<option value=\"")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var3 string
+				templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(fw.Value)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templates/home.templ`, Line: 32, Col: 30}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var4 string
+				templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(fw.Name)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templates/home.templ`, Line: 32, Col: 42}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " (:")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var5 string
+				templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(fw.Port))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/templates/home.templ`, Line: 32, Col: 70}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, ")</option>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</select></div><div class=\"control-group\"><label for=\"orm\">ORM</label> <select id=\"orm\" name=\"orm\"><option value=\"sql\">database/sql</option> <option value=\"gorm\">GORM</option> <option value=\"sqlx\">SQLx</option> <option value=\"pgx\">PGX</option></select></div><div class=\"control-group\"><label for=\"endpoint\">Endpoint</label> <select id=\"endpoint\" name=\"endpoint\"><option value=\"/health\">Health Check</option> <option value=\"/api/test/simple\">Simple Test</option> <option value=\"/api/test/database?limit=10\">Database Test</option> <option value=\"/api/test/json\">JSON Test</option> <option value=\"/api/info\">Framework Info</option></select></div><button class=\"test-button\" hx-get=\"/templ/run-test\" hx-include=\"[name='framework'], [name='orm'], [name='endpoint']\" hx-target=\"#results\" hx-swap=\"innerHTML\"><span class=\"htmx-indicator\">Testing...</span> <span>Run Test</span></button></div><div id=\"results\"></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
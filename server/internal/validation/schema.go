@@ -0,0 +1,63 @@
+package validation
+
+// OrderRequest is the order payload POST /api/test/validate takes: a
+// customer, addresses and a list of line items, shaped like what a checkout
+// would submit. Rules use the binding tag, the name Gin's validator reads,
+// so one schema serves Gin's native binding and the shared validator alike.
+type OrderRequest struct {
+	OrderReference  string        `json:"order_reference" binding:"required,alphanum,min=6,max=32"`
+	Customer        CustomerInput `json:"customer"`
+	ShippingAddress AddressInput  `json:"shipping_address"`
+	BillingAddress  *AddressInput `json:"billing_address,omitempty" binding:"omitempty"`
+	Items           []ItemInput   `json:"items" binding:"required,min=1,max=100,dive"`
+	Currency        string        `json:"currency" binding:"required,oneof=USD EUR GBP CAD"`
+	ShippingMethod  string        `json:"shipping_method" binding:"required,oneof=standard express overnight"`
+	CouponCode      *string       `json:"coupon_code,omitempty" binding:"omitempty,alphanum,max=20"`
+	Notes           *string       `json:"notes,omitempty" binding:"omitempty,max=500"`
+}
+
+type CustomerInput struct {
+	FirstName string  `json:"first_name" binding:"required,max=255"`
+	LastName  string  `json:"last_name" binding:"required,max=255"`
+	Email     string  `json:"email" binding:"required,email,max=255"`
+	Phone     *string `json:"phone,omitempty" binding:"omitempty,e164"`
+}
+
+type AddressInput struct {
+	Line1      string  `json:"line1" binding:"required,max=255"`
+	Line2      *string `json:"line2,omitempty" binding:"omitempty,max=255"`
+	City       string  `json:"city" binding:"required,max=100"`
+	State      string  `json:"state,omitempty" binding:"omitempty,max=100"`
+	PostalCode string  `json:"postal_code" binding:"required,max=20"`
+	Country    string  `json:"country" binding:"required,iso3166_1_alpha2"`
+}
+
+// ItemInput is one order line. Discount is a fraction of the line price.
+type ItemInput struct {
+	ProductID string  `json:"product_id" binding:"required,uuid"`
+	SKU       string  `json:"sku" binding:"required,max=100"`
+	Quantity  int     `json:"quantity" binding:"required,min=1,max=1000"`
+	UnitPrice float64 `json:"unit_price" binding:"required,gt=0,lte=100000"`
+	Discount  float64 `json:"discount" binding:"gte=0,lte=1"`
+}
+
+// OrderSummary is the answer to a valid order, computed from it so the
+// payload is used rather than only checked.
+type OrderSummary struct {
+	Valid     bool    `json:"valid"`
+	Mode      string  `json:"mode"`
+	Framework string  `json:"framework"`
+	Items     int     `json:"items"`
+	Units     int     `json:"units"`
+	Subtotal  float64 `json:"subtotal"`
+}
+
+// Summarize totals a validated order.
+func Summarize(req *OrderRequest, mode, framework string) OrderSummary {
+	summary := OrderSummary{Valid: true, Mode: mode, Framework: framework, Items: len(req.Items)}
+	for _, item := range req.Items {
+		summary.Units += item.Quantity
+		summary.Subtotal += float64(item.Quantity) * item.UnitPrice * (1 - item.Discount)
+	}
+	return summary
+}
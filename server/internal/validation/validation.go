@@ -0,0 +1,122 @@
+// Package validation holds the request validation workload: the order
+// schema, the validator shared by frameworks without one of their own, and
+// the problem every framework answers a bad request with, whichever binder
+// and validator found it.
+package validation
+
+import (
+	"bananas/internal/problem"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validation modes, chosen with ?mode=. Native binds and validates with the
+// framework's own binder (Gin's ShouldBindJSON, Echo's Bind, Fiber's
+// BodyParser); adapted decodes with encoding/json in the shared controller.
+// Frameworks with no binder of their own always run adapted.
+const (
+	ModeNative  = "native"
+	ModeAdapted = "adapted"
+)
+
+// shared is the validator used everywhere except by Gin's native binding,
+// which brings its own. Validators cache struct metadata, so it is built once.
+var shared = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	UseJSONNames(v)
+	return v
+}
+
+// UseJSONNames makes v report fields by their JSON names, as clients know
+// them. Gin's validator is given it too so its errors read the same.
+func UseJSONNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+}
+
+// Validate checks v against its binding rules.
+func Validate(v any) error {
+	return shared.Struct(v)
+}
+
+// Problem maps a binding or validation error onto the problem to answer
+// with: 422 listing the broken rules, and 400 for a body that could not be
+// decoded, whose error text differs between binders and so is left out.
+func Problem(err error) problem.Problem {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return problem.New(http.StatusBadRequest, "Invalid request body")
+	}
+
+	p := problem.New(http.StatusUnprocessableEntity, "Validation failed")
+	p.Errors = make([]problem.FieldError, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		p.Errors[i] = problem.FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(fe),
+		}
+	}
+	return p
+}
+
+// fieldPath drops the struct name the validator starts namespaces with.
+func fieldPath(namespace string) string {
+	_, path, ok := strings.Cut(namespace, ".")
+	if !ok {
+		return namespace
+	}
+	return path
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a UUID"
+	case "e164":
+		return "must be a phone number in E.164 format"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "iso3166_1_alpha2":
+		return "must be a two letter country code"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+		case reflect.Slice:
+			return fmt.Sprintf("must have %s %s entries", bound, fe.Param())
+		}
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
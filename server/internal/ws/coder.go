@@ -0,0 +1,41 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/coder/websocket"
+)
+
+// NewCoderHandler upgrades requests with coder/websocket and hands the
+// connection to serve. Accept answers failed handshakes itself.
+func NewCoderHandler(serve func(Conn)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: []string{"*"}})
+		if err != nil {
+			return
+		}
+		conn.SetReadLimit(MaxMessageSize)
+		serve(coderConn{conn})
+	})
+}
+
+// coderConn adapts coder/websocket's context based API to Conn. The
+// connection outlives the request, so it is not bound to the request
+// context, whose deadline belongs to the handshake.
+type coderConn struct {
+	conn *websocket.Conn
+}
+
+func (c coderConn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.conn.Read(context.Background())
+	return int(messageType), data, err
+}
+
+func (c coderConn) WriteMessage(messageType int, data []byte) error {
+	return c.conn.Write(context.Background(), websocket.MessageType(messageType), data)
+}
+
+func (c coderConn) Close() error {
+	return c.conn.Close(websocket.StatusNormalClosure, "")
+}
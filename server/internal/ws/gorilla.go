@@ -0,0 +1,26 @@
+package ws
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// gorillaUpgrader accepts any origin, matching the CORS policy of the HTTP
+// endpoints.
+var gorillaUpgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// NewGorillaHandler upgrades requests with gorilla/websocket and hands the
+// connection to serve. The upgrader answers failed handshakes itself.
+func NewGorillaHandler(serve func(Conn)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := gorillaUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.SetReadLimit(MaxMessageSize)
+		serve(conn)
+	})
+}
@@ -0,0 +1,103 @@
+package ws
+
+import "sync"
+
+// sendBuffer is how many broadcasts a client may fall behind before it is
+// dropped, so one slow reader cannot stall the fan-out for everyone else.
+const sendBuffer = 256
+
+type message struct {
+	messageType int
+	data        []byte
+}
+
+type client struct {
+	conn Conn
+	send chan message
+}
+
+// Hub relays every message it receives to all connected clients, the sender
+// included. Each server has its own hub, so clients only see broadcasts from
+// clients of the same process.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+}
+
+// NewHub returns an empty hub.
+func NewHub() *Hub {
+	return &Hub{clients: make(map[*client]struct{})}
+}
+
+// Serve joins conn to the hub and relays its messages until it closes. It
+// returns once the connection is finished with, as some libraries reuse the
+// connection after the handler returns.
+func (h *Hub) Serve(conn Conn) {
+	c := &client{conn: conn, send: make(chan message, sendBuffer)}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.writePump()
+	}()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		h.broadcast(message{messageType: messageType, data: data})
+	}
+
+	h.remove(c)
+	<-done
+}
+
+// broadcast queues msg for every client without blocking, dropping clients
+// whose buffer is full.
+func (h *Hub) broadcast(msg message) {
+	var slow []*client
+	h.mu.RLock()
+	for c := range h.clients {
+		select {
+		case c.send <- msg:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		h.remove(c)
+	}
+}
+
+// remove closes the client's send channel, which ends its write pump. Sends
+// happen under the read lock, so none can race with the close.
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// writePump is the connection's only writer. Closing the connection when it
+// stops also unblocks the read loop of a dropped client.
+func (c *client) writePump() {
+	for msg := range c.send {
+		if err := c.conn.WriteMessage(msg.messageType, msg.data); err != nil {
+			break
+		}
+	}
+	c.conn.Close()
+
+	// After a failed write, keep draining until the read loop notices and
+	// removes the client, so broadcasts never queue behind a dead connection.
+	for range c.send {
+	}
+}
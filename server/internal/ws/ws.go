@@ -0,0 +1,38 @@
+// Package ws implements the WebSocket echo and broadcast endpoints once, over
+// a small Conn interface, so every framework runs the same message handling
+// whichever WebSocket library it upgrades with.
+package ws
+
+// Message types use the RFC 6455 opcodes, which gorilla/websocket,
+// fasthttp/websocket and coder/websocket all share.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// MaxMessageSize caps incoming messages on every library, since their
+// defaults range from 32 KiB to unlimited.
+const MaxMessageSize = 64 << 10
+
+// Conn is an upgraded connection. gorilla/websocket and fasthttp/websocket
+// connections satisfy it as they are; coder/websocket is adapted. Reads and
+// writes may run concurrently with each other but not with themselves.
+type Conn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// Echo writes every message back to the sender until the connection closes.
+func Echo(conn Conn) {
+	defer conn.Close()
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			return
+		}
+	}
+}